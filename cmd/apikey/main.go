@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/ar-agahian/ice-assignment/internal/config"
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/mysql"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+)
+
+// apikey creates an API key directly in the database, which is needed to call the
+// admin endpoints before any other credential exists
+func main() {
+	name := flag.String("name", "", "name of the API key")
+	tenant := flag.String("tenant", domain.DefaultTenantID, "tenant the API key belongs to")
+	role := flag.String("role", string(domain.RoleAdmin), "role assigned to the API key: viewer, editor or admin")
+	flag.Parse()
+	if *name == "" {
+		log.Fatal("usage: apikey -name <name> [-tenant <tenant>] [-role <role>]")
+	}
+	if !domain.IsValidTenantID(*tenant) {
+		log.Fatalf("invalid tenant %q: use 1-64 letters, digits, underscores or hyphens", *tenant)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := mysql.NewDatabase(cfg.Database)
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	if err := mysql.RunMigrations(db); err != nil {
+		log.Fatalf("failed to run migrations: %v", err)
+	}
+
+	authUseCase := usecase.NewAuthUseCase(
+		mysql.NewAPIKeyRepository(db),
+		mysql.NewRoleRepository(db),
+		mysql.NewTransactionManager(db),
+		nil,
+		"",
+		nil,
+	)
+	// The command acts as an admin of the tenant the key is created in
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{
+		ID:       "cli",
+		Type:     domain.PrincipalUser,
+		TenantID: *tenant,
+		Role:     domain.RoleAdmin,
+	})
+	result, err := authUseCase.CreateAPIKey(ctx, usecase.CreateAPIKeyRequest{Name: *name, Role: domain.Role(*role)})
+	if err != nil {
+		log.Fatalf("failed to create api key: %v", err)
+	}
+	fmt.Printf("id:   %s\nrole: %s\nkey:  %s\n", result.APIKey.ID, result.Role, result.Key)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/app"
+	"github.com/ar-agahian/ice-assignment/internal/config"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := run(cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the API until SIGINT/SIGTERM, then drains in-flight requests, stops the
+// background workers, closes every dependency and flushes pending spans
+func run(cfg *config.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Printf("failed to flush spans: %v", err)
+		}
+	}()
+
+	application, err := app.NewApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := application.Close(); err != nil {
+			log.Printf("failed to close resources: %v", err)
+		}
+	}()
+	application.Start(context.Background())
+
+	server := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           application.Handler.SetupRoutes(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	log.Printf("server listening on %s", server.Addr)
+
+	var serveErr error
+	select {
+	case serveErr = <-errCh:
+	case <-ctx.Done():
+		log.Println("shutting down server")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("server did not drain in-flight requests: %v", err)
+		server.Close()
+	}
+	// The workers get their own budget, so slow requests cannot leave the outbox unflushed
+	stopCtx, cancelStop := context.WithTimeout(context.Background(), cfg.Server.WorkerStopTimeout)
+	defer cancelStop()
+	if err := application.Stop(stopCtx); err != nil {
+		log.Printf("failed to stop background workers: %v", err)
+	}
+
+	if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ar-agahian/ice-assignment/internal/api/stream"
+	"github.com/ar-agahian/ice-assignment/internal/config"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/redis"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+)
+
+func main() {
+	cfg, err := config.LoadWorker()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := run(cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run consumes the todo event streams until SIGINT/SIGTERM or a consumer failure, then
+// waits for in-flight messages, closes the Redis connection and flushes pending spans
+func run(cfg *config.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Printf("failed to flush spans: %v", err)
+		}
+	}()
+
+	consumer, err := redis.NewStreamConsumer(ctx, cfg.Redis, consumerConfig(cfg.Worker))
+	if err != nil {
+		return fmt.Errorf("failed to create stream consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	stream.NewTodoEventHandler().RegisterHandlers(consumer)
+
+	// Cancelling runCtx stops the consumer even if Run has not got to registering itself
+	// yet, which Stop would miss
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consumer.Run(runCtx)
+	}()
+	log.Println("worker started")
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("stream consumer stopped: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	log.Println("shutting down worker")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	cancelRun()
+	// Wait for Run itself, so the Redis connection is only closed once it has returned
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("stream consumer stopped: %w", err)
+		}
+	case <-shutdownCtx.Done():
+		log.Printf("worker did not drain in-flight messages: %v", shutdownCtx.Err())
+	}
+	return nil
+}
+
+// consumerConfig builds the consumer configuration, naming the consumer after the
+// host unless configured otherwise
+func consumerConfig(cfg config.WorkerConfig) redis.ConsumerConfig {
+	consumer := cfg.Consumer
+	if consumer == "" {
+		consumer, _ = os.Hostname()
+	}
+	return redis.ConsumerConfig{
+		Group:       cfg.Group,
+		Consumer:    consumer,
+		Concurrency: cfg.Concurrency,
+	}
+}
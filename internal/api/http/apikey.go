@@ -0,0 +1,114 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles admin requests for API keys
+type APIKeyHandler struct {
+	authUseCase *usecase.AuthUseCase
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(authUseCase *usecase.AuthUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+	Role string `json:"role"`
+}
+
+// APIKeyResponse represents a stored API key
+type APIKeyResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreateAPIKeyResponse represents a newly created API key, including the key itself
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key  string `json:"key"`
+	Role string `json:"role,omitempty"`
+}
+
+// ListAPIKeysResponse represents the list of API keys
+type ListAPIKeysResponse struct {
+	Items []APIKeyResponse `json:"items"`
+}
+
+// newAPIKeyResponse maps an API key to its HTTP representation
+func newAPIKeyResponse(key *domain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// CreateAPIKey handles POST /admin/api-keys requests
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(newBindingError(err))
+		return
+	}
+	result, err := h.authUseCase.CreateAPIKey(c.Request.Context(), usecase.CreateAPIKeyRequest{
+		Name: req.Name,
+		Role: domain.Role(req.Role),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(result.APIKey),
+		Key:            result.Key,
+		Role:           string(result.Role),
+	})
+}
+
+// ListAPIKeys handles GET /admin/api-keys requests
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.authUseCase.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := ListAPIKeysResponse{Items: make([]APIKeyResponse, 0, len(keys))}
+	for _, key := range keys {
+		response.Items = append(response.Items, newAPIKeyResponse(key))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey handles DELETE /admin/api-keys/:id requests
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.authUseCase.RevokeAPIKey(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterRoutes registers API key admin routes
+func (h *APIKeyHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/admin/api-keys", requirePermission(domain.PermissionAPIKeyManage), h.CreateAPIKey)
+	r.GET("/admin/api-keys", requirePermission(domain.PermissionAPIKeyManage), h.ListAPIKeys)
+	r.DELETE("/admin/api-keys/:id", requirePermission(domain.PermissionAPIKeyManage), h.RevokeAPIKey)
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyHandler(t *testing.T) {
+	keyID := uuid.New()
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMocks     func(*mocks.MockIAPIKeyRepository)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "create key",
+			method: http.MethodPost,
+			path:   "/admin/api-keys",
+			body:   `{"name":"ci"}`,
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"key":"ice_`,
+		},
+		{
+			name:   "create key with role",
+			method: http.MethodPost,
+			path:   "/admin/api-keys",
+			body:   `{"name":"ci","role":"editor"}`,
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"role":"editor"`,
+		},
+		{
+			name:   "create key without name",
+			method: http.MethodPost,
+			path:   "/admin/api-keys",
+			body:   `{}`,
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "list keys",
+			method: http.MethodGet,
+			path:   "/admin/api-keys",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository) {
+				repo.On("List", mock.Anything).Return([]*domain.APIKey{
+					{ID: keyID, Name: "ci", Prefix: "ice_abcdef", KeyHash: "secret", CreatedAt: createdAt},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":[{"id":"` + keyID.String() + `","name":"ci","prefix":"ice_abcdef","createdAt":"2025-01-02T03:04:05Z"}]}`,
+		},
+		{
+			name:   "list keys error",
+			method: http.MethodGet,
+			path:   "/admin/api-keys",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository) {
+				repo.On("List", mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "revoke key",
+			method: http.MethodDelete,
+			path:   "/admin/api-keys/" + keyID.String(),
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository) {
+				repo.On("Revoke", mock.Anything, keyID.String(), mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "revoke unknown key",
+			method: http.MethodDelete,
+			path:   "/admin/api-keys/" + keyID.String(),
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository) {
+				repo.On("Revoke", mock.Anything, keyID.String(), mock.Anything).
+					Return(apperrors.NewAppError("API_KEY_NOT_FOUND", "api key not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			repo := mocks.NewMockIAPIKeyRepository(t)
+			tt.setupMocks(repo)
+
+			roleRepo := mocks.NewMockIRoleRepository(t)
+			roleRepo.On("Assign", mock.Anything, mock.Anything).Return(nil).Maybe()
+			handler := NewAPIKeyHandler(usecase.NewAuthUseCase(repo, roleRepo, newTransactionManager(t), nil, "", nil))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			handler.RegisterRoutes(router.Group(""))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
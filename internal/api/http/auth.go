@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader is the request header carrying an API key
+const APIKeyHeader = "X-API-Key"
+
+// authenticate is a middleware that requires a JWT bearer token or an API key and
+// puts the authenticated principal into the request context
+func authenticate(authUseCase *usecase.AuthUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := authenticateRequest(c, authUseCase)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="api"`)
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(domain.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// authenticateRequest checks the X-API-Key header, then the Authorization header.
+// API keys are also accepted as bearer tokens.
+func authenticateRequest(c *gin.Context, authUseCase *usecase.AuthUseCase) (*domain.Principal, error) {
+	ctx := c.Request.Context()
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return authUseCase.AuthenticateAPIKey(ctx, key)
+	}
+	authorization := c.GetHeader("Authorization")
+	if authorization == "" {
+		return nil, apperrors.NewAppError("UNAUTHORIZED", "authentication required", http.StatusUnauthorized, nil)
+	}
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, apperrors.NewAppError("UNAUTHORIZED", "unsupported authorization scheme", http.StatusUnauthorized, nil)
+	}
+	if strings.HasPrefix(token, domain.APIKeyPrefix) {
+		return authUseCase.AuthenticateAPIKey(ctx, token)
+	}
+	return authUseCase.AuthenticateToken(ctx, token)
+}
+
+// requirePermission is a middleware that rejects requests whose principal lacks the
+// permission. Usecases check permissions as well; declaring them on routes documents
+// them and rejects requests before their bodies are read.
+func requirePermission(permission domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := usecase.Authorize(c.Request.Context(), permission); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
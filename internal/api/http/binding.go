@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report validation failures under the names clients send rather than Go field names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName returns the JSON or query parameter name of a request struct field
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// newBindingError translates a request binding failure into a 400 error, listing the
+// offending fields where they are known
+func newBindingError(err error) error {
+	var validationErrs validator.ValidationErrors
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	var numErr *strconv.NumError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]apperrors.FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, apperrors.FieldError{
+				Field:   fieldPath(fieldErr),
+				Rule:    fieldErr.Tag(),
+				Message: validationMessage(fieldErr),
+			})
+		}
+		return apperrors.NewValidationError("request validation failed", fields, err)
+	case errors.Is(err, io.EOF):
+		return apperrors.NewValidationError("request body is empty", nil, err)
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return apperrors.NewValidationError("request body is not valid JSON", nil, err)
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return apperrors.NewValidationError("request body must be a JSON object", nil, err)
+		}
+		return apperrors.NewValidationError("request validation failed", []apperrors.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + jsonTypeName(typeErr.Type),
+		}}, err)
+	case errors.As(err, &timeErr):
+		return apperrors.NewValidationError("request validation failed", []apperrors.FieldError{{
+			Rule:    "datetime",
+			Message: fmt.Sprintf("%q is not an RFC 3339 timestamp", timeErr.Value),
+		}}, err)
+	case errors.As(err, &numErr):
+		return apperrors.NewValidationError("request validation failed", []apperrors.FieldError{{
+			Rule:    "number",
+			Message: fmt.Sprintf("%q is not a valid number", numErr.Num),
+		}}, err)
+	default:
+		return apperrors.NewValidationError("invalid input", nil, err)
+	}
+}
+
+// fieldPath returns the dotted path of a field below the request struct
+func fieldPath(fieldErr validator.FieldError) string {
+	if _, path, ok := strings.Cut(fieldErr.Namespace(), "."); ok {
+		return path
+	}
+	return fieldErr.Field()
+}
+
+// validationMessage describes a failed validation rule in plain words
+func validationMessage(fieldErr validator.FieldError) string {
+	unit := ""
+	if fieldErr.Kind() == reflect.String {
+		unit = " characters"
+	}
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "uuid":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fieldErr.Param()), ", ")
+	case "min":
+		return "must be at least " + fieldErr.Param() + unit
+	case "max":
+		return "must be at most " + fieldErr.Param() + unit
+	default:
+		return fmt.Sprintf("failed the %q rule", fieldErr.Tag())
+	}
+}
+
+// jsonTypeName names the JSON type that decodes into t
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	default:
+		return "an object"
+	}
+}
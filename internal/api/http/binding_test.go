@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBindingError(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		query           string
+		expectedMessage string
+		expectedFields  []apperrors.FieldError
+	}{
+		{
+			name:            "missing required fields",
+			body:            `{}`,
+			expectedMessage: "request validation failed",
+			expectedFields: []apperrors.FieldError{
+				{Field: "description", Rule: "required", Message: "is required"},
+				{Field: "dueDate", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:            "invalid file id",
+			body:            `{"description":"a","dueDate":"2030-01-01T00:00:00Z","fileId":"abc"}`,
+			expectedMessage: "request validation failed",
+			expectedFields: []apperrors.FieldError{
+				{Field: "fileId", Rule: "uuid", Message: "must be a valid UUID"},
+			},
+		},
+		{
+			name:            "wrong field type",
+			body:            `{"description":42}`,
+			expectedMessage: "request validation failed",
+			expectedFields: []apperrors.FieldError{
+				{Field: "description", Rule: "type", Message: "must be a string"},
+			},
+		},
+		{
+			name:            "invalid timestamp",
+			body:            `{"description":"a","dueDate":"tomorrow"}`,
+			expectedMessage: "request validation failed",
+			expectedFields: []apperrors.FieldError{
+				{Rule: "datetime", Message: `"tomorrow" is not an RFC 3339 timestamp`},
+			},
+		},
+		{
+			name:            "malformed json",
+			body:            `{"description":`,
+			expectedMessage: "request body is not valid JSON",
+		},
+		{
+			name:            "syntax error",
+			body:            `{"description" "a"}`,
+			expectedMessage: "request body is not valid JSON",
+		},
+		{
+			name:            "empty body",
+			body:            ``,
+			expectedMessage: "request body is empty",
+		},
+		{
+			name:            "body is not an object",
+			body:            `[]`,
+			expectedMessage: "request body must be a JSON object",
+		},
+		{
+			name:            "query rule",
+			query:           "sortBy=title&limit=500",
+			expectedMessage: "request validation failed",
+			expectedFields: []apperrors.FieldError{
+				{Field: "sortBy", Rule: "oneof", Message: "must be one of: dueDate, createdAt"},
+				{Field: "limit", Rule: "max", Message: "must be at most 100"},
+			},
+		},
+		{
+			name:            "query number",
+			query:           "limit=ten",
+			expectedMessage: "request validation failed",
+			expectedFields: []apperrors.FieldError{
+				{Rule: "number", Message: `"ten" is not a valid number`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/todo?"+tt.query, strings.NewReader(tt.body))
+
+			var err error
+			if tt.query != "" {
+				err = c.ShouldBindQuery(&ListTodosQuery{})
+			} else {
+				err = c.ShouldBindJSON(&CreateTodoRequest{})
+			}
+			require.Error(t, err)
+
+			appErr, ok := apperrors.AsAppError(newBindingError(err))
+			require.True(t, ok)
+			assert.Equal(t, "INVALID_INPUT", appErr.Code)
+			assert.Equal(t, http.StatusBadRequest, appErr.HTTPStatus)
+			assert.Equal(t, tt.expectedMessage, appErr.Message)
+			assert.Equal(t, tt.expectedFields, appErr.Fields)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// DeadLetterHandler handles admin requests for dead-lettered stream messages
+type DeadLetterHandler struct {
+	deadLetterUseCase *usecase.DeadLetterUseCase
+}
+
+// NewDeadLetterHandler creates a new DeadLetterHandler
+func NewDeadLetterHandler(deadLetterUseCase *usecase.DeadLetterUseCase) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterUseCase: deadLetterUseCase,
+	}
+}
+
+// ListDeadLettersQuery represents the query parameters for listing dead letters
+type ListDeadLettersQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// DeadLetterResponse represents a dead-lettered message
+type DeadLetterResponse struct {
+	ID        string                 `json:"id"`
+	Stream    string                 `json:"stream"`
+	MessageID string                 `json:"messageId"`
+	Group     string                 `json:"group"`
+	Payload   map[string]interface{} `json:"payload"`
+	Error     string                 `json:"error"`
+	Attempts  int                    `json:"attempts"`
+	FailedAt  time.Time              `json:"failedAt"`
+}
+
+// ListDeadLettersResponse represents a page of dead-lettered messages
+type ListDeadLettersResponse struct {
+	Items      []DeadLetterResponse `json:"items"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+}
+
+// ReplayDeadLetterResponse represents the response for a replayed dead letter
+type ReplayDeadLetterResponse struct {
+	MessageID string `json:"messageId"`
+}
+
+// PurgeDeadLettersResponse represents the response for a purged dead-letter queue
+type PurgeDeadLettersResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+// newDeadLetterResponse maps a dead letter to its HTTP representation
+func newDeadLetterResponse(letter *client.DeadLetter) DeadLetterResponse {
+	return DeadLetterResponse{
+		ID:        letter.ID,
+		Stream:    letter.Stream,
+		MessageID: letter.MessageID,
+		Group:     letter.Group,
+		Payload:   letter.Values,
+		Error:     letter.Error,
+		Attempts:  letter.Attempts,
+		FailedAt:  letter.FailedAt,
+	}
+}
+
+// ListDeadLetters handles GET /admin/dlq/:stream requests
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	var query ListDeadLettersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindingError(err))
+		return
+	}
+	result, err := h.deadLetterUseCase.ListDeadLetters(c.Request.Context(), usecase.ListDeadLettersRequest{
+		Stream: c.Param("stream"),
+		After:  query.Cursor,
+		Limit:  query.Limit,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := ListDeadLettersResponse{
+		Items:      make([]DeadLetterResponse, 0, len(result.Items)),
+		NextCursor: result.NextCursor,
+	}
+	for _, letter := range result.Items {
+		response.Items = append(response.Items, newDeadLetterResponse(letter))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ReplayDeadLetter handles POST /admin/dlq/:stream/:id/replay requests
+func (h *DeadLetterHandler) ReplayDeadLetter(c *gin.Context) {
+	messageID, err := h.deadLetterUseCase.ReplayDeadLetter(c.Request.Context(), c.Param("stream"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, ReplayDeadLetterResponse{
+		MessageID: messageID,
+	})
+}
+
+// PurgeDeadLetters handles DELETE /admin/dlq/:stream requests
+func (h *DeadLetterHandler) PurgeDeadLetters(c *gin.Context) {
+	purged, err := h.deadLetterUseCase.PurgeDeadLetters(c.Request.Context(), usecase.PurgeDeadLettersRequest{
+		Stream: c.Param("stream"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, PurgeDeadLettersResponse{
+		Purged: purged,
+	})
+}
+
+// DeleteDeadLetter handles DELETE /admin/dlq/:stream/:id requests
+func (h *DeadLetterHandler) DeleteDeadLetter(c *gin.Context) {
+	err := h.deadLetterUseCase.DeleteDeadLetter(c.Request.Context(), c.Param("stream"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterRoutes registers dead-letter admin routes
+func (h *DeadLetterHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/admin/dlq/:stream", requirePermission(domain.PermissionStreamAdmin), h.ListDeadLetters)
+	r.DELETE("/admin/dlq/:stream", requirePermission(domain.PermissionStreamAdmin), h.PurgeDeadLetters)
+	r.POST("/admin/dlq/:stream/:id/replay", requirePermission(domain.PermissionStreamAdmin), h.ReplayDeadLetter)
+	r.DELETE("/admin/dlq/:stream/:id", requirePermission(domain.PermissionStreamAdmin), h.DeleteDeadLetter)
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeadLetterHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setupMocks     func(*mocks.MockIDeadLetterStore)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "list dead letters",
+			method: "GET",
+			path:   "/admin/dlq/todo-items?limit=1",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("List", mock.Anything, "todo-items", "", int64(2)).Return([]*client.DeadLetter{
+					{ID: "1-0", Stream: "todo-items", MessageID: "9-0", Values: map[string]interface{}{"data": "{}"}, Error: "boom", Attempts: 5},
+					{ID: "2-0"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "1-0",
+		},
+		{
+			name:   "list invalid limit",
+			method: "GET",
+			path:   "/admin/dlq/todo-items?limit=1000",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "list unknown stream",
+			method: "GET",
+			path:   "/admin/dlq/unknown",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "replay dead letter",
+			method: "POST",
+			path:   "/admin/dlq/todo-items/1-0/replay",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Replay", mock.Anything, "todo-items", "1-0").Return("10-0", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "10-0",
+		},
+		{
+			name:   "replay missing dead letter",
+			method: "POST",
+			path:   "/admin/dlq/todo-items/1-0/replay",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Replay", mock.Anything, "todo-items", "1-0").
+					Return("", apperrors.NewAppError("DEAD_LETTER_NOT_FOUND", "dead letter not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "purge dead letters",
+			method: "DELETE",
+			path:   "/admin/dlq/todo-items",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string(nil)).Return(int64(3), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"purged":3`,
+		},
+		{
+			name:   "delete dead letter",
+			method: "DELETE",
+			path:   "/admin/dlq/todo-items/1-0",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string{"1-0"}).Return(int64(1), nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "delete missing dead letter",
+			method: "DELETE",
+			path:   "/admin/dlq/todo-items/1-0",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string{"1-0"}).Return(int64(0), nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "DEAD_LETTER_NOT_FOUND",
+		},
+		{
+			name:   "delete invalid id",
+			method: "DELETE",
+			path:   "/admin/dlq/todo-items/abc",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			store := mocks.NewMockIDeadLetterStore(t)
+			tt.setupMocks(store)
+
+			handler := NewDeadLetterHandler(usecase.NewDeadLetterUseCase(store))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(asOperator())
+			handler.RegisterRoutes(router.Group(""))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
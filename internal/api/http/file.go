@@ -1,16 +1,24 @@
 package http
 
 import (
-	"bytes"
+	"errors"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"time"
 
+	"github.com/ar-agahian/ice-assignment/internal/domain"
 	"github.com/ar-agahian/ice-assignment/internal/usecase"
 	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	fileFormField = "file"
+)
+
 // FileHandler handles file upload HTTP requests
 type FileHandler struct {
 	fileUseCase *usecase.FileUseCase
@@ -28,55 +36,132 @@ type UploadFileResponse struct {
 	FileID string `json:"fileId"`
 }
 
-// UploadFile handles POST /upload requests
+// UploadFile handles POST /upload requests. The multipart body is read part by
+// part so the file is streamed to storage instead of being parsed into memory.
 func (h *FileHandler) UploadFile(c *gin.Context) {
-	_, header, err := c.Request.FormFile("file")
+	fileID, err := h.uploadFile(c)
 	if err != nil {
-		appErr := apperrors.NewAppError("FILE_REQUIRED", "file is required", http.StatusBadRequest, nil)
-		c.Error(appErr)
+		metrics.ObserveUploadRejection(err)
+		c.Error(err)
 		return
 	}
 
-	src, err := header.Open()
+	c.JSON(http.StatusOK, UploadFileResponse{
+		FileID: fileID,
+	})
+}
+
+// uploadFile streams the file part of the multipart request to the file use case
+func (h *FileHandler) uploadFile(c *gin.Context) (string, error) {
+	reader, err := c.Request.MultipartReader()
 	if err != nil {
-		appErr := apperrors.NewAppError("INVALID_INPUT", "invalid input", http.StatusBadRequest, nil)
-		c.Error(appErr)
+		return "", apperrors.NewAppError("FILE_REQUIRED", "file is required", http.StatusBadRequest, nil)
+	}
+
+	part, err := nextFilePart(reader)
+	if err != nil {
+		return "", err
+	}
+	defer part.Close()
+
+	return h.fileUseCase.UploadFile(c.Request.Context(), usecase.UploadFileRequest{
+		File:     part,
+		Filename: part.FileName(),
+	})
+}
+
+// DownloadFile handles GET /asset/:id requests, streaming the stored object
+func (h *FileHandler) DownloadFile(c *gin.Context) {
+	file, err := h.fileUseCase.DownloadFile(c.Request.Context(), usecase.DownloadFileRequest{
+		FileID:      c.Param("id"),
+		Range:       c.GetHeader("Range"),
+		IfNoneMatch: c.GetHeader("If-None-Match"),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if file.NotModified {
+		c.Header("ETag", file.ETag)
+		c.Status(http.StatusNotModified)
 		return
 	}
 	defer func() {
-		if err := src.Close(); err != nil {
+		if err := file.Body.Close(); err != nil {
 			log.Printf("failed to close file: %v", err)
 		}
 	}()
 
-	buf := bytes.NewBuffer(nil)
-	if _, err = io.Copy(buf, src); err != nil {
-		appErr := apperrors.NewAppError("INTERNAL_ERROR", "internal server error", http.StatusInternalServerError, err)
-		c.Error(appErr)
-		return
+	status := http.StatusOK
+	headers := map[string]string{
+		"Accept-Ranges": "bytes",
+	}
+	if file.ETag != "" {
+		headers["ETag"] = file.ETag
+	}
+	if file.ContentDisposition != "" {
+		headers["Content-Disposition"] = file.ContentDisposition
 	}
+	if !file.LastModified.IsZero() {
+		headers["Last-Modified"] = file.LastModified.UTC().Format(http.TimeFormat)
+	}
+	if file.ContentRange != "" {
+		status = http.StatusPartialContent
+		headers["Content-Range"] = file.ContentRange
+	}
+	c.DataFromReader(status, file.ContentLength, file.ContentType, file.Body, headers)
+}
 
-	contentType := http.DetectContentType(buf.Bytes())
-	fileSize := header.Size
-	filename := header.Filename
+// FileMetadataResponse represents the stored metadata of a file
+type FileMetadataResponse struct {
+	ID         string    `json:"id"`
+	Filename   string    `json:"filename"`
+	Size       int64     `json:"size"`
+	MimeType   string    `json:"mimeType"`
+	SHA256     string    `json:"sha256"`
+	UploadedBy string    `json:"uploadedBy,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
 
-	fileID, err := h.fileUseCase.UploadFile(c.Request.Context(), usecase.UploadFileRequest{
-		File:        bytes.NewReader(buf.Bytes()),
-		ContentType: contentType,
-		Size:        fileSize,
-		Filename:    filename,
-	})
+// GetFileMetadata handles GET /asset/:id/metadata requests
+func (h *FileHandler) GetFileMetadata(c *gin.Context) {
+	file, err := h.fileUseCase.GetFileMetadata(c.Request.Context(), c.Param("id"))
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, UploadFileResponse{
-		FileID: fileID,
+	c.JSON(http.StatusOK, FileMetadataResponse{
+		ID:         file.ID.String(),
+		Filename:   file.OriginalFilename,
+		Size:       file.Size,
+		MimeType:   file.MimeType,
+		SHA256:     file.SHA256,
+		UploadedBy: file.UploadedBy,
+		CreatedAt:  file.CreatedAt,
 	})
 }
 
-// RegisterRoutes registers file routes
-func (h *FileHandler) RegisterRoutes(r *gin.RouterGroup) {
-	r.POST("/asset", h.UploadFile)
+// RegisterRoutes registers file routes. idempotent is attached to the upload route.
+func (h *FileHandler) RegisterRoutes(r *gin.RouterGroup, idempotent gin.HandlerFunc) {
+	r.POST("/asset", requirePermission(domain.PermissionAssetCreate), idempotent, h.UploadFile)
+	r.GET("/asset/:id", requirePermission(domain.PermissionAssetRead), h.DownloadFile)
+	r.GET("/asset/:id/metadata", requirePermission(domain.PermissionAssetRead), h.GetFileMetadata)
+}
+
+// nextFilePart advances the multipart reader to the file form field
+func nextFilePart(reader *multipart.Reader) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, apperrors.NewAppError("FILE_REQUIRED", "file is required", http.StatusBadRequest, nil)
+		}
+		if err != nil {
+			return nil, apperrors.NewAppError("INVALID_INPUT", "invalid input", http.StatusBadRequest, err)
+		}
+		if part.FormName() == fileFormField && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
 }
@@ -0,0 +1,256 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFileHandler_UploadFile(t *testing.T) {
+	fileID := "3f1c1f9e-8f7a-4b7e-9a55-0c2b8f6d2a10"
+	tests := []struct {
+		name           string
+		fieldName      string
+		content        []byte
+		setupMocks     func(*mocks.MockIFileStorage, *mocks.MockIFileRepository)
+		expectedStatus int
+	}{
+		{
+			name:      "successful upload",
+			fieldName: "file",
+			content:   []byte("test file content"),
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				storage.On("Upload", mock.Anything, mock.Anything, "text/plain; charset=utf-8", "test.txt").
+					Return(func(ctx context.Context, file io.Reader, contentType string, filename string) (string, error) {
+						data, err := io.ReadAll(file)
+						if err != nil || string(data) != "test file content" {
+							return "", io.ErrUnexpectedEOF
+						}
+						return fileID, nil
+					})
+				fileRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.File")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "missing file field",
+			fieldName: "attachment",
+			content:   []byte("test file content"),
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "disallowed file type",
+			fieldName: "file",
+			content:   []byte{0x4D, 0x5A, 0x90, 0x00},
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			storage := mocks.NewMockIFileStorage(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(storage, fileRepo)
+
+			handler := NewFileHandler(usecase.NewFileUseCase(storage, fileRepo, usecase.UploadConfig{}))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			router.POST("/asset", handler.UploadFile)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, _ := writer.CreateFormFile(tt.fieldName, "test.txt")
+			_, _ = part.Write(tt.content)
+			_ = writer.Close()
+
+			req := httptest.NewRequest("POST", "/asset", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestFileHandler_DownloadFile(t *testing.T) {
+	fileID := "3f1c1f9e-8f7a-4b7e-9a55-0c2b8f6d2a10"
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name            string
+		headers         map[string]string
+		setupMocks      func(*mocks.MockIFileStorage)
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBody    string
+	}{
+		{
+			name: "full download",
+			setupMocks: func(storage *mocks.MockIFileStorage) {
+				storage.On("Open", mock.Anything, fileID, client.OpenFileOptions{}).Return(&client.FileObject{
+					Body:               io.NopCloser(strings.NewReader("test file content")),
+					ContentType:        "text/plain; charset=utf-8",
+					ContentLength:      17,
+					ContentDisposition: `attachment; filename=test.txt`,
+					ETag:               `"abc"`,
+					LastModified:       lastModified,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Content-Type":        "text/plain; charset=utf-8",
+				"Content-Length":      "17",
+				"Content-Disposition": `attachment; filename=test.txt`,
+				"ETag":                `"abc"`,
+				"Last-Modified":       "Tue, 02 Jan 2024 03:04:05 GMT",
+				"Accept-Ranges":       "bytes",
+			},
+			expectedBody: "test file content",
+		},
+		{
+			name:    "range request",
+			headers: map[string]string{"Range": "bytes=0-3"},
+			setupMocks: func(storage *mocks.MockIFileStorage) {
+				storage.On("Open", mock.Anything, fileID, client.OpenFileOptions{Range: "bytes=0-3"}).Return(&client.FileObject{
+					Body:          io.NopCloser(strings.NewReader("test")),
+					ContentType:   "text/plain; charset=utf-8",
+					ContentLength: 4,
+					ContentRange:  "bytes 0-3/17",
+				}, nil)
+			},
+			expectedStatus: http.StatusPartialContent,
+			expectedHeaders: map[string]string{
+				"Content-Range":  "bytes 0-3/17",
+				"Content-Length": "4",
+			},
+			expectedBody: "test",
+		},
+		{
+			name:    "not modified",
+			headers: map[string]string{"If-None-Match": `"abc"`},
+			setupMocks: func(storage *mocks.MockIFileStorage) {
+				storage.On("Open", mock.Anything, fileID, client.OpenFileOptions{IfNoneMatch: `"abc"`}).
+					Return(&client.FileObject{ETag: `"abc"`, NotModified: true}, nil)
+			},
+			expectedStatus:  http.StatusNotModified,
+			expectedHeaders: map[string]string{"ETag": `"abc"`},
+		},
+		{
+			name: "not found",
+			setupMocks: func(storage *mocks.MockIFileStorage) {
+				storage.On("Open", mock.Anything, fileID, client.OpenFileOptions{}).
+					Return(nil, apperrors.NewAppError("FILE_NOT_FOUND", "file not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			storage := mocks.NewMockIFileStorage(t)
+			tt.setupMocks(storage)
+
+			handler := NewFileHandler(usecase.NewFileUseCase(storage, mocks.NewMockIFileRepository(t), usecase.UploadConfig{}))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			router.GET("/asset/:id", handler.DownloadFile)
+
+			req := httptest.NewRequest("GET", "/asset/"+fileID, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for key, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(key), key)
+			}
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFileHandler_GetFileMetadata(t *testing.T) {
+	fileID := uuid.New()
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockIFileRepository)
+		expectedStatus int
+	}{
+		{
+			name: "found",
+			setupMocks: func(fileRepo *mocks.MockIFileRepository) {
+				fileRepo.On("GetByID", mock.Anything, fileID.String()).Return(&domain.File{
+					ID:               fileID,
+					OriginalFilename: "notes.txt",
+					Size:             12,
+					MimeType:         "text/plain; charset=utf-8",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			setupMocks: func(fileRepo *mocks.MockIFileRepository) {
+				fileRepo.On("GetByID", mock.Anything, fileID.String()).
+					Return(nil, apperrors.NewAppError("FILE_NOT_FOUND", "file not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(fileRepo)
+
+			handler := NewFileHandler(usecase.NewFileUseCase(mocks.NewMockIFileStorage(t), fileRepo, usecase.UploadConfig{}))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			handler.RegisterRoutes(router.Group(""), idempotency(nil, 0))
+
+			req := httptest.NewRequest("GET", "/asset/"+fileID.String()+"/metadata", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -1,35 +1,95 @@
 package http
 
 import (
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
 	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/pkg/correlation"
 	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // Handler sets up HTTP routes and middleware
 type Handler struct {
-	todoHandler *TodoHandler
-	fileHandler *FileHandler
+	todoHandler       *TodoHandler
+	fileHandler       *FileHandler
+	apiKeyHandler     *APIKeyHandler
+	roleHandler       *RoleHandler
+	authUseCase       *usecase.AuthUseCase
+	deadLetterHandler *DeadLetterHandler
+	streamHandler     *StreamHandler
+	healthHandler     *HealthHandler
+	idempotencyStore  client.IIdempotencyStore
+	maxBodySize       int64
 }
 
-// NewHandler creates a new HTTP handler
-func NewHandler(todoUseCase *usecase.TodoUseCase, fileUseCase *usecase.FileUseCase) *Handler {
-	return &Handler{
-		todoHandler: NewTodoHandler(todoUseCase),
-		fileHandler: NewFileHandler(fileUseCase),
+// NewHandler creates a new HTTP handler. All routes require authentication through
+// authUseCase and declare the permission they need, except the public /metrics,
+// /healthz and /readyz endpoints. The dead-letter and stream admin routes and the probes are only
+// registered when their use cases are not nil, and Idempotency-Key headers are only honoured when
+// idempotencyStore is not nil. maxUploadSize is the upload size limit; idempotent requests
+// may exceed it by multipartOverhead.
+func NewHandler(
+	todoUseCase *usecase.TodoUseCase,
+	fileUseCase *usecase.FileUseCase,
+	authUseCase *usecase.AuthUseCase,
+	deadLetterUseCase *usecase.DeadLetterUseCase,
+	streamUseCase *usecase.StreamUseCase,
+	healthUseCase *usecase.HealthUseCase,
+	idempotencyStore client.IIdempotencyStore,
+	maxUploadSize int64,
+) *Handler {
+	h := &Handler{
+		todoHandler:      NewTodoHandler(todoUseCase),
+		fileHandler:      NewFileHandler(fileUseCase),
+		apiKeyHandler:    NewAPIKeyHandler(authUseCase),
+		roleHandler:      NewRoleHandler(authUseCase),
+		authUseCase:      authUseCase,
+		idempotencyStore: idempotencyStore,
+		maxBodySize:      maxUploadSize + multipartOverhead,
 	}
+	if deadLetterUseCase != nil {
+		h.deadLetterHandler = NewDeadLetterHandler(deadLetterUseCase)
+	}
+	if streamUseCase != nil {
+		h.streamHandler = NewStreamHandler(streamUseCase)
+	}
+	if healthUseCase != nil {
+		h.healthHandler = NewHealthHandler(healthUseCase)
+	}
+	return h
 }
 
 // SetupRoutes configures all HTTP routes
 func (h *Handler) SetupRoutes() *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger())
+	// Recovery runs inside traceRequests and requestMetrics so panics are recorded as
+	// 500 responses
+	r.Use(traceRequests())
+	r.Use(requestMetrics())
 	r.Use(gin.Recovery())
+	r.Use(correlationID())
 	r.Use(errorHandler())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	if h.healthHandler != nil {
+		h.healthHandler.RegisterRoutes(&r.RouterGroup)
+	}
 	api := r.Group("/api")
+	api.Use(authenticate(h.authUseCase))
+	idempotent := idempotency(h.idempotencyStore, h.maxBodySize)
 	{
-		h.todoHandler.RegisterRoutes(api)
-		h.fileHandler.RegisterRoutes(api)
+		h.todoHandler.RegisterRoutes(api, idempotent)
+		h.fileHandler.RegisterRoutes(api, idempotent)
+		h.apiKeyHandler.RegisterRoutes(api)
+		h.roleHandler.RegisterRoutes(api)
+		if h.deadLetterHandler != nil {
+			h.deadLetterHandler.RegisterRoutes(api)
+		}
+		if h.streamHandler != nil {
+			h.streamHandler.RegisterRoutes(api)
+		}
 	}
 	return r
 }
@@ -38,12 +98,52 @@ func (h *Handler) SetupRoutes() *gin.Engine {
 func errorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
-		if len(c.Errors) > 0 {
-			err := c.Errors.Last().Err
-			statusCode := apperrors.GetHTTPStatus(err)
-			response := apperrors.GetErrorResponse(err)
-			c.JSON(statusCode, response)
-			return
+		if len(c.Errors) > 0 && !c.Writer.Written() {
+			writeError(c, c.Errors.Last().Err)
+		}
+	}
+}
+
+// writeError writes the error response for err: RFC 7807 problem details when the
+// client accepts application/problem+json over application/json, the legacy
+// {"error": {...}} shape otherwise
+func writeError(c *gin.Context, err error) {
+	statusCode := apperrors.GetHTTPStatus(err)
+	if c.NegotiateFormat(gin.MIMEJSON, apperrors.ProblemContentType) == apperrors.ProblemContentType {
+		c.Header("Content-Type", apperrors.ProblemContentType)
+		c.JSON(statusCode, apperrors.GetProblemDetails(err, c.Request.URL.Path))
+		return
+	}
+	response := apperrors.GetErrorResponse(err)
+	c.JSON(statusCode, response)
+}
+
+// maxCorrelationIDLength bounds client-supplied correlation IDs
+const maxCorrelationIDLength = 128
+
+// correlationID is a middleware that propagates the X-Correlation-ID header into the
+// request context, generating an ID when the client did not send a usable one
+func correlationID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(correlation.Header)
+		if !isValidCorrelationID(id) {
+			id = uuid.New().String()
+		}
+		c.Request = c.Request.WithContext(correlation.WithID(c.Request.Context(), id))
+		c.Header(correlation.Header, id)
+		c.Next()
+	}
+}
+
+// isValidCorrelationID reports whether id is a non-empty, bounded, printable ASCII token
+func isValidCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
 		}
 	}
+	return true
 }
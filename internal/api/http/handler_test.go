@@ -0,0 +1,223 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/ar-agahian/ice-assignment/pkg/correlation"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCorrelationID(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		expectSame bool
+	}{
+		{
+			name:       "propagates client id",
+			header:     "req-123",
+			expectSame: true,
+		},
+		{
+			name:   "generates missing id",
+			header: "",
+		},
+		{
+			name:   "replaces overlong id",
+			header: strings.Repeat("a", 129),
+		},
+		{
+			name:   "replaces id with control characters",
+			header: "req\t123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			var contextID string
+			router := gin.New()
+			router.Use(correlationID())
+			router.GET("/", func(c *gin.Context) {
+				contextID = correlation.FromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(correlation.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, contextID, w.Header().Get(correlation.Header))
+			if tt.expectSame {
+				assert.Equal(t, tt.header, contextID)
+			} else {
+				_, err := uuid.Parse(contextID)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestErrorHandler_ContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		err                 error
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "legacy format by default",
+			err:                 apperrors.NewAppError("TODO_NOT_FOUND", "todo not found", http.StatusNotFound, nil),
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":{"code":"TODO_NOT_FOUND","message":"todo not found"}}`,
+		},
+		{
+			name:                "legacy format for application/json",
+			accept:              "application/json",
+			err:                 apperrors.NewAppError("TODO_NOT_FOUND", "todo not found", http.StatusNotFound, nil),
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":{"code":"TODO_NOT_FOUND","message":"todo not found"}}`,
+		},
+		{
+			name:                "problem details",
+			accept:              "application/problem+json",
+			err:                 apperrors.NewAppError("TODO_NOT_FOUND", "todo not found", http.StatusNotFound, nil),
+			expectedContentType: "application/problem+json",
+			expectedBody:        `{"type":"urn:ice-assignment:problem:todo-not-found","title":"Not Found","status":404,"detail":"todo not found","instance":"/todo/1","code":"TODO_NOT_FOUND"}`,
+		},
+		{
+			name:                "problem details preferred over json",
+			accept:              "application/problem+json, application/json;q=0.9",
+			err:                 apperrors.NewValidationError("request validation failed", []apperrors.FieldError{{Field: "description", Rule: "required", Message: "is required"}}, nil),
+			expectedContentType: "application/problem+json",
+			expectedBody:        `{"type":"urn:ice-assignment:problem:invalid-input","title":"Bad Request","status":400,"detail":"request validation failed","instance":"/todo/1","code":"INVALID_INPUT","fields":[{"field":"description","rule":"required","message":"is required"}]}`,
+		},
+		{
+			name:                "problem details hide internal errors",
+			accept:              "application/problem+json",
+			err:                 assert.AnError,
+			expectedContentType: "application/problem+json",
+			expectedBody:        `{"type":"urn:ice-assignment:problem:internal-error","title":"Internal Server Error","status":500,"detail":"internal server error","instance":"/todo/1","code":"INTERNAL_ERROR"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(errorHandler())
+			router.GET("/todo/:id", func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			req := httptest.NewRequest("GET", "/todo/1", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestSetupRoutes_DeadLetterRoutes(t *testing.T) {
+	tests := []struct {
+		name           string
+		withStore      bool
+		operators      []string
+		expectedStatus int
+	}{
+		{
+			name:           "registered with a dead-letter store",
+			withStore:      true,
+			operators:      []string{"user:user-1"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "forbidden for tenant admins",
+			withStore:      true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "omitted without a dead-letter store",
+			withStore:      false,
+			operators:      []string{"user:user-1"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadLetterUseCase *usecase.DeadLetterUseCase
+			if tt.withStore {
+				store := mocks.NewMockIDeadLetterStore(t)
+				store.On("List", mock.Anything, "todo-items", "", int64(51)).Return(nil, nil).Maybe()
+				deadLetterUseCase = usecase.NewDeadLetterUseCase(store)
+			}
+			verifier := mocks.NewMockITokenVerifier(t)
+			verifier.On("Verify", mock.Anything, "token").Return(&domain.Principal{ID: "user-1", Type: domain.PrincipalUser}, nil).Maybe()
+			roleRepo := mocks.NewMockIRoleRepository(t)
+			roleRepo.On("Get", mock.Anything, domain.PrincipalUser, "user-1").
+				Return(&domain.RoleAssignment{Role: domain.RoleAdmin}, nil).Maybe()
+			authUseCase := usecase.NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), roleRepo, newTransactionManager(t), verifier, "", tt.operators)
+			router := NewHandler(nil, nil, authUseCase, deadLetterUseCase, nil, nil, nil, 0).SetupRoutes()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/dlq/todo-items", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestSetupRoutes_IdempotencyAfterPermission(t *testing.T) {
+	verifier := mocks.NewMockITokenVerifier(t)
+	verifier.On("Verify", mock.Anything, "token").Return(&domain.Principal{ID: "user-1", Type: domain.PrincipalUser}, nil)
+	roleRepo := mocks.NewMockIRoleRepository(t)
+	roleRepo.On("Get", mock.Anything, domain.PrincipalUser, "user-1").Return(&domain.RoleAssignment{Role: domain.RoleViewer}, nil)
+	authUseCase := usecase.NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), roleRepo, newTransactionManager(t), verifier, "", nil)
+	// The store has no expectations: a forbidden request must not reserve a key
+	store := mocks.NewMockIIdempotencyStore(t)
+	router := NewHandler(nil, nil, authUseCase, nil, nil, nil, store, 0).SetupRoutes()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/asset", strings.NewReader("upload"))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSetupRoutes_RequiresAuthentication(t *testing.T) {
+	authUseCase := usecase.NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), mocks.NewMockIRoleRepository(t), newTransactionManager(t), nil, "", nil)
+	router := NewHandler(nil, nil, authUseCase, nil, nil, nil, nil, 0).SetupRoutes()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/todo", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="api"`, w.Header().Get("WWW-Authenticate"))
+}
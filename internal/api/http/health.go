@@ -0,0 +1,87 @@
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// Health statuses reported by the probe endpoints
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	healthUseCase *usecase.HealthUseCase
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(healthUseCase *usecase.HealthUseCase) *HealthHandler {
+	return &HealthHandler{
+		healthUseCase: healthUseCase,
+	}
+}
+
+// LivenessResponse represents the liveness probe response
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
+
+// HealthCheckResponse represents the status of one dependency
+type HealthCheckResponse struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+// ReadinessResponse represents the readiness probe response
+type ReadinessResponse struct {
+	Status string                `json:"status"`
+	Checks []HealthCheckResponse `json:"checks"`
+}
+
+// Liveness handles GET /healthz requests. It only reports that the process is up.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, LivenessResponse{Status: healthStatusOK})
+}
+
+// Readiness handles GET /readyz requests, answering 503 when any dependency is unhealthy.
+// The probe is public, so failures are only logged and never returned to the caller.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	report := h.healthUseCase.CheckReadiness(c.Request.Context())
+
+	response := ReadinessResponse{
+		Status: healthStatusOK,
+		Checks: make([]HealthCheckResponse, 0, len(report.Checks)),
+	}
+	for _, check := range report.Checks {
+		result := HealthCheckResponse{
+			Name:      check.Name,
+			Status:    healthStatusOK,
+			LatencyMs: float64(check.Latency.Microseconds()) / 1000,
+		}
+		if check.Err != nil {
+			result.Status = healthStatusUnavailable
+			log.Printf("readiness check %s failed: %v", check.Name, check.Err)
+		}
+		response.Checks = append(response.Checks, result)
+	}
+
+	statusCode := http.StatusOK
+	if !report.Healthy {
+		response.Status = healthStatusUnavailable
+		statusCode = http.StatusServiceUnavailable
+	}
+	c.JSON(statusCode, response)
+}
+
+// RegisterRoutes registers the probe routes. They are public so orchestrators can
+// call them without credentials.
+func (h *HealthHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/healthz", h.Liveness)
+	r.GET("/readyz", h.Readiness)
+}
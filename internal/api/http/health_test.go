@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(mysql, redis *mocks.MockIHealthChecker)
+		expectedStatus int
+		expectedBody   []string
+		unexpectedBody []string
+	}{
+		{
+			name: "liveness",
+			path: "/healthz",
+			setupMocks: func(mysql, redis *mocks.MockIHealthChecker) {
+				// No mocks needed, liveness does not check dependencies
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`{"status":"ok"}`},
+		},
+		{
+			name: "ready",
+			path: "/readyz",
+			setupMocks: func(mysql, redis *mocks.MockIHealthChecker) {
+				mysql.On("Check", mock.Anything).Return(nil)
+				redis.On("Check", mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				`{"status":"ok","checks":[{"name":"mysql","status":"ok","latencyMs":`,
+				`{"name":"redis","status":"ok","latencyMs":`,
+			},
+		},
+		{
+			name: "dependency unavailable",
+			path: "/readyz",
+			setupMocks: func(mysql, redis *mocks.MockIHealthChecker) {
+				mysql.On("Check", mock.Anything).Return(nil)
+				redis.On("Check", mock.Anything).Return(errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: []string{
+				`{"status":"unavailable","checks":[{"name":"mysql","status":"ok","latencyMs":`,
+				`"name":"redis","status":"unavailable"`,
+			},
+			unexpectedBody: []string{"connection refused"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mysql := mocks.NewMockIHealthChecker(t)
+			redis := mocks.NewMockIHealthChecker(t)
+			tt.setupMocks(mysql, redis)
+
+			handler := NewHealthHandler(usecase.NewHealthUseCase(map[string]client.IHealthChecker{
+				"mysql": mysql,
+				"redis": redis,
+			}, time.Second))
+
+			router := gin.New()
+			handler.RegisterRoutes(router.Group(""))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for _, body := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), body)
+			}
+			for _, body := range tt.unexpectedBody {
+				assert.NotContains(t, w.Body.String(), body)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying a client-chosen idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// multipartOverhead is the room left above the upload size limit for multipart
+	// boundaries, part headers and other form fields when fingerprinting
+	multipartOverhead = 1 << 20
+	// idempotencyRefreshInterval is how often the reservation of a request that is still
+	// running is extended, well within the store's lock TTL
+	idempotencyRefreshInterval = time.Minute
+)
+
+// idempotency is a middleware that replays the stored response of an earlier request
+// with the same Idempotency-Key and request fingerprint. It is attached to individual
+// routes after requirePermission, so that unauthorized requests are rejected before their
+// bodies are read. Responses are stored unless they are authentication, authorization or
+// server errors, which release the key so that the request can be retried. Bodies larger
+// than maxBodySize are rejected, since they are buffered for fingerprinting. A nil store
+// disables the middleware.
+func idempotency(store client.IIdempotencyStore, maxBodySize int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if store == nil || key == "" {
+			c.Next()
+			return
+		}
+		route := c.Request.Method + " " + c.FullPath()
+		if !isValidIdempotencyKey(key) {
+			c.Error(apperrors.NewAppError("INVALID_IDEMPOTENCY_KEY", "idempotency key must be at most 255 printable characters", http.StatusBadRequest, nil))
+			c.Abort()
+			return
+		}
+
+		fingerprint, cleanup, err := fingerprintRequest(c.Request, maxBodySize)
+		defer cleanup()
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.Error(apperrors.NewAppError("REQUEST_TOO_LARGE", "request body is too large", http.StatusRequestEntityTooLarge, err))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.Error(newBindingError(err))
+			c.Abort()
+			return
+		}
+
+		// Keep storing the response even if the client disconnects
+		ctx := context.WithoutCancel(c.Request.Context())
+		scopedKey := idempotencyScope(ctx, route) + key
+		record, err := store.Reserve(ctx, scopedKey, fingerprint)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if record != nil {
+			replayIdempotentResponse(c, record, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		stopRefresh := keepReserved(ctx, store, scopedKey, idempotencyRefreshInterval)
+		stored := false
+		defer func() {
+			stopRefresh()
+			if !stored {
+				if err := store.Release(ctx, scopedKey); err != nil {
+					log.Printf("failed to release idempotency key: %v", err)
+				}
+			}
+		}()
+
+		c.Next()
+		if len(c.Errors) > 0 && !c.Writer.Written() {
+			writeError(c, c.Errors.Last().Err)
+		}
+		stopRefresh()
+		if !isStorableStatus(recorder.Status()) {
+			return
+		}
+		err = store.Complete(ctx, scopedKey, &client.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			StatusCode:  recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			log.Printf("failed to store idempotent response: %v", err)
+			return
+		}
+		stored = true
+	}
+}
+
+// keepReserved extends the reservation of key every interval until the returned stop
+// function is called, so that slow requests such as large uploads keep their key.
+// stop waits for a pending extension and may be called more than once.
+func keepReserved(ctx context.Context, store client.IIdempotencyStore, key string, interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.Extend(ctx, key); err != nil {
+					log.Printf("failed to extend idempotency key: %v", err)
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
+}
+
+// isStorableStatus reports whether a response with the given status is replayed for
+// retries. Authentication and authorization failures are not, since the retry may carry
+// other credentials, and neither are server errors, since the retry may succeed.
+func isStorableStatus(status int) bool {
+	return status != http.StatusUnauthorized && status != http.StatusForbidden && status < http.StatusInternalServerError
+}
+
+// idempotencyScope namespaces keys by tenant, caller and route so that different
+// clients and endpoints cannot collide
+func idempotencyScope(ctx context.Context, route string) string {
+	if principal := domain.PrincipalFromContext(ctx); principal != nil {
+		return domain.TenantFromContext(ctx) + ":" + string(principal.Type) + ":" + principal.ID + ":" + route + ":"
+	}
+	return route + ":"
+}
+
+// replayIdempotentResponse answers a request whose key is already taken
+func replayIdempotentResponse(c *gin.Context, record *client.IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		c.Error(apperrors.NewAppError("IDEMPOTENCY_KEY_REUSED", "idempotency key was already used for a different request", http.StatusUnprocessableEntity, nil))
+		c.Abort()
+		return
+	}
+	if !record.Completed {
+		c.Error(apperrors.NewAppError("IDEMPOTENCY_KEY_IN_USE", "a request with this idempotency key is still being processed", http.StatusConflict, nil))
+		c.Abort()
+		return
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.StatusCode, record.ContentType, record.Body)
+	c.Abort()
+}
+
+// isValidIdempotencyKey reports whether key is a non-empty, bounded, printable ASCII string
+func isValidIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// fingerprintRequest hashes the method, path and body of a request and replaces the
+// body so that handlers can still read it. Multipart bodies are spooled to a temporary
+// file and hashed part by part, since clients pick a new boundary on every retry; JSON
+// bodies are hashed in canonical form. Bodies larger than maxBodySize fail with an
+// *http.MaxBytesError. cleanup must be called once the request is done.
+func fingerprintRequest(req *http.Request, maxBodySize int64) (string, func(), error) {
+	h := sha256.New()
+	io.WriteString(h, req.Method+" "+req.URL.Path+"\x00")
+	req.Body = http.MaxBytesReader(nil, req.Body, maxBodySize)
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" && params["boundary"] != "" {
+		return fingerprintMultipart(req, h, params["boundary"])
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", func() {}, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	h.Write(canonicalJSON(body))
+	return hex.EncodeToString(h.Sum(nil)), func() {}, nil
+}
+
+// fingerprintMultipart hashes the form names, filenames and contents of a multipart body
+func fingerprintMultipart(req *http.Request, h hash.Hash, boundary string) (string, func(), error) {
+	spool, err := os.CreateTemp("", "idempotency-*")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+
+	body := io.TeeReader(req.Body, spool)
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", cleanup, err
+		}
+		io.WriteString(h, part.FormName()+"\x00"+part.FileName()+"\x00")
+		if _, err := io.Copy(h, part); err != nil {
+			return "", cleanup, err
+		}
+		h.Write([]byte{0})
+	}
+	// Spool the epilogue as well so that the handler sees the complete body
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return "", cleanup, err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return "", cleanup, err
+	}
+	req.Body = spool
+	return hex.EncodeToString(h.Sum(nil)), cleanup, nil
+}
+
+// canonicalJSON re-encodes a JSON document with sorted keys and no insignificant
+// whitespace, returning other bodies unchanged
+func canonicalJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return body
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
+
+// responseRecorder captures the response body while writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write records and writes response bytes
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString records and writes a response string
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,389 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTodoBody    = `{"description":"Buy milk","dueDate":"2030-01-01T00:00:00Z"}`
+	testMaxBodySize = 1 << 20
+)
+
+// testFingerprint returns the fingerprint of a POST /api/todo request with the given body
+func testFingerprint(t *testing.T, body string) string {
+	req := httptest.NewRequest(http.MethodPost, "/api/todo", strings.NewReader(body))
+	fingerprint, cleanup, err := fingerprintRequest(req, testMaxBodySize)
+	require.NoError(t, err)
+	cleanup()
+	return fingerprint
+}
+
+func TestIdempotency(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		key             string
+		handler         gin.HandlerFunc
+		setupMocks      func(*mocks.MockIIdempotencyStore)
+		expectedStatus  int
+		expectedBody    string
+		expectReplayed  bool
+		expectedInvokes int
+	}{
+		{
+			name: "without key",
+			path: "/api/todo",
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				// No mocks needed, the middleware is skipped
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedInvokes: 1,
+		},
+		{
+			name: "route without idempotency",
+			path: "/api/todo/1/start",
+			key:  "key-1",
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				// No mocks needed, the middleware is skipped
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedInvokes: 1,
+		},
+		{
+			name: "first request stores the response",
+			path: "/api/todo",
+			key:  "key-1",
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				fingerprint := testFingerprint(t, testTodoBody)
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", fingerprint).Return(nil, nil)
+				store.On("Complete", mock.Anything, "POST /api/todo:key-1", &client.IdempotencyRecord{
+					Fingerprint: fingerprint,
+					StatusCode:  http.StatusCreated,
+					ContentType: "application/json; charset=utf-8",
+					Body:        []byte(`{"id":"todo-1"}`),
+				}).Return(nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedBody:    `{"id":"todo-1"}`,
+			expectedInvokes: 1,
+		},
+		{
+			name: "retry replays the stored response",
+			path: "/api/todo",
+			key:  "key-1",
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				fingerprint := testFingerprint(t, testTodoBody)
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", fingerprint).Return(&client.IdempotencyRecord{
+					Fingerprint: fingerprint,
+					Completed:   true,
+					StatusCode:  http.StatusCreated,
+					ContentType: "application/json; charset=utf-8",
+					Body:        []byte(`{"id":"todo-1"}`),
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"todo-1"}`,
+			expectReplayed: true,
+		},
+		{
+			name: "key reused with a different body",
+			path: "/api/todo",
+			key:  "key-1",
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", mock.Anything).Return(&client.IdempotencyRecord{
+					Fingerprint: testFingerprint(t, `{"description":"Other"}`),
+					Completed:   true,
+					StatusCode:  http.StatusCreated,
+				}, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "IDEMPOTENCY_KEY_REUSED",
+		},
+		{
+			name: "request still in progress",
+			path: "/api/todo",
+			key:  "key-1",
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", mock.Anything).Return(&client.IdempotencyRecord{
+					Fingerprint: testFingerprint(t, testTodoBody),
+				}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "IDEMPOTENCY_KEY_IN_USE",
+		},
+		{
+			name: "client errors are stored",
+			path: "/api/todo",
+			key:  "key-1",
+			handler: func(c *gin.Context) {
+				c.Error(apperrors.NewAppError("INVALID_DESCRIPTION", "description cannot be empty", http.StatusBadRequest, nil))
+			},
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", mock.Anything).Return(nil, nil)
+				store.On("Complete", mock.Anything, "POST /api/todo:key-1", mock.MatchedBy(func(record *client.IdempotencyRecord) bool {
+					return record.StatusCode == http.StatusBadRequest && strings.Contains(string(record.Body), "INVALID_DESCRIPTION")
+				})).Return(nil)
+			},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    `{"error":{"code":"INVALID_DESCRIPTION","message":"description cannot be empty"}}`,
+			expectedInvokes: 1,
+		},
+		{
+			name: "server errors release the key",
+			path: "/api/todo",
+			key:  "key-1",
+			handler: func(c *gin.Context) {
+				c.Error(errors.New("database error"))
+			},
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", mock.Anything).Return(nil, nil)
+				store.On("Release", mock.Anything, "POST /api/todo:key-1").Return(nil)
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedInvokes: 1,
+		},
+		{
+			name: "authorization errors release the key",
+			path: "/api/todo",
+			key:  "key-1",
+			handler: func(c *gin.Context) {
+				c.Error(apperrors.NewAppError("FORBIDDEN", "permission denied", http.StatusForbidden, nil))
+			},
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", mock.Anything).Return(nil, nil)
+				store.On("Release", mock.Anything, "POST /api/todo:key-1").Return(nil)
+			},
+			expectedStatus:  http.StatusForbidden,
+			expectedInvokes: 1,
+		},
+		{
+			name: "invalid key",
+			path: "/api/todo",
+			key:  strings.Repeat("k", 256),
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "INVALID_IDEMPOTENCY_KEY",
+		},
+		{
+			name: "store unavailable",
+			path: "/api/todo",
+			key:  "key-1",
+			setupMocks: func(store *mocks.MockIIdempotencyStore) {
+				store.On("Reserve", mock.Anything, "POST /api/todo:key-1", mock.Anything).Return(nil, errors.New("redis error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			store := mocks.NewMockIIdempotencyStore(t)
+			tt.setupMocks(store)
+
+			invokes := 0
+			handler := tt.handler
+			if handler == nil {
+				handler = func(c *gin.Context) {
+					c.JSON(http.StatusCreated, gin.H{"id": "todo-1"})
+				}
+			}
+			router := gin.New()
+			router.Use(errorHandler())
+			api := router.Group("/api")
+			api.POST("/todo", idempotency(store, testMaxBodySize), func(c *gin.Context) {
+				invokes++
+				body, _ := io.ReadAll(c.Request.Body)
+				assert.Equal(t, testTodoBody, string(body), "handler should see the original body")
+				handler(c)
+			})
+			api.POST("/todo/:id/start", func(c *gin.Context) {
+				invokes++
+				handler(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(testTodoBody))
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			if tt.expectReplayed {
+				assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+			} else {
+				assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+			}
+			assert.Equal(t, tt.expectedInvokes, invokes)
+		})
+	}
+}
+
+func TestFingerprintRequest(t *testing.T) {
+	t.Run("canonicalizes JSON bodies", func(t *testing.T) {
+		assert.Equal(t,
+			testFingerprint(t, `{"description":"Buy milk","dueDate":"2030-01-01T00:00:00Z"}`),
+			testFingerprint(t, "{\n  \"dueDate\": \"2030-01-01T00:00:00Z\",\n  \"description\": \"Buy milk\"\n}"),
+		)
+		assert.NotEqual(t,
+			testFingerprint(t, `{"description":"Buy milk"}`),
+			testFingerprint(t, `{"description":"Buy bread"}`),
+		)
+	})
+
+	t.Run("ignores the multipart boundary", func(t *testing.T) {
+		upload := func(boundary, content string) (*http.Request, []byte) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			require.NoError(t, writer.SetBoundary(boundary))
+			part, err := writer.CreateFormFile("file", "notes.txt")
+			require.NoError(t, err)
+			part.Write([]byte(content))
+			require.NoError(t, writer.Close())
+			raw := body.Bytes()
+			req := httptest.NewRequest(http.MethodPost, "/api/asset", bytes.NewReader(raw))
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			return req, raw
+		}
+
+		first, firstBody := upload("boundary-one", "hello")
+		firstFingerprint, cleanup, err := fingerprintRequest(first, testMaxBodySize)
+		require.NoError(t, err)
+		defer cleanup()
+		spooled, err := io.ReadAll(first.Body)
+		require.NoError(t, err)
+		assert.Equal(t, firstBody, spooled, "handler should see the original body")
+
+		second, _ := upload("boundary-two", "hello")
+		secondFingerprint, cleanup, err := fingerprintRequest(second, testMaxBodySize)
+		require.NoError(t, err)
+		defer cleanup()
+		assert.Equal(t, firstFingerprint, secondFingerprint)
+
+		third, _ := upload("boundary-one", "changed")
+		thirdFingerprint, cleanup, err := fingerprintRequest(third, testMaxBodySize)
+		require.NoError(t, err)
+		defer cleanup()
+		assert.NotEqual(t, firstFingerprint, thirdFingerprint)
+	})
+	t.Run("rejects oversized bodies", func(t *testing.T) {
+		body := bytes.Repeat([]byte("a"), testMaxBodySize+1)
+		req := httptest.NewRequest(http.MethodPost, "/api/todo", bytes.NewReader(body))
+		_, cleanup, err := fingerprintRequest(req, testMaxBodySize)
+		defer cleanup()
+		var tooLarge *http.MaxBytesError
+		assert.ErrorAs(t, err, &tooLarge)
+	})
+	t.Run("follows the upload size limit", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "video.bin")
+		require.NoError(t, err)
+		part.Write(bytes.Repeat([]byte("a"), 17<<20))
+		require.NoError(t, writer.Close())
+
+		for _, tt := range []struct {
+			name          string
+			maxUploadSize int64
+			wantTooLarge  bool
+		}{
+			{name: "default limit", maxUploadSize: 10 << 20, wantTooLarge: true},
+			{name: "raised limit", maxUploadSize: 20 << 20},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodPost, "/api/asset", bytes.NewReader(body.Bytes()))
+				req.Header.Set("Content-Type", writer.FormDataContentType())
+				_, cleanup, err := fingerprintRequest(req, tt.maxUploadSize+multipartOverhead)
+				defer cleanup()
+				if tt.wantTooLarge {
+					var tooLarge *http.MaxBytesError
+					assert.ErrorAs(t, err, &tooLarge)
+					return
+				}
+				assert.NoError(t, err)
+			})
+		}
+	})
+}
+
+func TestIdempotency_WithoutStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/todo", idempotency(nil, testMaxBodySize), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/todo", strings.NewReader(testTodoBody))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestKeepReserved(t *testing.T) {
+	store := mocks.NewMockIIdempotencyStore(t)
+	extended := make(chan struct{}, 1)
+	store.On("Extend", mock.Anything, "key-1").Return(nil).Run(func(mock.Arguments) {
+		select {
+		case extended <- struct{}{}:
+		default:
+		}
+	})
+
+	stop := keepReserved(context.Background(), store, "key-1", 10*time.Millisecond)
+	select {
+	case <-extended:
+	case <-time.After(time.Second):
+		t.Fatal("reservation was not extended")
+	}
+	stop()
+	stop()
+
+	calls := len(store.Calls)
+	time.Sleep(30 * time.Millisecond)
+	assert.Len(t, store.Calls, calls, "no extension after stop")
+}
+
+func TestIsStorableStatus(t *testing.T) {
+	assert.True(t, isStorableStatus(http.StatusCreated))
+	assert.True(t, isStorableStatus(http.StatusBadRequest))
+	assert.True(t, isStorableStatus(http.StatusUnprocessableEntity))
+	assert.False(t, isStorableStatus(http.StatusUnauthorized))
+	assert.False(t, isStorableStatus(http.StatusForbidden))
+	assert.False(t, isStorableStatus(http.StatusInternalServerError))
+	assert.False(t, isStorableStatus(http.StatusServiceUnavailable))
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "POST /api/todo:", idempotencyScope(ctx, "POST /api/todo"))
+
+	ctx = domain.WithPrincipal(ctx, &domain.Principal{ID: "user-1", Type: domain.PrincipalUser, TenantID: "acme"})
+	assert.Equal(t, "acme:user:user-1:POST /api/todo:", idempotencyScope(ctx, "POST /api/todo"))
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that did not match any route, so unknown paths do
+// not create new series
+const unmatchedRoute = "unmatched"
+
+// requestMetrics is a middleware that records the count and latency of requests by
+// route template and status
+func requestMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
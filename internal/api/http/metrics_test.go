@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authUseCase := usecase.NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), mocks.NewMockIRoleRepository(t), newTransactionManager(t), nil, "", nil)
+	router := NewHandler(nil, nil, authUseCase, nil, nil, nil, nil, 0).SetupRoutes()
+
+	for _, path := range []string{"/api/todo/123", "/unknown/123"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// The metrics endpoint is public and labels requests by route template
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `ice_http_requests_total{method="GET",route="/api/todo/:id",status="401"}`)
+	assert.Contains(t, w.Body.String(), `ice_http_requests_total{method="GET",route="unmatched",status="404"}`)
+	assert.NotContains(t, w.Body.String(), `route="/api/todo/123"`)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler handles admin requests for role assignments
+type RoleHandler struct {
+	authUseCase *usecase.AuthUseCase
+}
+
+// NewRoleHandler creates a new RoleHandler
+func NewRoleHandler(authUseCase *usecase.AuthUseCase) *RoleHandler {
+	return &RoleHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+// AssignRoleRequest represents the request body for assigning a role
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// RoleAssignmentResponse represents a role assignment
+type RoleAssignmentResponse struct {
+	PrincipalType string    `json:"principalType"`
+	PrincipalID   string    `json:"principalId"`
+	Role          string    `json:"role"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ListRoleAssignmentsResponse represents the list of role assignments
+type ListRoleAssignmentsResponse struct {
+	Items []RoleAssignmentResponse `json:"items"`
+}
+
+// newRoleAssignmentResponse maps a role assignment to its HTTP representation
+func newRoleAssignmentResponse(assignment *domain.RoleAssignment) RoleAssignmentResponse {
+	return RoleAssignmentResponse{
+		PrincipalType: string(assignment.PrincipalType),
+		PrincipalID:   assignment.PrincipalID,
+		Role:          string(assignment.Role),
+		UpdatedAt:     assignment.UpdatedAt,
+	}
+}
+
+// ListRoleAssignments handles GET /admin/roles requests
+func (h *RoleHandler) ListRoleAssignments(c *gin.Context) {
+	assignments, err := h.authUseCase.ListRoleAssignments(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := ListRoleAssignmentsResponse{Items: make([]RoleAssignmentResponse, 0, len(assignments))}
+	for _, assignment := range assignments {
+		response.Items = append(response.Items, newRoleAssignmentResponse(assignment))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// AssignRole handles PUT /admin/roles/:principalType/:principalId requests
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(newBindingError(err))
+		return
+	}
+	assignment, err := h.authUseCase.AssignRole(c.Request.Context(), usecase.AssignRoleRequest{
+		PrincipalType: domain.PrincipalType(c.Param("principalType")),
+		PrincipalID:   c.Param("principalId"),
+		Role:          domain.Role(req.Role),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newRoleAssignmentResponse(assignment))
+}
+
+// RemoveRole handles DELETE /admin/roles/:principalType/:principalId requests
+func (h *RoleHandler) RemoveRole(c *gin.Context) {
+	err := h.authUseCase.RemoveRole(c.Request.Context(), domain.PrincipalType(c.Param("principalType")), c.Param("principalId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterRoutes registers role assignment admin routes
+func (h *RoleHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/admin/roles", requirePermission(domain.PermissionRoleManage), h.ListRoleAssignments)
+	r.PUT("/admin/roles/:principalType/:principalId", requirePermission(domain.PermissionRoleManage), h.AssignRole)
+	r.DELETE("/admin/roles/:principalType/:principalId", requirePermission(domain.PermissionRoleManage), h.RemoveRole)
+}
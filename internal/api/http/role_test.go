@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRoleHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           domain.Role
+		method         string
+		path           string
+		body           string
+		setupMocks     func(*mocks.MockIRoleRepository)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "assign role",
+			role:   domain.RoleAdmin,
+			method: http.MethodPut,
+			path:   "/admin/roles/user/user-2",
+			body:   `{"role":"editor"}`,
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				roleRepo.On("Assign", mock.Anything, mock.MatchedBy(func(assignment *domain.RoleAssignment) bool {
+					return assignment.PrincipalID == "user-2" && assignment.Role == domain.RoleEditor
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"principalType":"user","principalId":"user-2","role":"editor"`,
+		},
+		{
+			name:   "assign unknown role",
+			role:   domain.RoleAdmin,
+			method: http.MethodPut,
+			path:   "/admin/roles/user/user-2",
+			body:   `{"role":"owner"}`,
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"INVALID_ROLE"`,
+		},
+		{
+			name:   "list assignments",
+			role:   domain.RoleAdmin,
+			method: http.MethodGet,
+			path:   "/admin/roles",
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				roleRepo.On("List", mock.Anything).Return([]*domain.RoleAssignment{
+					{PrincipalType: domain.PrincipalAPIKey, PrincipalID: "key-1", Role: domain.RoleViewer},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":[{"principalType":"api_key","principalId":"key-1","role":"viewer"`,
+		},
+		{
+			name:   "remove role",
+			role:   domain.RoleAdmin,
+			method: http.MethodDelete,
+			path:   "/admin/roles/api_key/key-1",
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				roleRepo.On("Delete", mock.Anything, domain.PrincipalAPIKey, "key-1").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "remove missing role",
+			role:   domain.RoleAdmin,
+			method: http.MethodDelete,
+			path:   "/admin/roles/api_key/key-1",
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				roleRepo.On("Delete", mock.Anything, domain.PrincipalAPIKey, "key-1").
+					Return(apperrors.NewAppError("ROLE_ASSIGNMENT_NOT_FOUND", "role assignment not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "editor cannot assign roles",
+			role:   domain.RoleEditor,
+			method: http.MethodPut,
+			path:   "/admin/roles/user/user-1",
+			body:   `{"role":"admin"}`,
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, authorization fails early
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `"code":"FORBIDDEN"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			roleRepo := mocks.NewMockIRoleRepository(t)
+			tt.setupMocks(roleRepo)
+
+			authUseCase := usecase.NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), roleRepo, newTransactionManager(t), nil, "", nil)
+			handler := NewRoleHandler(authUseCase)
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(tt.role))
+			handler.RegisterRoutes(router.Group(""))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
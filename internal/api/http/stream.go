@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// StreamHandler handles admin requests for stream statistics
+type StreamHandler struct {
+	streamUseCase *usecase.StreamUseCase
+}
+
+// NewStreamHandler creates a new StreamHandler
+func NewStreamHandler(streamUseCase *usecase.StreamUseCase) *StreamHandler {
+	return &StreamHandler{
+		streamUseCase: streamUseCase,
+	}
+}
+
+// StreamInfoResponse represents the length and consumer progress of a stream
+type StreamInfoResponse struct {
+	Stream  string                      `json:"stream"`
+	Length  int64                       `json:"length"`
+	FirstID string                      `json:"firstId,omitempty"`
+	LastID  string                      `json:"lastId,omitempty"`
+	Groups  []ConsumerGroupInfoResponse `json:"groups"`
+}
+
+// ConsumerGroupInfoResponse represents the progress of a consumer group
+type ConsumerGroupInfoResponse struct {
+	Name            string `json:"name"`
+	Consumers       int64  `json:"consumers"`
+	LastDeliveredID string `json:"lastDeliveredId"`
+	Pending         int64  `json:"pending"`
+	Lag             int64  `json:"lag"`
+}
+
+// GetStreamInfo handles GET /admin/streams/:stream requests
+func (h *StreamHandler) GetStreamInfo(c *gin.Context) {
+	info, err := h.streamUseCase.GetStreamInfo(c.Request.Context(), c.Param("stream"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := StreamInfoResponse{
+		Stream:  info.Stream,
+		Length:  info.Length,
+		FirstID: info.FirstID,
+		LastID:  info.LastID,
+		Groups:  make([]ConsumerGroupInfoResponse, 0, len(info.Groups)),
+	}
+	for _, group := range info.Groups {
+		response.Groups = append(response.Groups, ConsumerGroupInfoResponse{
+			Name:            group.Name,
+			Consumers:       group.Consumers,
+			LastDeliveredID: group.LastDeliveredID,
+			Pending:         group.Pending,
+			Lag:             group.Lag,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RegisterRoutes registers stream admin routes
+func (h *StreamHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/admin/streams/:stream", requirePermission(domain.PermissionStreamAdmin), h.GetStreamInfo)
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStreamHandler_GetStreamInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(*mocks.MockIStreamInspector)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "stream info",
+			path: "/admin/streams/todo-items",
+			setupMocks: func(inspector *mocks.MockIStreamInspector) {
+				inspector.On("Info", mock.Anything, "todo-items").Return(&client.StreamInfo{
+					Stream:  "todo-items",
+					Length:  3,
+					FirstID: "1-0",
+					LastID:  "3-0",
+					Groups: []client.ConsumerGroupInfo{
+						{Name: "todo-worker", Consumers: 2, LastDeliveredID: "2-0", Pending: 1, Lag: 1},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"groups":[{"name":"todo-worker","consumers":2,"lastDeliveredId":"2-0","pending":1,"lag":1}]`,
+		},
+		{
+			name: "empty stream",
+			path: "/admin/streams/todo-items",
+			setupMocks: func(inspector *mocks.MockIStreamInspector) {
+				inspector.On("Info", mock.Anything, "todo-items").
+					Return(&client.StreamInfo{Stream: "todo-items"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"stream":"todo-items","length":0,"groups":[]}`,
+		},
+		{
+			name: "unknown stream",
+			path: "/admin/streams/unknown",
+			setupMocks: func(inspector *mocks.MockIStreamInspector) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			inspector := mocks.NewMockIStreamInspector(t)
+			tt.setupMocks(inspector)
+
+			handler := NewStreamHandler(usecase.NewStreamUseCase(inspector))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(asOperator())
+			handler.RegisterRoutes(router.Group(""))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ar-agahian/ice-assignment/internal/domain"
 	"github.com/ar-agahian/ice-assignment/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
@@ -27,19 +28,72 @@ type CreateTodoRequest struct {
 	FileID      string    `json:"fileId,omitempty" binding:"omitempty,uuid"`
 }
 
+// ReplaceTodoRequest represents the request body for replacing a todo item
+type ReplaceTodoRequest struct {
+	Description string    `json:"description" binding:"required"`
+	DueDate     time.Time `json:"dueDate" binding:"required"`
+	FileID      string    `json:"fileId,omitempty" binding:"omitempty,uuid"`
+}
+
+// PatchTodoRequest represents the request body for partially updating a todo item
+type PatchTodoRequest struct {
+	Description *string    `json:"description,omitempty"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	FileID      *string    `json:"fileId,omitempty" binding:"omitempty,uuid"`
+}
+
+// ListTodosQuery represents the query parameters for listing todo items
+type ListTodosQuery struct {
+	DueBefore     *time.Time `form:"dueBefore"`
+	DueAfter      *time.Time `form:"dueAfter"`
+	CreatedBefore *time.Time `form:"createdBefore"`
+	CreatedAfter  *time.Time `form:"createdAfter"`
+	UpdatedBefore *time.Time `form:"updatedBefore"`
+	UpdatedAfter  *time.Time `form:"updatedAfter"`
+	HasAttachment *bool      `form:"hasAttachment"`
+	SortBy        string     `form:"sortBy" binding:"omitempty,oneof=dueDate createdAt"`
+	Order         string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Cursor        string     `form:"cursor"`
+	Limit         int        `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
 // TodoResponse represents the response for a todo item
 type TodoResponse struct {
-	ID          string    `json:"id"`
-	Description string    `json:"description"`
-	DueDate     time.Time `json:"dueDate"`
-	FileID      string    `json:"fileId,omitempty"`
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	DueDate     time.Time  `json:"dueDate"`
+	FileID      string     `json:"fileId,omitempty"`
+	Status      string     `json:"status"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// ListTodosResponse represents a page of todo items
+type ListTodosResponse struct {
+	Items      []TodoResponse `json:"items"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// newTodoResponse maps a domain todo item to its HTTP representation
+func newTodoResponse(item *domain.TodoItem) TodoResponse {
+	return TodoResponse{
+		ID:          item.ID.String(),
+		Description: item.Description,
+		DueDate:     item.DueDate,
+		FileID:      item.FileID,
+		Status:      string(item.Status),
+		CompletedAt: item.CompletedAt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
 }
 
 // CreateTodo handles POST /todo requests
 func (h *TodoHandler) CreateTodo(c *gin.Context) {
 	var req CreateTodoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(err)
+		c.Error(newBindingError(err))
 		return
 	}
 	todoItem, err := h.todoUseCase.CreateTodoItem(c.Request.Context(), usecase.CreateTodoItemRequest{
@@ -52,16 +106,146 @@ func (h *TodoHandler) CreateTodo(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, TodoResponse{
-		ID:          todoItem.ID.String(),
-		Description: todoItem.Description,
-		DueDate:     todoItem.DueDate,
-		FileID:      todoItem.FileID,
+	c.JSON(http.StatusCreated, newTodoResponse(todoItem))
+}
+
+// ListTodos handles GET /todo requests
+func (h *TodoHandler) ListTodos(c *gin.Context) {
+	var query ListTodosQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(newBindingError(err))
+		return
+	}
+	result, err := h.todoUseCase.ListTodoItems(c.Request.Context(), usecase.ListTodoItemsRequest{
+		DueBefore:     query.DueBefore,
+		DueAfter:      query.DueAfter,
+		CreatedBefore: query.CreatedBefore,
+		CreatedAfter:  query.CreatedAfter,
+		UpdatedBefore: query.UpdatedBefore,
+		UpdatedAfter:  query.UpdatedAfter,
+		HasAttachment: query.HasAttachment,
+		SortBy:        query.SortBy,
+		Descending:    query.Order == "desc",
+		Cursor:        query.Cursor,
+		Limit:         query.Limit,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := ListTodosResponse{
+		Items:      make([]TodoResponse, 0, len(result.Items)),
+		NextCursor: result.NextCursor,
+	}
+	for _, item := range result.Items {
+		response.Items = append(response.Items, newTodoResponse(item))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTodo handles GET /todo/:id requests
+func (h *TodoHandler) GetTodo(c *gin.Context) {
+	todoItem, err := h.todoUseCase.GetTodoItem(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTodoResponse(todoItem))
+}
+
+// ReplaceTodo handles PUT /todo/:id requests
+func (h *TodoHandler) ReplaceTodo(c *gin.Context) {
+	var req ReplaceTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(newBindingError(err))
+		return
+	}
+	todoItem, err := h.todoUseCase.UpdateTodoItem(c.Request.Context(), c.Param("id"), usecase.UpdateTodoItemRequest{
+		Description: &req.Description,
+		DueDate:     &req.DueDate,
+		FileID:      &req.FileID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTodoResponse(todoItem))
+}
+
+// PatchTodo handles PATCH /todo/:id requests
+func (h *TodoHandler) PatchTodo(c *gin.Context) {
+	var req PatchTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(newBindingError(err))
+		return
+	}
+	todoItem, err := h.todoUseCase.UpdateTodoItem(c.Request.Context(), c.Param("id"), usecase.UpdateTodoItemRequest{
+		Description: req.Description,
+		DueDate:     req.DueDate,
+		FileID:      req.FileID,
 	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTodoResponse(todoItem))
 }
 
-// RegisterRoutes registers todo routes
-func (h *TodoHandler) RegisterRoutes(r *gin.RouterGroup) {
-	r.POST("/todo", h.CreateTodo)
+// DeleteTodo handles DELETE /todo/:id requests
+func (h *TodoHandler) DeleteTodo(c *gin.Context) {
+	if err := h.todoUseCase.DeleteTodoItem(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CompleteTodo handles POST /todo/:id/complete requests
+func (h *TodoHandler) CompleteTodo(c *gin.Context) {
+	h.changeStatus(c, domain.TodoStatusDone)
 }
 
+// ReopenTodo handles POST /todo/:id/reopen requests
+func (h *TodoHandler) ReopenTodo(c *gin.Context) {
+	h.changeStatus(c, domain.TodoStatusOpen)
+}
+
+// StartTodo handles POST /todo/:id/start requests
+func (h *TodoHandler) StartTodo(c *gin.Context) {
+	h.changeStatus(c, domain.TodoStatusInProgress)
+}
+
+// CancelTodo handles POST /todo/:id/cancel requests
+func (h *TodoHandler) CancelTodo(c *gin.Context) {
+	h.changeStatus(c, domain.TodoStatusCancelled)
+}
+
+// changeStatus transitions the todo item identified by the id path parameter
+func (h *TodoHandler) changeStatus(c *gin.Context, status domain.TodoStatus) {
+	todoItem, err := h.todoUseCase.ChangeTodoStatus(c.Request.Context(), c.Param("id"), status)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTodoResponse(todoItem))
+}
+
+// RegisterRoutes registers todo routes. idempotent is attached to the create route.
+func (h *TodoHandler) RegisterRoutes(r *gin.RouterGroup, idempotent gin.HandlerFunc) {
+	r.POST("/todo", requirePermission(domain.PermissionTodoCreate), idempotent, h.CreateTodo)
+	r.GET("/todo", requirePermission(domain.PermissionTodoRead), h.ListTodos)
+	r.GET("/todo/:id", requirePermission(domain.PermissionTodoRead), h.GetTodo)
+	r.PUT("/todo/:id", requirePermission(domain.PermissionTodoUpdate), h.ReplaceTodo)
+	r.PATCH("/todo/:id", requirePermission(domain.PermissionTodoUpdate), h.PatchTodo)
+	r.DELETE("/todo/:id", requirePermission(domain.PermissionTodoDelete), h.DeleteTodo)
+	r.POST("/todo/:id/start", requirePermission(domain.PermissionTodoUpdate), h.StartTodo)
+	r.POST("/todo/:id/complete", requirePermission(domain.PermissionTodoUpdate), h.CompleteTodo)
+	r.POST("/todo/:id/cancel", requirePermission(domain.PermissionTodoUpdate), h.CancelTodo)
+	r.POST("/todo/:id/reopen", requirePermission(domain.PermissionTodoUpdate), h.ReopenTodo)
+}
@@ -2,45 +2,75 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
 	"github.com/ar-agahian/ice-assignment/internal/usecase"
 	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// newTransactionManager returns a transaction manager mock that runs fn directly
+func newTransactionManager(t *testing.T) *mocks.MockITransactionManager {
+	txManager := mocks.NewMockITransactionManager(t)
+	txManager.EXPECT().WithinTransaction(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		Maybe()
+	return txManager
+}
+
 func TestTodoHandler_CreateTodo(t *testing.T) {
 	tests := []struct {
 		name           string
 		requestBody    interface{}
-		setupMocks     func(*mocks.MockITodoRepository, *mocks.MockIStreamPublisher)
+		setupMocks     func(*mocks.MockITodoRepository, *mocks.MockIFileRepository, *mocks.MockIOutboxRepository)
 		expectedStatus int
+		expectedBody   string
 	}{
 		{
 			name: "successful creation",
 			requestBody: CreateTodoRequest{
 				Description: "Test todo",
 				DueDate:     time.Now().Add(24 * time.Hour),
-				FileID:      "file-123",
+				FileID:      "3f1c1f9e-8f7a-4b7e-9a55-0c2b8f6d2a10",
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				fileRepo.On("Exists", mock.Anything, "3f1c1f9e-8f7a-4b7e-9a55-0c2b8f6d2a10").Return(true, nil)
 				todoRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
-				streamRepo.On("Publish", mock.Anything, "todo-items", mock.Anything).Return(nil)
+				outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
 		},
+		{
+			name: "unknown file",
+			requestBody: CreateTodoRequest{
+				Description: "Test todo",
+				DueDate:     time.Now().Add(24 * time.Hour),
+				FileID:      "3f1c1f9e-8f7a-4b7e-9a55-0c2b8f6d2a10",
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				fileRepo.On("Exists", mock.Anything, "3f1c1f9e-8f7a-4b7e-9a55-0c2b8f6d2a10").Return(false, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 		{
 			name: "invalid request body",
 			requestBody: map[string]interface{}{
 				"invalid": "data",
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
 				// No mocks needed
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -51,24 +81,40 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 				Description: "",
 				DueDate:     time.Now().Add(24 * time.Hour),
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"fields":[{"field":"description","rule":"required","message":"is required"}]`,
+		},
+		{
+			name: "invalid file id",
+			requestBody: map[string]interface{}{
+				"description": "Test todo",
+				"dueDate":     time.Now().Add(24 * time.Hour),
+				"fileId":      "not-a-uuid",
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"fields":[{"field":"fileId","rule":"uuid","message":"must be a valid UUID"}]`,
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			todoRepo := mocks.NewMockITodoRepository(t)
-			streamRepo := mocks.NewMockIStreamPublisher(t)
-			tt.setupMocks(todoRepo, streamRepo)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo, fileRepo, outboxRepo)
 
-			todoUseCase := usecase.NewTodoUseCase(todoRepo, streamRepo)
+			todoUseCase := usecase.NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t))
 			handler := NewTodoHandler(todoUseCase)
 
 			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
 			router.POST("/todo", handler.CreateTodo)
 
 			body, _ := json.Marshal(tt.requestBody)
@@ -78,6 +124,311 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 
 			router.ServeHTTP(w, req)
 
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetTodo(t *testing.T) {
+	todoID := uuid.New()
+	tests := []struct {
+		name           string
+		id             string
+		setupMocks     func(*mocks.MockITodoRepository)
+		expectedStatus int
+	}{
+		{
+			name: "found",
+			id:   todoID.String(),
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				todoRepo.On("GetByID", mock.Anything, todoID.String()).Return(&domain.TodoItem{ID: todoID, Description: "Test todo"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			id:   todoID.String(),
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				todoRepo.On("GetByID", mock.Anything, todoID.String()).Return(nil, apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "invalid id",
+			id:   "not-a-uuid",
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				todoRepo.On("GetByID", mock.Anything, "not-a-uuid").Return(nil, apperrors.NewAppError("INVALID_ID", "invalid todo item id", http.StatusBadRequest, nil))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo)
+
+			handler := NewTodoHandler(usecase.NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t)))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			router.GET("/todo/:id", handler.GetTodo)
+
+			req := httptest.NewRequest("GET", "/todo/"+tt.id, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTodoHandler_UpdateTodo(t *testing.T) {
+	todoID := uuid.New()
+	tests := []struct {
+		name           string
+		method         string
+		requestBody    interface{}
+		setupMocks     func(*mocks.MockITodoRepository, *mocks.MockIOutboxRepository)
+		expectedStatus int
+	}{
+		{
+			name:   "successful replace",
+			method: "PUT",
+			requestBody: ReplaceTodoRequest{
+				Description: "Replaced todo",
+				DueDate:     time.Now().Add(48 * time.Hour),
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(&domain.TodoItem{ID: todoID, Description: "Test todo"}, nil)
+				todoRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.TodoItem")).Return(nil)
+				outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "replace missing description",
+			method: "PUT",
+			requestBody: map[string]interface{}{
+				"dueDate": time.Now().Add(48 * time.Hour),
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "successful patch",
+			method: "PATCH",
+			requestBody: map[string]interface{}{
+				"description": "Patched todo",
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(&domain.TodoItem{ID: todoID, Description: "Test todo"}, nil)
+				todoRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.TodoItem")).Return(nil)
+				outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "patch not found",
+			method: "PATCH",
+			requestBody: map[string]interface{}{
+				"description": "Patched todo",
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(nil, apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo, outboxRepo)
+
+			handler := NewTodoHandler(usecase.NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t)))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			router.PUT("/todo/:id", handler.ReplaceTodo)
+			router.PATCH("/todo/:id", handler.PatchTodo)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(tt.method, "/todo/"+todoID.String(), bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTodoHandler_DeleteTodo(t *testing.T) {
+	todoID := uuid.New()
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockITodoRepository, *mocks.MockIOutboxRepository)
+		expectedStatus int
+	}{
+		{
+			name: "successful deletion",
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("Delete", mock.Anything, todoID.String()).Return(nil)
+				outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("Delete", mock.Anything, todoID.String()).Return(apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo, outboxRepo)
+
+			handler := NewTodoHandler(usecase.NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t)))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			router.DELETE("/todo/:id", handler.DeleteTodo)
+
+			req := httptest.NewRequest("DELETE", "/todo/"+todoID.String(), nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTodoHandler_ListTodos(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*mocks.MockITodoRepository)
+		expectedStatus int
+	}{
+		{
+			name:  "successful listing with filters",
+			query: "?hasAttachment=true&sortBy=createdAt&order=desc&limit=10&dueAfter=2024-01-01T00:00:00Z",
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				todoRepo.On("List", mock.Anything, mock.MatchedBy(func(q repository.ListQuery) bool {
+					return q.HasAttachment != nil && *q.HasAttachment &&
+						q.SortBy == repository.SortByCreatedAt && q.Descending &&
+						q.DueAfter != nil && q.Limit == 11
+				})).Return([]*domain.TodoItem{{ID: uuid.New(), Description: "Test todo"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "invalid sort field",
+			query: "?sortBy=description",
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid date",
+			query: "?dueBefore=tomorrow",
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo)
+
+			handler := NewTodoHandler(usecase.NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t)))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			router.GET("/todo", handler.ListTodos)
+
+			req := httptest.NewRequest("GET", "/todo"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTodoHandler_ChangeStatus(t *testing.T) {
+	todoID := uuid.New()
+	tests := []struct {
+		name           string
+		action         string
+		current        domain.TodoStatus
+		expectedStatus int
+	}{
+		{name: "complete open item", action: "complete", current: domain.TodoStatusOpen, expectedStatus: http.StatusOK},
+		{name: "reopen done item", action: "reopen", current: domain.TodoStatusDone, expectedStatus: http.StatusOK},
+		{name: "start open item", action: "start", current: domain.TodoStatusOpen, expectedStatus: http.StatusOK},
+		{name: "cancel in-progress item", action: "cancel", current: domain.TodoStatusInProgress, expectedStatus: http.StatusOK},
+		{name: "complete cancelled item", action: "complete", current: domain.TodoStatusCancelled, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(&domain.TodoItem{ID: todoID, Status: tt.current}, nil)
+			if tt.expectedStatus == http.StatusOK {
+				todoRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.TodoItem")).Return(nil)
+				outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(nil)
+			}
+
+			handler := NewTodoHandler(usecase.NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t)))
+
+			router := gin.New()
+			router.Use(errorHandler())
+			router.Use(withRole(domain.RoleAdmin))
+			handler.RegisterRoutes(router.Group(""), idempotency(nil, 0))
+
+			req := httptest.NewRequest("POST", "/todo/"+todoID.String()+"/"+tt.action, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
 			assert.Equal(t, tt.expectedStatus, w.Code)
 		})
 	}
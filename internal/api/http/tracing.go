@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer starts the server spans of HTTP requests
+var tracer = otel.Tracer("github.com/ar-agahian/ice-assignment/internal/api/http")
+
+// traceRequests is a middleware that runs each request in a server span, continuing
+// the trace of the W3C traceparent header when the client sent one
+func traceRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		name := c.Request.Method
+		attributes := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(c.Request.Method),
+			semconv.URLPath(c.Request.URL.Path),
+		}
+		if route := c.FullPath(); route != "" {
+			name += " " + route
+			attributes = append(attributes, semconv.HTTPRoute(route))
+		}
+		ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attributes...))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			if len(c.Errors) > 0 {
+				span.RecordError(c.Errors.Last().Err)
+			}
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
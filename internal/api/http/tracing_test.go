@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceRequests(t *testing.T) {
+	tests := []struct {
+		name            string
+		traceParent     string
+		expectedTraceID string
+	}{
+		{
+			name:            "continues the client's trace",
+			traceParent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:        "malformed traceparent is ignored",
+			traceParent: "00-invalid",
+		},
+	}
+
+	propagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagator) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			var traceID string
+			router := gin.New()
+			router.Use(traceRequests())
+			router.GET("/todo/:id", func(c *gin.Context) {
+				if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+					traceID = spanContext.TraceID().String()
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/todo/123", nil)
+			req.Header.Set("traceparent", tt.traceParent)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedTraceID, traceID)
+		})
+	}
+}
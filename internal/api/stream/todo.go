@@ -0,0 +1,130 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/pkg/cloudevents"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TodoItemsStream is the stream todo events are published to
+const TodoItemsStream = "todo-items"
+
+// tracer starts the spans of processed messages
+var tracer = otel.Tracer("github.com/ar-agahian/ice-assignment/internal/api/stream")
+
+// todoEventRetryPolicy retries a failing todo event five times before dead-lettering it
+var todoEventRetryPolicy = client.RetryPolicy{
+	MaxDeliveries:  5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+}
+
+// TodoEventHandler handles messages from the todo-items stream
+type TodoEventHandler struct{}
+
+// NewTodoEventHandler creates a new TodoEventHandler
+func NewTodoEventHandler() *TodoEventHandler {
+	return &TodoEventHandler{}
+}
+
+// RegisterHandlers registers the handler's streams on the consumer
+func (h *TodoEventHandler) RegisterHandlers(consumer client.IStreamConsumer) {
+	consumer.Handle(TodoItemsStream, h.HandleTodoEvent, todoEventRetryPolicy)
+}
+
+// HandleTodoEvent decodes a todo event and logs it in a span continuing the event's
+// trace. Unknown event types are skipped so that new producers do not break this consumer.
+func (h *TodoEventHandler) HandleTodoEvent(ctx context.Context, msg *client.StreamMessage) (err error) {
+	event, err := decodeEvent(msg)
+	if err != nil {
+		return err
+	}
+	_, span := tracer.Start(tracing.Extract(ctx, event.TraceParent, event.TraceState), "process "+TodoItemsStream,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingDestinationName(TodoItemsStream),
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingMessageID(msg.ID),
+			semconv.CloudEventsEventType(string(event.Type)),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+	if event.SchemaVersion > domain.EventSchemaVersion {
+		return fmt.Errorf("message %s: unsupported %s schema version %d", msg.ID, event.Type, event.SchemaVersion)
+	}
+
+	switch event.Type {
+	case domain.EventTodoCreated:
+		var payload domain.TodoCreatedPayload
+		if err := event.DecodePayload(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		log.Printf("todo created: id=%s dueDate=%s correlationId=%s", payload.ID, payload.DueDate.Format(time.RFC3339), event.CorrelationID)
+	case domain.EventTodoUpdated:
+		var payload domain.TodoUpdatedPayload
+		if err := event.DecodePayload(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		log.Printf("todo updated: id=%s correlationId=%s", payload.ID, event.CorrelationID)
+	case domain.EventTodoDeleted:
+		var payload domain.TodoDeletedPayload
+		if err := event.DecodePayload(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		log.Printf("todo deleted: id=%s correlationId=%s", payload.ID, event.CorrelationID)
+	case domain.EventTodoStatusChanged:
+		var payload domain.TodoStatusChangedPayload
+		if err := event.DecodePayload(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		log.Printf("todo status changed: id=%s %s -> %s correlationId=%s", payload.ID, payload.PreviousStatus, payload.Status, event.CorrelationID)
+	default:
+		log.Printf("skipping unknown event type %q in message %s", event.Type, msg.ID)
+	}
+	return nil
+}
+
+// decodeEvent decodes the event envelope of a message written in any of the stream
+// publisher's encodings: a plain "data" field or CloudEvents structured/binary mode
+func decodeEvent(msg *client.StreamMessage) (*domain.Event, error) {
+	ce, ok, err := cloudevents.Decode(msg.Values)
+	if err != nil {
+		return nil, fmt.Errorf("decode message %s: %w", msg.ID, err)
+	}
+	if ok {
+		event := &domain.Event{
+			ID:            ce.ID,
+			Type:          domain.EventType(ce.Type),
+			SchemaVersion: ce.SchemaVersion,
+			TenantID:      ce.TenantID,
+			CorrelationID: ce.CorrelationID,
+			TraceParent:   ce.TraceParent,
+			TraceState:    ce.TraceState,
+			Payload:       ce.Data,
+		}
+		if ce.Time != nil {
+			event.OccurredAt = *ce.Time
+		}
+		return event, nil
+	}
+
+	raw, ok := msg.Values["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("message %s has no data field", msg.ID)
+	}
+	var event domain.Event
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		return nil, fmt.Errorf("decode message %s: %w", msg.ID, err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,102 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleTodoEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:   "created event",
+			values: map[string]interface{}{"data": `{"id":"e1","type":"todo.created","schemaVersion":1,"payload":{"id":"1","description":"test","dueDate":"2024-01-01T00:00:00Z"}}`},
+		},
+		{
+			name:   "status changed event",
+			values: map[string]interface{}{"data": `{"id":"e2","type":"todo.status_changed","schemaVersion":1,"payload":{"id":"1","previousStatus":"open","status":"done"}}`},
+		},
+		{
+			name: "cloudevents binary event",
+			values: map[string]interface{}{
+				"ce_specversion":   "1.0",
+				"ce_id":            "e6",
+				"ce_source":        "/ice-assignment",
+				"ce_type":          "todo.deleted",
+				"ce_schemaversion": "1",
+				"content-type":     "application/json",
+				"data":             `{"id":"1"}`,
+			},
+		},
+		{
+			name: "cloudevents structured event",
+			values: map[string]interface{}{
+				"content-type": "application/cloudevents+json",
+				"data":         `{"specversion":"1.0","id":"e7","source":"/ice-assignment","type":"todo.deleted","schemaversion":1,"data":{"id":"1"}}`,
+			},
+		},
+		{
+			name: "invalid cloudevent",
+			values: map[string]interface{}{
+				"content-type": "application/cloudevents+json",
+				"data":         `{"specversion":"1.0","type":"todo.deleted"}`,
+			},
+			expectError: true,
+		},
+		{
+			name:   "unknown event type is skipped",
+			values: map[string]interface{}{"data": `{"id":"e3","type":"todo.archived","schemaVersion":1,"payload":{}}`},
+		},
+		{
+			name:        "newer schema version",
+			values:      map[string]interface{}{"data": `{"id":"e4","type":"todo.created","schemaVersion":2,"payload":{}}`},
+			expectError: true,
+		},
+		{
+			name:        "malformed payload",
+			values:      map[string]interface{}{"data": `{"id":"e5","type":"todo.deleted","schemaVersion":1,"payload":{"id":1}}`},
+			expectError: true,
+		},
+		{
+			name:        "missing data field",
+			values:      map[string]interface{}{"other": "value"},
+			expectError: true,
+		},
+		{
+			name:        "malformed data",
+			values:      map[string]interface{}{"data": "{not json"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTodoEventHandler()
+			err := h.HandleTodoEvent(context.Background(), &client.StreamMessage{
+				ID:     "1-0",
+				Stream: TodoItemsStream,
+				Values: tt.values,
+			})
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	consumer := mocks.NewMockIStreamConsumer(t)
+	consumer.On("Handle", TodoItemsStream, mock.AnythingOfType("client.MessageHandler"), todoEventRetryPolicy).Return()
+
+	NewTodoEventHandler().RegisterHandlers(consumer)
+}
@@ -2,67 +2,253 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 
 	httphandler "github.com/ar-agahian/ice-assignment/internal/api/http"
+	"github.com/ar-agahian/ice-assignment/internal/api/stream"
+	"github.com/ar-agahian/ice-assignment/internal/config"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/jwks"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/kafka"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/memory"
 	"github.com/ar-agahian/ice-assignment/internal/infrastructure/mysql"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/nats"
 	"github.com/ar-agahian/ice-assignment/internal/infrastructure/redis"
 	"github.com/ar-agahian/ice-assignment/internal/infrastructure/s3"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
 	"github.com/ar-agahian/ice-assignment/internal/usecase"
 	"gorm.io/gorm"
 )
 
+// StreamPublisher is a stream publisher that owns a broker connection
+type StreamPublisher interface {
+	client.IStreamPublisher
+	Close() error
+}
+
 // App holds all application dependencies
 type App struct {
 	DB              *gorm.DB
 	TodoUseCase     *usecase.TodoUseCase
 	FileUseCase     *usecase.FileUseCase
 	Handler         *httphandler.Handler
-	StreamPublisher *redis.StreamPublisher
+	StreamPublisher StreamPublisher
+	OutboxRelay     *usecase.OutboxRelay
+	// StreamTrimmer is nil unless the Redis backend is used
+	StreamTrimmer    *redis.StreamTrimmer
+	IdempotencyStore *redis.IdempotencyStore
+
+	// closers release resources in the order they were opened
+	closers     []namedCloser
+	workers     sync.WaitGroup
+	stopWorkers context.CancelFunc
 }
 
-// NewApp initializes all application dependencies
-func NewApp() (*App, error) {
+// namedCloser releases one application resource
+type namedCloser struct {
+	name  string
+	close func() error
+}
+
+// NewApp initializes all application dependencies. Resources opened before a failure
+// are closed again.
+func NewApp(cfg *config.Config) (_ *App, err error) {
+	a := &App{}
+	defer func() {
+		if err != nil {
+			a.Close()
+		}
+	}()
+
 	// database
-	db, err := mysql.NewDatabase()
+	db, err := mysql.NewDatabase(cfg.Database)
 	if err != nil {
 		return nil, err
 	}
+	a.addCloser("database", func() error { return mysql.CloseDatabase(db) })
 	if err := mysql.RunMigrations(db); err != nil {
 		return nil, err
 	}
+	if err := mysql.RegisterMetrics(db); err != nil {
+		return nil, err
+	}
+	if err := mysql.RegisterTracing(db); err != nil {
+		return nil, err
+	}
 
 	// repositories
 	todoRepo := mysql.NewTodoRepository(db)
+	fileRepo := mysql.NewFileRepository(db)
+	outboxRepo := mysql.NewOutboxRepository(db)
+	txManager := mysql.NewTransactionManager(db)
+	apiKeyRepo := mysql.NewAPIKeyRepository(db)
+	roleRepo := mysql.NewRoleRepository(db)
 
 	// infrastructure clients
 	ctx := context.Background()
-	s3Storage, err := s3.NewFileStorage(ctx)
+	s3Storage, err := s3.NewFileStorage(ctx, cfg.S3)
+	if err != nil {
+		return nil, err
+	}
+
+	streamPublisher, err := newStreamPublisher(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	a.addCloser("stream publisher", streamPublisher.Close)
+
+	idempotencyStore, err := redis.NewIdempotencyStore(ctx, cfg.Redis, cfg.Idempotency.TTL)
 	if err != nil {
 		return nil, err
 	}
+	a.addCloser("idempotency store", idempotencyStore.Close)
 
-	streamPublisher, err := redis.NewStreamPublisher(ctx)
+	tokenVerifier, err := newTokenVerifier(ctx, cfg.Auth)
 	if err != nil {
 		return nil, err
 	}
 
 	// usecases
-	todoUseCase := usecase.NewTodoUseCase(todoRepo, streamPublisher)
-	fileUseCase := usecase.NewFileUseCase(s3Storage)
+	todoUseCase := usecase.NewTodoUseCase(todoRepo, fileRepo, outboxRepo, txManager)
+	fileUseCase := usecase.NewFileUseCase(s3Storage, fileRepo, cfg.Upload)
+	authUseCase := usecase.NewAuthUseCase(apiKeyRepo, roleRepo, txManager, tokenVerifier, cfg.Auth.DefaultRole, cfg.Auth.Operators)
+	outboxRelay := usecase.NewOutboxRelay(outboxRepo, txManager, streamPublisher)
+	healthCheckers := map[string]client.IHealthChecker{
+		"mysql": mysql.NewHealthChecker(db),
+		"redis": idempotencyStore,
+		"s3":    s3Storage,
+	}
+	// the in-memory backend has nothing to check
+	if checker, ok := streamPublisher.(client.IHealthChecker); ok {
+		healthCheckers["stream"] = checker
+	}
+	healthUseCase := usecase.NewHealthUseCase(healthCheckers, cfg.Server.HealthCheckTimeout)
+	// dead-letter queues, retention and stream statistics are only supported on Redis Streams
+	var deadLetterUseCase *usecase.DeadLetterUseCase
+	var streamUseCase *usecase.StreamUseCase
+	var streamTrimmer *redis.StreamTrimmer
+	if redisPublisher, ok := streamPublisher.(*redis.StreamPublisher); ok {
+		deadLetterUseCase = usecase.NewDeadLetterUseCase(redis.NewDeadLetterStore(redisPublisher))
+		streamInspector := redis.NewStreamInspector(redisPublisher)
+		streamUseCase = usecase.NewStreamUseCase(streamInspector)
+		if err := redis.RegisterStreamMetrics(streamInspector, []string{stream.TodoItemsStream}); err != nil {
+			return nil, err
+		}
+		streamTrimmer, err = newStreamTrimmer(redisPublisher, cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// http-handler
-	handler := httphandler.NewHandler(todoUseCase, fileUseCase)
-
-	return &App{
-		DB:              db,
-		TodoUseCase:     todoUseCase,
-		FileUseCase:     fileUseCase,
-		Handler:         handler,
-		StreamPublisher: streamPublisher,
-	}, nil
+	handler := httphandler.NewHandler(todoUseCase, fileUseCase, authUseCase, deadLetterUseCase, streamUseCase, healthUseCase, idempotencyStore, fileUseCase.MaxFileSize())
+
+	a.DB = db
+	a.TodoUseCase = todoUseCase
+	a.FileUseCase = fileUseCase
+	a.Handler = handler
+	a.StreamPublisher = streamPublisher
+	a.OutboxRelay = outboxRelay
+	a.StreamTrimmer = streamTrimmer
+	a.IdempotencyStore = idempotencyStore
+	return a, nil
 }
 
-// Close closes all application resources
-func (a *App) Close() error {
+// newStreamPublisher creates the stream publisher for the configured backend
+func newStreamPublisher(ctx context.Context, cfg *config.Config) (StreamPublisher, error) {
+	switch cfg.Stream.Backend {
+	case "", config.StreamBackendRedis:
+		return redis.NewStreamPublisher(ctx, cfg.Redis)
+	case config.StreamBackendMemory:
+		return memory.NewStreamPublisher(0), nil
+	case config.StreamBackendNATS:
+		return nats.NewStreamPublisher(ctx, cfg.Stream.NATS)
+	case config.StreamBackendKafka:
+		return kafka.NewStreamPublisher(ctx, cfg.Stream.Kafka)
+	default:
+		return nil, fmt.Errorf("unknown stream backend %q", cfg.Stream.Backend)
+	}
+}
+
+// newStreamTrimmer creates a trimmer for the configured retention policies, or returns
+// nil when no policy is configured
+func newStreamTrimmer(publisher *redis.StreamPublisher, cfg redis.Config) (*redis.StreamTrimmer, error) {
+	policies, err := redis.ParseRetentionPolicies(cfg.StreamRetention)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	return redis.NewStreamTrimmer(publisher, policies, cfg.StreamTrimInterval), nil
+}
+
+// newTokenVerifier creates a verifier for JWTs signed with keys from the configured JWKS,
+// or returns nil when JWT authentication is not configured
+func newTokenVerifier(ctx context.Context, cfg config.AuthConfig) (client.ITokenVerifier, error) {
+	if cfg.JWKS == "" {
+		return nil, nil
+	}
+	return jwks.NewTokenVerifier(ctx, cfg.JWKS, cfg.JWTIssuer, cfg.JWTAudience)
+}
+
+// Start launches background workers that run until ctx is cancelled or Stop is called
+func (a *App) Start(ctx context.Context) {
+	ctx, a.stopWorkers = context.WithCancel(ctx)
+	a.goWorker(func() { a.OutboxRelay.Run(ctx) })
+	if a.StreamTrimmer != nil {
+		a.goWorker(func() { a.StreamTrimmer.Run(ctx) })
+	}
+}
+
+// goWorker runs fn in a goroutine tracked by Stop
+func (a *App) goWorker(fn func()) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		fn()
+	}()
+}
+
+// Stop cancels the background workers, waits for them to return and then relays the
+// messages still in the outbox, giving up when ctx expires
+func (a *App) Stop(ctx context.Context) error {
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+	}
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("background workers did not stop: %w", ctx.Err())
+	}
+	if a.OutboxRelay == nil {
+		return nil
+	}
+	if err := a.OutboxRelay.Flush(ctx); err != nil {
+		return fmt.Errorf("flush outbox: %w", err)
+	}
 	return nil
 }
+
+// addCloser registers a resource to be released by Close
+func (a *App) addCloser(name string, close func() error) {
+	a.closers = append(a.closers, namedCloser{name: name, close: close})
+}
+
+// Close releases all application resources in the reverse order of their creation
+// and returns the errors of every resource that failed to close. Calling Close again
+// is a no-op.
+func (a *App) Close() error {
+	var errs []error
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		closer := a.closers[i]
+		if err := closer.close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", closer.name, err))
+		}
+	}
+	a.closers = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_Close(t *testing.T) {
+	var closed []string
+	a := &App{}
+	for _, name := range []string{"database", "stream publisher", "idempotency store"} {
+		a.addCloser(name, func() error {
+			closed = append(closed, name)
+			if name == "idempotency store" || name == "database" {
+				return errors.New("connection reset")
+			}
+			return nil
+		})
+	}
+
+	err := a.Close()
+
+	assert.Equal(t, []string{"idempotency store", "stream publisher", "database"}, closed)
+	assert.EqualError(t, err, "close idempotency store: connection reset\nclose database: connection reset")
+	assert.NoError(t, a.Close())
+	assert.Len(t, closed, 3)
+}
+
+func TestApp_Stop(t *testing.T) {
+	tests := []struct {
+		name          string
+		worker        func(ctx context.Context)
+		expectedError string
+	}{
+		{
+			name: "waits for workers",
+			worker: func(ctx context.Context) {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+			},
+		},
+		{
+			name: "gives up on stuck workers",
+			worker: func(ctx context.Context) {
+				time.Sleep(time.Second)
+			},
+			expectedError: "background workers did not stop: context deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{}
+			ctx, cancel := context.WithCancel(context.Background())
+			a.stopWorkers = cancel
+			finished := false
+			a.goWorker(func() {
+				tt.worker(ctx)
+				finished = true
+			})
+
+			stopCtx, stopCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer stopCancel()
+			err := a.Stop(stopCtx)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, finished)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/kafka"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/mysql"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/nats"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/redis"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/s3"
+	"github.com/ar-agahian/ice-assignment/internal/usecase"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+)
+
+// Stream backends selectable with STREAM_BACKEND
+const (
+	StreamBackendRedis  = "redis"
+	StreamBackendMemory = "memory"
+	StreamBackendNATS   = "nats"
+	StreamBackendKafka  = "kafka"
+)
+
+// Config is the complete application configuration. Each field is read from the
+// environment variable in its env tag or the YAML key in its yaml tag, falling back
+// to the value in its default tag.
+type Config struct {
+	Server      ServerConfig         `yaml:"server"`
+	Database    mysql.Config         `yaml:"database"`
+	Redis       redis.Config         `yaml:"redis"`
+	Stream      StreamConfig         `yaml:"stream"`
+	S3          s3.Config            `yaml:"s3"`
+	Upload      usecase.UploadConfig `yaml:"upload"`
+	Auth        AuthConfig           `yaml:"auth"`
+	Idempotency IdempotencyConfig    `yaml:"idempotency"`
+	Worker      WorkerConfig         `yaml:"worker"`
+	Tracing     tracing.Config       `yaml:"tracing"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port int `yaml:"port" env:"SERVER_PORT" default:"8080"`
+	// ShutdownTimeout bounds how long in-flight requests are drained
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SERVER_SHUTDOWN_TIMEOUT" default:"30s"`
+	// WorkerStopTimeout bounds how long background workers are stopped and the outbox
+	// is flushed once requests are drained
+	WorkerStopTimeout time.Duration `yaml:"workerStopTimeout" env:"SERVER_WORKER_STOP_TIMEOUT" default:"10s"`
+	// HealthCheckTimeout bounds each dependency check of the readiness probe
+	HealthCheckTimeout time.Duration `yaml:"healthCheckTimeout" env:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+}
+
+// StreamConfig selects the stream backend events are published to
+type StreamConfig struct {
+	Backend string       `yaml:"backend" env:"STREAM_BACKEND" default:"redis"`
+	NATS    nats.Config  `yaml:"nats"`
+	Kafka   kafka.Config `yaml:"kafka"`
+}
+
+// AuthConfig configures JWT verification, the role of principals without an assignment
+// and the operators of the deployment
+type AuthConfig struct {
+	// JWKS is a file path or URL of the JSON Web Key Set; JWTs are rejected when empty
+	JWKS        string      `yaml:"jwks" env:"AUTH_JWKS"`
+	JWTIssuer   string      `yaml:"jwtIssuer" env:"AUTH_JWT_ISSUER"`
+	JWTAudience string      `yaml:"jwtAudience" env:"AUTH_JWT_AUDIENCE"`
+	DefaultRole domain.Role `yaml:"defaultRole" env:"AUTH_DEFAULT_ROLE"`
+	// Operators lists the "user:<sub>" and "api_key:<id>" principals allowed to manage the
+	// streams and dead-letter queues shared by all tenants
+	Operators []string `yaml:"operators" env:"AUTH_OPERATORS"`
+}
+
+// IdempotencyConfig configures the storage of Idempotency-Key responses
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" default:"24h"`
+}
+
+// WorkerConfig configures the stream consumer of cmd/worker
+type WorkerConfig struct {
+	Group string `yaml:"group" env:"WORKER_GROUP" default:"todo-worker"`
+	// Consumer names this instance within the group and defaults to the hostname
+	Consumer    string `yaml:"consumer" env:"WORKER_CONSUMER"`
+	Concurrency int    `yaml:"concurrency" env:"WORKER_CONCURRENCY"`
+}
+
+// Validate checks the configuration of the server and reports every invalid setting at once
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.check(c.Server.Port > 0 && c.Server.Port <= 65535, "SERVER_PORT (server.port) must be between 1 and 65535")
+	v.check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT (server.shutdownTimeout) must be positive")
+	v.check(c.Server.WorkerStopTimeout > 0, "SERVER_WORKER_STOP_TIMEOUT (server.workerStopTimeout) must be positive")
+	v.check(c.Server.HealthCheckTimeout > 0, "HEALTH_CHECK_TIMEOUT (server.healthCheckTimeout) must be positive")
+
+	v.check(c.Database.User != "", "DB_USER (database.user) is required")
+	v.check(c.Database.Host != "", "DB_HOST (database.host) is required")
+	v.check(c.Database.Name != "", "DB_NAME (database.name) is required")
+	v.check(c.Database.Port > 0 && c.Database.Port <= 65535, "DB_PORT (database.port) must be between 1 and 65535")
+	v.check(c.Database.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS (database.maxOpenConns) must be positive")
+	v.check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS (database.maxIdleConns) must be between 0 and DB_MAX_OPEN_CONNS")
+	v.check(c.Database.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME (database.connMaxLifetime) must not be negative")
+
+	// Redis also backs the idempotency store, so it is needed with every stream backend
+	c.validateRedis(v)
+
+	switch c.Stream.Backend {
+	case StreamBackendRedis, StreamBackendMemory:
+	case StreamBackendNATS:
+		v.check(c.Stream.NATS.URL != "", "NATS_URL (stream.nats.url) is required for the nats backend")
+	case StreamBackendKafka:
+		v.check(len(c.Stream.Kafka.Brokers) > 0, "KAFKA_BROKERS (stream.kafka.brokers) is required for the kafka backend")
+	default:
+		v.add("STREAM_BACKEND (stream.backend) must be one of redis, memory, nats or kafka, got %q", c.Stream.Backend)
+	}
+
+	v.check(c.S3.Bucket != "", "S3_BUCKET_NAME (s3.bucket) is required")
+
+	v.check(c.Upload.MaxSize >= 0, "UPLOAD_MAX_SIZE (upload.maxSize) must not be negative")
+
+	v.check(c.Auth.DefaultRole == "" || c.Auth.DefaultRole.IsValid(),
+		"AUTH_DEFAULT_ROLE (auth.defaultRole) must be viewer, editor or admin")
+	for _, ref := range c.Auth.Operators {
+		if !domain.IsValidPrincipalRef(ref) {
+			v.add("AUTH_OPERATORS (auth.operators) must list user:<id> or api_key:<id> principals, got %q", ref)
+		}
+	}
+
+	v.check(c.Idempotency.TTL > 0, "IDEMPOTENCY_TTL (idempotency.ttl) must be positive")
+
+	c.validateWorker(v)
+	c.validateTracing(v)
+
+	return v.err()
+}
+
+// ValidateWorker checks the settings cmd/worker uses: Redis, the consumer, tracing and
+// the shutdown timeout. The database, S3 and the HTTP server are not needed.
+func (c *Config) ValidateWorker() error {
+	v := &validator{}
+
+	v.check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT (server.shutdownTimeout) must be positive")
+	c.validateRedis(v)
+	c.validateWorker(v)
+	c.validateTracing(v)
+
+	return v.err()
+}
+
+// validateRedis checks the Redis connection and stream settings
+func (c *Config) validateRedis(v *validator) {
+	v.check(c.Redis.Addr != "", "REDIS_ADDR (redis.addr) is required")
+	v.check(c.Redis.DB >= 0, "REDIS_DB (redis.db) must not be negative")
+	if _, err := redis.ParseStreamEncoding(string(c.Redis.StreamEncoding)); err != nil {
+		v.add("REDIS_STREAM_ENCODING (redis.streamEncoding): %v", err)
+	}
+	if _, err := redis.ParseRetentionPolicies(c.Redis.StreamRetention); err != nil {
+		v.add("REDIS_STREAM_RETENTION (redis.streamRetention): %v", err)
+	}
+	v.check(c.Redis.StreamTrimInterval > 0, "REDIS_STREAM_TRIM_INTERVAL (redis.streamTrimInterval) must be positive")
+}
+
+// validateWorker checks the stream consumer settings
+func (c *Config) validateWorker(v *validator) {
+	v.check(c.Worker.Group != "", "WORKER_GROUP (worker.group) is required")
+	v.check(c.Worker.Concurrency >= 0, "WORKER_CONCURRENCY (worker.concurrency) must not be negative")
+}
+
+// validateTracing checks the tracing settings
+func (c *Config) validateTracing(v *validator) {
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		v.add("TRACING_EXPORTER (tracing.exporter) must be one of none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	v.check(c.Tracing.ServiceName != "", "TRACING_SERVICE_NAME (tracing.serviceName) is required")
+}
+
+// validator collects validation failures
+type validator struct {
+	errs []error
+}
+
+// check records message unless ok holds
+func (v *validator) check(ok bool, message string) {
+	if !ok {
+		v.errs = append(v.errs, errors.New(message))
+	}
+}
+
+// add records a formatted failure
+func (v *validator) add(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+// err returns the collected failures, or nil when there are none
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(v.errs...))
+}
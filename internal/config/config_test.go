@@ -0,0 +1,273 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/redis"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig returns a configuration that passes validation
+func validConfig(t *testing.T) *Config {
+	t.Setenv("DB_USER", "root")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_NAME", "todo_db")
+	t.Setenv("S3_BUCKET_NAME", "test-bucket")
+	cfg, err := Read()
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestRead_Defaults(t *testing.T) {
+	cfg := validConfig(t)
+
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, 30*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.WorkerStopTimeout)
+	assert.Equal(t, 2*time.Second, cfg.Server.HealthCheckTimeout)
+	assert.Equal(t, 3306, cfg.Database.Port)
+	assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxLifetime)
+	assert.Equal(t, redis.EncodingData, cfg.Redis.StreamEncoding)
+	assert.Equal(t, StreamBackendRedis, cfg.Stream.Backend)
+	assert.Equal(t, []string{"localhost:9092"}, cfg.Stream.Kafka.Brokers)
+	assert.Equal(t, 24*time.Hour, cfg.Idempotency.TTL)
+	assert.Equal(t, tracing.ExporterNone, cfg.Tracing.Exporter)
+	assert.Equal(t, "ice-assignment", cfg.Tracing.ServiceName)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestRead_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  port: 9090
+  shutdownTimeout: 10s
+database:
+  host: db.internal
+  maxOpenConns: 50
+stream:
+  kafka:
+    brokers: [kafka-1:9092, kafka-2:9092]
+upload:
+  allowedMimeTypes: [application/pdf]
+`), 0o600))
+	t.Setenv(FileEnv, path)
+	t.Setenv("DB_HOST", "db.override")
+	t.Setenv("DB_MAX_IDLE_CONNS", "")
+	t.Setenv("UPLOAD_MAX_SIZE", "1048576")
+	t.Setenv("AUTH_DEFAULT_ROLE", "viewer")
+	t.Setenv("AUTH_OPERATORS", "user:ops, api_key:0b1e")
+
+	cfg, err := Read()
+	require.NoError(t, err)
+
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, 10*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, "db.override", cfg.Database.Host)
+	assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 5, cfg.Database.MaxIdleConns)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Stream.Kafka.Brokers)
+	assert.Equal(t, []string{"application/pdf"}, cfg.Upload.AllowedMimeTypes)
+	assert.Equal(t, int64(1048576), cfg.Upload.MaxSize)
+	assert.Equal(t, domain.RoleViewer, cfg.Auth.DefaultRole)
+	assert.Equal(t, []string{"user:ops", "api_key:0b1e"}, cfg.Auth.Operators)
+}
+
+func TestRead_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		file          string
+		expectedError string
+	}{
+		{
+			name:          "malformed integer",
+			env:           map[string]string{"DB_PORT": "mysql"},
+			expectedError: `invalid DB_PORT "mysql": expected an integer`,
+		},
+		{
+			name:          "malformed duration",
+			env:           map[string]string{"IDEMPOTENCY_TTL": "1 day"},
+			expectedError: `invalid IDEMPOTENCY_TTL "1 day": time: unknown unit " day" in duration "1 day"`,
+		},
+		{
+			name:          "unknown yaml key",
+			file:          "database:\n  hostname: localhost\n",
+			expectedError: "field hostname not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				require.NoError(t, os.WriteFile(path, []byte(tt.file), 0o600))
+				t.Setenv(FileEnv, path)
+			}
+
+			_, err := Read()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		modify         func(*Config)
+		expectedErrors []string
+	}{
+		{
+			name: "non-positive server timeouts",
+			modify: func(cfg *Config) {
+				cfg.Server.ShutdownTimeout = 0
+				cfg.Server.WorkerStopTimeout = 0
+				cfg.Server.HealthCheckTimeout = 0
+			},
+			expectedErrors: []string{
+				"SERVER_SHUTDOWN_TIMEOUT (server.shutdownTimeout) must be positive",
+				"SERVER_WORKER_STOP_TIMEOUT (server.workerStopTimeout) must be positive",
+				"HEALTH_CHECK_TIMEOUT (server.healthCheckTimeout) must be positive",
+			},
+		},
+		{
+			name: "missing database settings",
+			modify: func(cfg *Config) {
+				cfg.Database.User = ""
+				cfg.Database.Host = ""
+			},
+			expectedErrors: []string{
+				"DB_USER (database.user) is required",
+				"DB_HOST (database.host) is required",
+			},
+		},
+		{
+			name: "idle connections above pool size",
+			modify: func(cfg *Config) {
+				cfg.Database.MaxOpenConns = 2
+				cfg.Database.MaxIdleConns = 5
+			},
+			expectedErrors: []string{"DB_MAX_IDLE_CONNS (database.maxIdleConns) must be between 0 and DB_MAX_OPEN_CONNS"},
+		},
+		{
+			name: "unknown stream backend",
+			modify: func(cfg *Config) {
+				cfg.Stream.Backend = "rabbitmq"
+			},
+			expectedErrors: []string{`STREAM_BACKEND (stream.backend) must be one of redis, memory, nats or kafka, got "rabbitmq"`},
+		},
+		{
+			name: "invalid stream settings",
+			modify: func(cfg *Config) {
+				cfg.Redis.StreamEncoding = "xml"
+				cfg.Redis.StreamRetention = "todo-items=maxlen:-1"
+			},
+			expectedErrors: []string{
+				"REDIS_STREAM_ENCODING (redis.streamEncoding)",
+				"REDIS_STREAM_RETENTION (redis.streamRetention): invalid maxlen for stream todo-items",
+			},
+		},
+		{
+			name: "unknown tracing exporter",
+			modify: func(cfg *Config) {
+				cfg.Tracing.Exporter = "jaeger"
+			},
+			expectedErrors: []string{`TRACING_EXPORTER (tracing.exporter) must be one of none, stdout or otlp, got "jaeger"`},
+		},
+		{
+			name: "missing bucket and invalid role",
+			modify: func(cfg *Config) {
+				cfg.S3.Bucket = ""
+				cfg.Auth.DefaultRole = "owner"
+			},
+			expectedErrors: []string{
+				"S3_BUCKET_NAME (s3.bucket) is required",
+				"AUTH_DEFAULT_ROLE (auth.defaultRole) must be viewer, editor or admin",
+			},
+		},
+		{
+			name: "invalid operator",
+			modify: func(cfg *Config) {
+				cfg.Auth.Operators = []string{"user:ops", "group:ops"}
+			},
+			expectedErrors: []string{`AUTH_OPERATORS (auth.operators) must list user:<id> or api_key:<id> principals, got "group:ops"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+
+			require.Error(t, err)
+			for _, expected := range tt.expectedErrors {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateWorker(t *testing.T) {
+	tests := []struct {
+		name           string
+		modify         func(*Config)
+		expectedErrors []string
+	}{
+		{
+			name:   "needs no database, s3 or server settings",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "missing redis address and group",
+			modify: func(cfg *Config) {
+				cfg.Redis.Addr = ""
+				cfg.Worker.Group = ""
+			},
+			expectedErrors: []string{
+				"REDIS_ADDR (redis.addr) is required",
+				"WORKER_GROUP (worker.group) is required",
+			},
+		},
+		{
+			name: "non-positive shutdown timeout",
+			modify: func(cfg *Config) {
+				cfg.Server.ShutdownTimeout = 0
+			},
+			expectedErrors: []string{"SERVER_SHUTDOWN_TIMEOUT (server.shutdownTimeout) must be positive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Read()
+			require.NoError(t, err)
+			require.Error(t, cfg.Validate(), "the server needs database and s3 settings")
+			tt.modify(cfg)
+
+			err = cfg.ValidateWorker()
+
+			if len(tt.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tt.expectedErrors {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the path of an optional YAML file
+const FileEnv = "CONFIG_FILE"
+
+// Load reads the configuration and validates it for the server
+func Load() (*Config, error) {
+	return load((*Config).Validate)
+}
+
+// LoadWorker reads the configuration and validates only the settings of cmd/worker
+func LoadWorker() (*Config, error) {
+	return load((*Config).ValidateWorker)
+}
+
+// load reads the configuration and checks it with validate
+func load(validate func(*Config) error) (*Config, error) {
+	cfg, err := Read()
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Read builds the configuration without validating it. Defaults are overridden by the
+// YAML file named by CONFIG_FILE, which is in turn overridden by environment
+// variables. A .env file in the working directory is loaded into the environment
+// first; it never overrides variables that are already set. Empty variables are
+// treated as unset.
+func Read() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("load .env: %w", err)
+	}
+
+	cfg := &Config{}
+	fields := reflect.ValueOf(cfg).Elem()
+	if err := setFields(fields, "default", func(name string) string { return name }); err != nil {
+		return nil, err
+	}
+	if path := os.Getenv(FileEnv); path != "" {
+		if err := readFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := setFields(fields, "env", os.Getenv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readFile decodes a YAML file into cfg, rejecting unknown keys
+func readFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer file.Close()
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// setFields walks a config struct and assigns every field whose tag resolves to a
+// non-empty value through lookup
+func setFields(v reflect.Value, tag string, lookup func(string) string) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := v.Type().Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := setFields(field, tag, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		name, ok := structField.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		value := lookup(name)
+		if value == "" {
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			envName := structField.Tag.Get("env")
+			return fmt.Errorf("invalid %s %q: %w", envName, value, err)
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setField parses value into a string, integer, boolean, duration or string slice field
+func setField(field reflect.Value, value string) error {
+	switch {
+	case field.Type() == durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() >= reflect.Int && field.Kind() <= reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("expected an integer")
+		}
+		field.SetInt(n)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected true or false")
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
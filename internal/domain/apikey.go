@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key so that leaked keys are easy to recognize
+const APIKeyPrefix = "ice_"
+
+// APIKey represents a stored API key. Only the SHA-256 hash of the key is kept;
+// the key itself is shown once when it is created.
+type APIKey struct {
+	ID        uuid.UUID  `gorm:"type:varchar(36);primaryKey"`
+	TenantID  string     `gorm:"type:varchar(64);not null;default:default;index"`
+	Name      string     `gorm:"type:varchar(255);not null"`
+	Prefix    string     `gorm:"type:varchar(16);not null"`
+	KeyHash   string     `gorm:"type:char(64);not null;uniqueIndex"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	RevokedAt *time.Time `gorm:"type:timestamp;null"`
+}
+
+// TableName specifies the table name for GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// NewAPIKey generates a new random API key for a tenant and returns its record together
+// with the key
+func NewAPIKey(tenantID, name string) (*APIKey, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return &APIKey{
+		ID:       uuid.New(),
+		TenantID: tenantID,
+		Name:     name,
+		Prefix:   key[:len(APIKeyPrefix)+6],
+		KeyHash:  HashAPIKey(key),
+	}, key, nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash under which a key is stored
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsRevoked reports whether the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Principal returns the principal authenticated by this key
+func (k *APIKey) Principal() *Principal {
+	return &Principal{
+		ID:       k.ID.String(),
+		Type:     PrincipalAPIKey,
+		Name:     k.Name,
+		TenantID: k.TenantID,
+	}
+}
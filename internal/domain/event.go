@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifies the kind of a domain event
+type EventType string
+
+const (
+	EventTodoCreated       EventType = "todo.created"
+	EventTodoUpdated       EventType = "todo.updated"
+	EventTodoDeleted       EventType = "todo.deleted"
+	EventTodoStatusChanged EventType = "todo.status_changed"
+)
+
+// EventSchemaVersion is the schema version of the event payloads emitted by this service.
+// Additive changes keep the version; removing or changing the meaning of a field bumps it.
+const EventSchemaVersion = 1
+
+// Event is the versioned envelope every stream message is wrapped in. TenantID is the
+// tenant the changed data belongs to. TraceParent and TraceState carry the W3C trace
+// context of the request that caused the event, so consumers can continue its trace.
+type Event struct {
+	ID            string          `json:"id"`
+	Type          EventType       `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	TenantID      string          `json:"tenantId,omitempty"`
+	CorrelationID string          `json:"correlationId,omitempty"`
+	TraceParent   string          `json:"traceparent,omitempty"`
+	TraceState    string          `json:"tracestate,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewEvent wraps a payload into a new event envelope
+func NewEvent(eventType EventType, payload interface{}, correlationID string, occurredAt time.Time) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		SchemaVersion: EventSchemaVersion,
+		OccurredAt:    occurredAt.UTC(),
+		CorrelationID: correlationID,
+		Payload:       data,
+	}, nil
+}
+
+// DecodePayload decodes the event payload into v
+func (e *Event) DecodePayload(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// TodoCreatedPayload is the payload of a todo.created event
+type TodoCreatedPayload struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	DueDate     time.Time  `json:"dueDate"`
+	FileID      string     `json:"fileId,omitempty"`
+	Status      TodoStatus `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// TodoUpdatedPayload is the payload of a todo.updated event and carries the item's new state
+type TodoUpdatedPayload struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"dueDate"`
+	FileID      string    `json:"fileId,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TodoDeletedPayload is the payload of a todo.deleted event
+type TodoDeletedPayload struct {
+	ID string `json:"id"`
+}
+
+// TodoStatusChangedPayload is the payload of a todo.status_changed event
+type TodoStatusChangedPayload struct {
+	ID             string     `json:"id"`
+	PreviousStatus TodoStatus `json:"previousStatus"`
+	Status         TodoStatus `json:"status"`
+	CompletedAt    *time.Time `json:"completedAt,omitempty"`
+}
+
+// NewTodoCreatedPayload builds the todo.created payload for an item
+func NewTodoCreatedPayload(item *TodoItem) TodoCreatedPayload {
+	return TodoCreatedPayload{
+		ID:          item.ID.String(),
+		Description: item.Description,
+		DueDate:     item.DueDate.UTC(),
+		FileID:      item.FileID,
+		Status:      item.Status,
+		CreatedAt:   item.CreatedAt.UTC(),
+	}
+}
+
+// NewTodoUpdatedPayload builds the todo.updated payload for an item
+func NewTodoUpdatedPayload(item *TodoItem) TodoUpdatedPayload {
+	return TodoUpdatedPayload{
+		ID:          item.ID.String(),
+		Description: item.Description,
+		DueDate:     item.DueDate.UTC(),
+		FileID:      item.FileID,
+		UpdatedAt:   item.UpdatedAt.UTC(),
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// File represents the metadata of a file stored in S3
+type File struct {
+	ID               uuid.UUID `gorm:"type:varchar(36);primaryKey"` // S3 object key within the tenant's prefix
+	TenantID         string    `gorm:"type:varchar(64);not null;default:default;index"`
+	OwnerID          string    `gorm:"type:varchar(255);index"` // Principal that uploaded the file
+	OriginalFilename string    `gorm:"type:varchar(255);not null"`
+	Size             int64     `gorm:"not null"`
+	MimeType         string    `gorm:"type:varchar(255);not null"`
+	SHA256           string    `gorm:"type:char(64);not null;index"`
+	UploadedBy       string    `gorm:"type:varchar(255);index"`
+	CreatedAt        time.Time `gorm:"autoCreateTime;index"`
+}
+
+// TableName specifies the table name for GORM
+func (File) TableName() string {
+	return "files"
+}
+
+// AssignOwner records the principal uploading the file and its tenant
+func (f *File) AssignOwner(principal *Principal) {
+	if principal == nil {
+		return
+	}
+	f.TenantID = principal.TenantID
+	f.OwnerID = principal.ID
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage represents a stream message waiting to be relayed to the broker.
+// Messages sharing an AggregateKey are relayed one at a time in Seq order.
+type OutboxMessage struct {
+	ID uuid.UUID `gorm:"type:varchar(36);primaryKey"`
+	// Seq is assigned by the database on insert and orders the messages of an aggregate
+	Seq           uint64    `gorm:"autoIncrement;unique;not null;index:idx_outbox_messages_aggregate_seq,priority:2"`
+	Stream        string    `gorm:"type:varchar(255);not null"`
+	AggregateKey  string    `gorm:"type:varchar(255);not null;default:'';index:idx_outbox_messages_aggregate_seq,priority:1"`
+	Payload       string    `gorm:"type:text;not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	LastError     string    `gorm:"type:varchar(1000)"`
+	NextAttemptAt time.Time `gorm:"type:timestamp(6);not null;index"`
+	// LockedUntil is set while a relay publishes the message
+	LockedUntil *time.Time `gorm:"type:timestamp(6);null"`
+	// FailedAt is set once the message ran out of attempts. It is kept for inspection
+	// but no longer relayed, and no longer holds back the later messages of its aggregate.
+	FailedAt  *time.Time `gorm:"type:timestamp(6);null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+// NewOutboxMessage creates a new OutboxMessage that is due immediately
+func NewOutboxMessage(stream, aggregateKey string, payload []byte) *OutboxMessage {
+	return &OutboxMessage{
+		ID:            uuid.New(),
+		Stream:        stream,
+		AggregateKey:  aggregateKey,
+		Payload:       string(payload),
+		NextAttemptAt: time.Now(),
+	}
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// PrincipalType identifies how a principal authenticated
+type PrincipalType string
+
+const (
+	PrincipalUser   PrincipalType = "user"
+	PrincipalAPIKey PrincipalType = "api_key"
+)
+
+// DefaultTenantID is the tenant of callers that do not name one, so that
+// single-tenant deployments need no tenant configuration
+const DefaultTenantID = "default"
+
+// tenantIDPattern restricts tenant IDs to characters that are safe in S3 object keys
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// IsValidTenantID reports whether id is 1-64 letters, digits, underscores or hyphens
+func IsValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// ID is the JWT subject or the API key ID
+	ID   string
+	Type PrincipalType
+	// Name is a display name: the token's name claim or the API key name
+	Name string
+	// TenantID is the tenant whose data the principal can access
+	TenantID string
+	// Role is the role assigned to the principal, empty when it has none
+	Role Role
+	// Operator marks a principal configured as an operator of the deployment, which
+	// additionally has the operator-only permissions
+	Operator bool
+}
+
+// Can reports whether the principal's role grants the permission, or whether the
+// principal is an operator and the permission is operator-only
+func (p *Principal) Can(permission Permission) bool {
+	if p == nil {
+		return false
+	}
+	return p.Role.Grants(permission) || p.Operator && permission.IsOperatorOnly()
+}
+
+// Ref returns the "<type>:<id>" reference under which operators are configured
+func (p *Principal) Ref() string {
+	return string(p.Type) + ":" + p.ID
+}
+
+// IsValidPrincipalRef reports whether ref is a "user:<id>" or "api_key:<id>" reference
+// with an ID of 1-255 characters
+func IsValidPrincipalRef(ref string) bool {
+	principalType, id, ok := strings.Cut(ref, ":")
+	if !ok || id == "" || len(id) > 255 {
+		return false
+	}
+	return PrincipalType(principalType) == PrincipalUser || PrincipalType(principalType) == PrincipalAPIKey
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal of ctx, or nil for
+// unauthenticated contexts such as background workers
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// TenantFromContext returns the tenant of the principal of ctx, or DefaultTenantID
+// when ctx carries no principal or the principal has no tenant
+func TenantFromContext(ctx context.Context) string {
+	if principal := PrincipalFromContext(ctx); principal != nil && principal.TenantID != "" {
+		return principal.TenantID
+	}
+	return DefaultTenantID
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+)
+
+// Role is a named set of permissions assigned to a principal
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// Permission allows a single kind of operation
+type Permission string
+
+const (
+	PermissionTodoRead     Permission = "todo:read"
+	PermissionTodoCreate   Permission = "todo:create"
+	PermissionTodoUpdate   Permission = "todo:update"
+	PermissionTodoDelete   Permission = "todo:delete"
+	PermissionAssetRead    Permission = "asset:read"
+	PermissionAssetCreate  Permission = "asset:create"
+	PermissionAPIKeyManage Permission = "apikey:manage"
+	PermissionRoleManage   Permission = "role:manage"
+	// PermissionStreamAdmin manages dead-letter queues and reads stream statistics. These
+	// are shared by all tenants, so only operators have it.
+	PermissionStreamAdmin Permission = "stream:admin"
+)
+
+// operatorPermissions are granted to operators only and are never part of a role
+var operatorPermissions = []Permission{
+	PermissionStreamAdmin,
+}
+
+// rolePermissions lists the permissions granted by each role
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {
+		PermissionTodoRead,
+		PermissionAssetRead,
+	},
+	RoleEditor: {
+		PermissionTodoRead,
+		PermissionTodoCreate,
+		PermissionTodoUpdate,
+		PermissionTodoDelete,
+		PermissionAssetRead,
+		PermissionAssetCreate,
+	},
+	RoleAdmin: {
+		PermissionTodoRead,
+		PermissionTodoCreate,
+		PermissionTodoUpdate,
+		PermissionTodoDelete,
+		PermissionAssetRead,
+		PermissionAssetCreate,
+		PermissionAPIKeyManage,
+		PermissionRoleManage,
+	},
+}
+
+// IsValid reports whether the role is a known role
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Grants reports whether the role includes the permission
+func (r Role) Grants(permission Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOperatorOnly reports whether the permission is granted to operators only
+func (p Permission) IsOperatorOnly() bool {
+	for _, permission := range operatorPermissions {
+		if permission == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAssignment grants a role to a principal within its tenant
+type RoleAssignment struct {
+	TenantID      string        `gorm:"type:varchar(64);primaryKey"`
+	PrincipalType PrincipalType `gorm:"type:varchar(20);primaryKey"`
+	PrincipalID   string        `gorm:"type:varchar(255);primaryKey"`
+	Role          Role          `gorm:"type:varchar(20);not null"`
+	CreatedAt     time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time     `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (RoleAssignment) TableName() string {
+	return "role_assignments"
+}
@@ -1,19 +1,61 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// TodoStatus represents the lifecycle state of a todo item
+type TodoStatus string
+
+const (
+	TodoStatusOpen       TodoStatus = "open"
+	TodoStatusInProgress TodoStatus = "in_progress"
+	TodoStatusDone       TodoStatus = "done"
+	TodoStatusCancelled  TodoStatus = "cancelled"
+)
+
+// ErrInvalidStatusTransition is returned when a status change is not allowed
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// todoStatusTransitions lists the statuses reachable from each status
+var todoStatusTransitions = map[TodoStatus][]TodoStatus{
+	TodoStatusOpen:       {TodoStatusInProgress, TodoStatusDone, TodoStatusCancelled},
+	TodoStatusInProgress: {TodoStatusOpen, TodoStatusDone, TodoStatusCancelled},
+	TodoStatusDone:       {TodoStatusOpen},
+	TodoStatusCancelled:  {TodoStatusOpen},
+}
+
+// IsValid reports whether the status is a known lifecycle state
+func (s TodoStatus) IsValid() bool {
+	_, ok := todoStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed
+func (s TodoStatus) CanTransitionTo(next TodoStatus) bool {
+	for _, allowed := range todoStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // TodoItem represents a todo item in the domain
 type TodoItem struct {
-	ID          uuid.UUID `gorm:"type:varchar(36);primaryKey"`
-	Description string    `gorm:"type:varchar(500);not null"`
-	DueDate     time.Time `gorm:"type:timestamp;not null;index"`
-	FileID      string    `gorm:"type:varchar(255)"` // Reference to file stored in S3
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	ID          uuid.UUID  `gorm:"type:varchar(36);primaryKey"`
+	TenantID    string     `gorm:"type:varchar(64);not null;default:default;index"`
+	OwnerID     string     `gorm:"type:varchar(255);index"` // Principal that created the item
+	Description string     `gorm:"type:varchar(500);not null"`
+	DueDate     time.Time  `gorm:"type:timestamp;not null;index"`
+	FileID      string     `gorm:"type:varchar(255)"` // Reference to file stored in S3
+	Status      TodoStatus `gorm:"type:varchar(20);not null;default:open;index"`
+	CompletedAt *time.Time `gorm:"type:timestamp NULL"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime;index"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime;index"`
 }
 
 // TableName specifies the table name for GORM
@@ -28,6 +70,29 @@ func NewTodoItem(description string, dueDate time.Time, fileID string) *TodoItem
 		Description: description,
 		DueDate:     dueDate,
 		FileID:      fileID,
+		Status:      TodoStatusOpen,
+	}
+}
+
+// AssignOwner records the principal creating the item and its tenant
+func (t *TodoItem) AssignOwner(principal *Principal) {
+	if principal == nil {
+		return
 	}
+	t.TenantID = principal.TenantID
+	t.OwnerID = principal.ID
 }
 
+// TransitionTo moves the item to the next status, maintaining CompletedAt
+func (t *TodoItem) TransitionTo(next TodoStatus, now time.Time) error {
+	if !t.Status.CanTransitionTo(next) {
+		return ErrInvalidStatusTransition
+	}
+	t.Status = next
+	if next == TodoStatusDone {
+		t.CompletedAt = &now
+	} else {
+		t.CompletedAt = nil
+	}
+	return nil
+}
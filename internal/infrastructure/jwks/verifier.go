@@ -0,0 +1,278 @@
+package jwks
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// minRefreshInterval rate-limits JWKS refreshes triggered by unknown key IDs
+	minRefreshInterval = time.Minute
+	// maxKeyAge is how long keys fetched from a URL are used before they are refreshed
+	maxKeyAge    = time.Hour
+	fetchTimeout = 10 * time.Second
+)
+
+// TokenVerifier implements the TokenVerifier interface for HS256 and RS256 JWTs signed
+// with keys from a JSON Web Key Set. Sets loaded from a URL are refreshed hourly and
+// when a token names an unknown key ID.
+type TokenVerifier struct {
+	source     string
+	issuer     string
+	audience   string
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]*signingKey
+	fetchedAt time.Time
+}
+
+// signingKey is a verification key together with the algorithm it is restricted to
+type signingKey struct {
+	alg string
+	key interface{}
+}
+
+// jsonWebKey is the subset of RFC 7517 fields used for HS256 and RS256 keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// NewTokenVerifier creates a TokenVerifier for the JWKS at source, a file path or an
+// http(s) URL. Empty issuer and audience are not checked.
+func NewTokenVerifier(ctx context.Context, source, issuer, audience string) (*TokenVerifier, error) {
+	v := &TokenVerifier{
+		source:     source,
+		issuer:     issuer,
+		audience:   audience,
+		httpClient: &http.Client{Timeout: fetchTimeout},
+	}
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Verify validates a JWT and returns the principal named by its sub claim. The tenant
+// claim selects the principal's tenant and defaults to domain.DefaultTenantID.
+func (v *TokenVerifier) Verify(ctx context.Context, token string) (*domain.Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		opts = append(opts, jwt.WithAudience(v.audience))
+	}
+
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
+		return v.lookup(ctx, token)
+	}, opts...)
+	if err != nil {
+		return nil, apperrors.NewAppError("UNAUTHORIZED", "invalid token", http.StatusUnauthorized, err)
+	}
+	if claims.Subject == "" {
+		return nil, apperrors.NewAppError("UNAUTHORIZED", "invalid token", http.StatusUnauthorized, errors.New("token has no subject"))
+	}
+	tenantID := claims.Tenant
+	if tenantID == "" {
+		tenantID = domain.DefaultTenantID
+	}
+	if !domain.IsValidTenantID(tenantID) {
+		return nil, apperrors.NewAppError("UNAUTHORIZED", "invalid token", http.StatusUnauthorized, fmt.Errorf("invalid tenant %q", tenantID))
+	}
+	return &domain.Principal{
+		ID:       claims.Subject,
+		Type:     domain.PrincipalUser,
+		Name:     claims.Name,
+		TenantID: tenantID,
+	}, nil
+}
+
+// tokenClaims are the claims read from a verified token
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Name   string `json:"name,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// lookup returns the key a token was signed with
+func (v *TokenVerifier) lookup(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, found := v.key(kid)
+	if !found || v.stale() {
+		if err := v.refresh(ctx); err != nil && !found {
+			return nil, err
+		}
+		key, found = v.key(kid)
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if key.alg != "" && key.alg != token.Method.Alg() {
+		return nil, fmt.Errorf("key %q is not usable with %s", kid, token.Method.Alg())
+	}
+	return key.key, nil
+}
+
+// key returns the key with the given ID; tokens without a key ID use the only key of the set
+func (v *TokenVerifier) key(kid string) (*signingKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// stale reports whether keys fetched from a URL are due for a refresh
+func (v *TokenVerifier) stale() bool {
+	if !v.isURL() {
+		return false
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return time.Since(v.fetchedAt) > maxKeyAge
+}
+
+// refresh reloads the key set, at most once per minRefreshInterval for URL sources
+func (v *TokenVerifier) refresh(ctx context.Context) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.keys != nil && (!v.isURL() || time.Since(v.fetchedAt) < minRefreshInterval) {
+		return nil
+	}
+	data, err := v.load(ctx)
+	if err != nil {
+		return fmt.Errorf("load jwks: %w", err)
+	}
+	keys, err := parseKeySet(data)
+	if err != nil {
+		return fmt.Errorf("parse jwks: %w", err)
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+// load reads the raw key set from a file or URL
+func (v *TokenVerifier) load(ctx context.Context) ([]byte, error) {
+	if !v.isURL() {
+		return os.ReadFile(v.source)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// isURL reports whether the key set is fetched over HTTP
+func (v *TokenVerifier) isURL() bool {
+	return strings.HasPrefix(v.source, "http://") || strings.HasPrefix(v.source, "https://")
+}
+
+// parseKeySet decodes the HS256 and RS256 signing keys of a JWKS document.
+// Encryption keys and unsupported key types are ignored.
+func parseKeySet(data []byte) (map[string]*signingKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*signingKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key *signingKey
+		var err error
+		switch jwk.Kty {
+		case "oct":
+			key, err = parseSymmetricKey(jwk)
+		case "RSA":
+			key, err = parseRSAKey(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no HS256 or RS256 signing keys")
+	}
+	return keys, nil
+}
+
+// parseSymmetricKey decodes an oct key for HS256
+func parseSymmetricKey(jwk jsonWebKey) (*signingKey, error) {
+	if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodHS256.Alg() {
+		return nil, fmt.Errorf("unsupported algorithm %s", jwk.Alg)
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+	if err != nil || len(secret) == 0 {
+		return nil, errors.New("invalid k")
+	}
+	return &signingKey{alg: jwt.SigningMethodHS256.Alg(), key: secret}, nil
+}
+
+// parseRSAKey decodes an RSA public key for RS256
+func parseRSAKey(jwk jsonWebKey) (*signingKey, error) {
+	if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodRS256.Alg() {
+		return nil, fmt.Errorf("unsupported algorithm %s", jwk.Alg)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil || len(n) == 0 {
+		return nil, errors.New("invalid n")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid e")
+	}
+	publicKey := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	if publicKey.N.BitLen() < 2048 {
+		return nil, errors.New("rsa keys must be at least 2048 bits")
+	}
+	return &signingKey{alg: jwt.SigningMethodRS256.Alg(), key: publicKey}, nil
+}
@@ -0,0 +1,219 @@
+package jwks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// testKeySet builds a JWKS document with an HS256 key "hs" and an RS256 key "rs"
+func testKeySet(t *testing.T, rsaKey *rsa.PrivateKey) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "oct", "kid": "hs", "alg": "HS256", "k": base64.RawURLEncoding.EncodeToString(testSecret)},
+			{
+				"kty": "RSA",
+				"kid": "rs",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{"kty": "oct", "kid": "enc", "use": "enc", "k": "c2VjcmV0"},
+		},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// signToken signs claims with the given method, key ID and key
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestTokenVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, testKeySet(t, rsaKey), 0o600))
+
+	verifier, err := NewTokenVerifier(context.Background(), path, "https://issuer.example", "ice")
+	require.NoError(t, err)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":    "user-1",
+			"name":   "Alice",
+			"tenant": "acme",
+			"iss":    "https://issuer.example",
+			"aud":    "ice",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+		}
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := valid()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedTenant string
+		expectErr      bool
+	}{
+		{
+			name:           "HS256 token",
+			token:          signToken(t, jwt.SigningMethodHS256, "hs", testSecret, valid()),
+			expectedTenant: "acme",
+		},
+		{
+			name:           "RS256 token",
+			token:          signToken(t, jwt.SigningMethodRS256, "rs", rsaKey, valid()),
+			expectedTenant: "acme",
+		},
+		{
+			name:           "token without tenant",
+			token:          signToken(t, jwt.SigningMethodHS256, "hs", testSecret, with("tenant", nil)),
+			expectedTenant: domain.DefaultTenantID,
+		},
+		{
+			name:      "invalid tenant",
+			token:     signToken(t, jwt.SigningMethodHS256, "hs", testSecret, with("tenant", "../acme")),
+			expectErr: true,
+		},
+		{
+			name:      "expired token",
+			token:     signToken(t, jwt.SigningMethodHS256, "hs", testSecret, with("exp", time.Now().Add(-time.Minute).Unix())),
+			expectErr: true,
+		},
+		{
+			name:      "missing expiry",
+			token:     signToken(t, jwt.SigningMethodHS256, "hs", testSecret, with("exp", nil)),
+			expectErr: true,
+		},
+		{
+			name:      "wrong issuer",
+			token:     signToken(t, jwt.SigningMethodHS256, "hs", testSecret, with("iss", "https://other.example")),
+			expectErr: true,
+		},
+		{
+			name:      "wrong audience",
+			token:     signToken(t, jwt.SigningMethodHS256, "hs", testSecret, with("aud", "other")),
+			expectErr: true,
+		},
+		{
+			name:      "missing subject",
+			token:     signToken(t, jwt.SigningMethodHS256, "hs", testSecret, with("sub", nil)),
+			expectErr: true,
+		},
+		{
+			name:      "unknown key id",
+			token:     signToken(t, jwt.SigningMethodHS256, "other", testSecret, valid()),
+			expectErr: true,
+		},
+		{
+			name:      "algorithm does not match key",
+			token:     signToken(t, jwt.SigningMethodHS256, "rs", testSecret, valid()),
+			expectErr: true,
+		},
+		{
+			name:      "bad signature",
+			token:     signToken(t, jwt.SigningMethodHS256, "hs", []byte("wrong-secret"), valid()),
+			expectErr: true,
+		},
+		{
+			name:      "encryption key",
+			token:     signToken(t, jwt.SigningMethodHS256, "enc", []byte("secret"), valid()),
+			expectErr: true,
+		},
+		{
+			name:      "malformed token",
+			token:     "not-a-token",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, principal)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &domain.Principal{ID: "user-1", Type: domain.PrincipalUser, Name: "Alice", TenantID: tt.expectedTenant}, principal)
+		})
+	}
+}
+
+func TestTokenVerifier_URL(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keySet := testKeySet(t, rsaKey)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(keySet)
+	}))
+	defer server.Close()
+
+	verifier, err := NewTokenVerifier(context.Background(), server.URL, "", "")
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+	_, err = verifier.Verify(context.Background(), signToken(t, jwt.SigningMethodRS256, "rs", rsaKey, claims))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Unknown key IDs trigger at most one refresh per minRefreshInterval
+	_, err = verifier.Verify(context.Background(), signToken(t, jwt.SigningMethodHS256, "new", testSecret, claims))
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	verifier.fetchedAt = time.Now().Add(-minRefreshInterval)
+	_, err = verifier.Verify(context.Background(), signToken(t, jwt.SigningMethodHS256, "new", testSecret, claims))
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestNewTokenVerifier_InvalidKeySet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys":[{"kty":"oct","kid":"enc","use":"enc","k":"c2VjcmV0"}]}`), 0o600))
+
+	_, err := NewTokenVerifier(context.Background(), path, "", "")
+	assert.Error(t, err)
+
+	_, err = NewTokenVerifier(context.Background(), filepath.Join(t.TempDir(), "missing.json"), "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	defaultBroker = "localhost:9092"
+	// closeFlushTimeout bounds how long Close waits for buffered records to be delivered
+	closeFlushTimeout = 10 * time.Second
+)
+
+// Config holds the Kafka connection settings
+type Config struct {
+	Brokers []string `yaml:"brokers" env:"KAFKA_BROKERS" default:"localhost:9092"`
+}
+
+// StreamPublisher implements the StreamPublisher interface using Kafka. Each stream
+// is published to the topic of the same name.
+type StreamPublisher struct {
+	client *kgo.Client
+}
+
+// NewStreamPublisher creates a new Kafka StreamPublisher for the configured seed brokers
+func NewStreamPublisher(ctx context.Context, cfg Config) (*StreamPublisher, error) {
+	brokers := cfg.Brokers
+	if len(brokers) == 0 {
+		brokers = []string{defaultBroker}
+	}
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.AllowAutoTopicCreation(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &StreamPublisher{client: client}, nil
+}
+
+// Publish synchronously produces a JSON-encoded message to a Kafka topic. Messages are
+// keyed by the entity they describe so that events of one todo item stay ordered.
+func (p *StreamPublisher) Publish(ctx context.Context, stream string, data map[string]interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	record := &kgo.Record{
+		Topic: stream,
+		Key:   messageKey(data),
+		Value: jsonData,
+	}
+	return p.client.ProduceSync(ctx, record).FirstErr()
+}
+
+// messageKey returns the payload id of an event envelope, falling back to the message id
+func messageKey(data map[string]interface{}) []byte {
+	if payload, ok := data["payload"].(map[string]interface{}); ok {
+		if id, ok := payload["id"].(string); ok && id != "" {
+			return []byte(id)
+		}
+	}
+	if id, ok := data["id"].(string); ok && id != "" {
+		return []byte(id)
+	}
+	return nil
+}
+
+// Check pings the seed brokers and implements the HealthChecker interface
+func (p *StreamPublisher) Check(ctx context.Context) error {
+	return p.client.Ping(ctx)
+}
+
+// Close flushes buffered records, such as those of a Publish whose context was cancelled,
+// and closes the Kafka client. Records not delivered within closeFlushTimeout are dropped.
+func (p *StreamPublisher) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), closeFlushTimeout)
+	defer cancel()
+	err := p.client.Flush(ctx)
+	p.client.Close()
+	return err
+}
@@ -0,0 +1,146 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/streamtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// runCluster starts an in-process fake Kafka cluster and returns its broker addresses
+func runCluster(t *testing.T) []string {
+	cluster, err := kfake.NewCluster(
+		kfake.NumBrokers(1),
+		kfake.AllowAutoTopicCreation(),
+		kfake.DefaultNumPartitions(3),
+	)
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	return cluster.ListenAddrs()
+}
+
+// readRecords consumes the first n records of a topic from the beginning
+func readRecords(t *testing.T, brokers []string, topic string, n int) []*kgo.Record {
+	consumer, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamtest.ReadTimeout)
+	defer cancel()
+	records := make([]*kgo.Record, 0, n)
+	for len(records) < n {
+		fetches := consumer.PollFetches(ctx)
+		if ctx.Err() != nil {
+			t.Fatalf("received %d of %d messages", len(records), n)
+		}
+		require.NoError(t, fetches.Err())
+		records = append(records, fetches.Records()...)
+	}
+	return records
+}
+
+func TestStreamPublisher_Conformance(t *testing.T) {
+	brokers := runCluster(t)
+	streamtest.RunPublisherConformance(t, func(t *testing.T) streamtest.Backend {
+		publisher, err := NewStreamPublisher(context.Background(), Config{Brokers: brokers})
+		require.NoError(t, err)
+		t.Cleanup(func() { publisher.Close() })
+		return streamtest.Backend{
+			Publisher: publisher,
+			Read: func(t *testing.T, stream string, n int) []map[string]interface{} {
+				records := readRecords(t, brokers, stream, n)
+				messages := make([]map[string]interface{}, 0, len(records))
+				for _, record := range records {
+					var data map[string]interface{}
+					require.NoError(t, json.Unmarshal(record.Value, &data))
+					messages = append(messages, data)
+				}
+				return messages
+			},
+		}
+	})
+}
+
+func TestStreamPublisher_KeysByEntity(t *testing.T) {
+	brokers := runCluster(t)
+	publisher, err := NewStreamPublisher(context.Background(), Config{Brokers: brokers})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	require.NoError(t, publisher.Publish(context.Background(), "todo-items", map[string]interface{}{
+		"id":      "event-1",
+		"type":    "todo.created",
+		"payload": map[string]interface{}{"id": "todo-1"},
+	}))
+
+	records := readRecords(t, brokers, "todo-items", 1)
+	assert.Equal(t, "todo-1", string(records[0].Key))
+}
+
+func TestStreamPublisher_CloseFlushes(t *testing.T) {
+	brokers := runCluster(t)
+	publisher, err := NewStreamPublisher(context.Background(), Config{Brokers: brokers})
+	require.NoError(t, err)
+
+	// Buffer a record without waiting for it, as a cancelled Publish would leave behind
+	publisher.client.Produce(context.Background(), &kgo.Record{Topic: "todo-items", Value: []byte(`{"id":"event-1"}`)}, nil)
+	require.NoError(t, publisher.Close())
+
+	records := readRecords(t, brokers, "todo-items", 1)
+	assert.JSONEq(t, `{"id":"event-1"}`, string(records[0].Value))
+}
+
+func TestStreamPublisher_Check(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1))
+	require.NoError(t, err)
+	publisher, err := NewStreamPublisher(context.Background(), Config{Brokers: cluster.ListenAddrs()})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	assert.NoError(t, publisher.Check(context.Background()))
+
+	cluster.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Error(t, publisher.Check(ctx))
+}
+
+func TestMessageKey(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want []byte
+	}{
+		{
+			name: "payload id",
+			data: map[string]interface{}{"id": "event-1", "payload": map[string]interface{}{"id": "todo-1"}},
+			want: []byte("todo-1"),
+		},
+		{
+			name: "message id",
+			data: map[string]interface{}{"id": "todo-1", "description": "plain message"},
+			want: []byte("todo-1"),
+		},
+		{
+			name: "no id",
+			data: map[string]interface{}{"description": "plain message"},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, messageKey(tt.data))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+)
+
+// ErrPublisherClosed is returned when publishing to a closed StreamPublisher
+var ErrPublisherClosed = errors.New("stream publisher is closed")
+
+const defaultBufferSize = 64
+
+// StreamPublisher implements the StreamPublisher interface with in-process fan-out to
+// subscribers. Messages are encoded like the Redis publisher's default format, with
+// the JSON-encoded data in a single "data" field.
+type StreamPublisher struct {
+	mu          sync.RWMutex
+	bufferSize  int
+	sequence    atomic.Uint64
+	subscribers map[string]map[*subscription]struct{}
+	closed      bool
+	closing     chan struct{}
+	closeOnce   sync.Once
+}
+
+// subscription is a subscriber's channel for one stream
+type subscription struct {
+	ch   chan *client.StreamMessage
+	done chan struct{}
+}
+
+// NewStreamPublisher creates a new in-process StreamPublisher. bufferSize is the number
+// of messages buffered per subscriber before Publish blocks.
+func NewStreamPublisher(bufferSize int) *StreamPublisher {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	return &StreamPublisher{
+		bufferSize:  bufferSize,
+		subscribers: make(map[string]map[*subscription]struct{}),
+		closing:     make(chan struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving every message published to stream after the
+// call, and a function that ends the subscription and closes the channel.
+func (p *StreamPublisher) Subscribe(stream string) (<-chan *client.StreamMessage, func()) {
+	sub := &subscription{
+		ch:   make(chan *client.StreamMessage, p.bufferSize),
+		done: make(chan struct{}),
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	if p.subscribers[stream] == nil {
+		p.subscribers[stream] = make(map[*subscription]struct{})
+	}
+	p.subscribers[stream][sub] = struct{}{}
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			// Release publishers blocked on this subscriber before taking the lock
+			close(sub.done)
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if _, ok := p.subscribers[stream][sub]; ok {
+				delete(p.subscribers[stream], sub)
+				close(sub.ch)
+			}
+		})
+	}
+}
+
+// Publish delivers a message to every current subscriber of the stream. It blocks
+// while a subscriber's buffer is full, until ctx is done.
+func (p *StreamPublisher) Publish(ctx context.Context, stream string, data map[string]interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Holding the read lock keeps subscriber channels open while sending
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	id := fmt.Sprintf("%d-0", p.sequence.Add(1))
+	for sub := range p.subscribers[stream] {
+		msg := &client.StreamMessage{
+			ID:      id,
+			Stream:  stream,
+			Values:  map[string]interface{}{"data": string(jsonData)},
+			Attempt: 1,
+		}
+		select {
+		case sub.ch <- msg:
+		case <-sub.done:
+		case <-p.closing:
+			return ErrPublisherClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close ends all subscriptions; further publishes fail with ErrPublisherClosed
+func (p *StreamPublisher) Close() error {
+	// Release blocked publishers before taking the lock
+	p.closeOnce.Do(func() { close(p.closing) })
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	for _, subs := range p.subscribers {
+		for sub := range subs {
+			close(sub.ch)
+		}
+	}
+	p.subscribers = nil
+	return nil
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/streamtest"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subscribingPublisher subscribes to every stream before its first publish, since the
+// in-process publisher only delivers to existing subscribers
+type subscribingPublisher struct {
+	*StreamPublisher
+	mu   sync.Mutex
+	subs map[string]<-chan *client.StreamMessage
+}
+
+func (p *subscribingPublisher) Publish(ctx context.Context, stream string, data map[string]interface{}) error {
+	p.subscription(stream)
+	return p.StreamPublisher.Publish(ctx, stream, data)
+}
+
+func (p *subscribingPublisher) subscription(stream string) <-chan *client.StreamMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ch, ok := p.subs[stream]; ok {
+		return ch
+	}
+	ch, _ := p.StreamPublisher.Subscribe(stream)
+	p.subs[stream] = ch
+	return ch
+}
+
+func TestStreamPublisher_Conformance(t *testing.T) {
+	streamtest.RunPublisherConformance(t, func(t *testing.T) streamtest.Backend {
+		publisher := &subscribingPublisher{
+			StreamPublisher: NewStreamPublisher(100),
+			subs:            make(map[string]<-chan *client.StreamMessage),
+		}
+		t.Cleanup(func() { publisher.Close() })
+		return streamtest.Backend{
+			Publisher: publisher,
+			Read: func(t *testing.T, stream string, n int) []map[string]interface{} {
+				ch := publisher.subscription(stream)
+				messages := make([]map[string]interface{}, 0, n)
+				for len(messages) < n {
+					select {
+					case msg := <-ch:
+						var data map[string]interface{}
+						require.NoError(t, json.Unmarshal([]byte(msg.Values["data"].(string)), &data))
+						messages = append(messages, data)
+					case <-time.After(streamtest.ReadTimeout):
+						t.Fatalf("received %d of %d messages", len(messages), n)
+					}
+				}
+				return messages
+			},
+		}
+	})
+}
+
+func TestStreamPublisher_FanOut(t *testing.T) {
+	publisher := NewStreamPublisher(1)
+	defer publisher.Close()
+
+	first, unsubscribeFirst := publisher.Subscribe("todo-items")
+	second, unsubscribeSecond := publisher.Subscribe("todo-items")
+	defer unsubscribeSecond()
+
+	require.NoError(t, publisher.Publish(context.Background(), "todo-items", map[string]interface{}{"id": "1"}))
+	msg := <-first
+	assert.Equal(t, "1-0", msg.ID)
+	assert.Equal(t, "todo-items", msg.Stream)
+	assert.Equal(t, 1, msg.Attempt)
+	assert.Equal(t, `{"id":"1"}`, (<-second).Values["data"])
+
+	unsubscribeFirst()
+	_, open := <-first
+	assert.False(t, open, "channel should be closed after unsubscribing")
+	require.NoError(t, publisher.Publish(context.Background(), "todo-items", map[string]interface{}{"id": "2"}))
+	assert.Equal(t, "2-0", (<-second).ID)
+}
+
+func TestStreamPublisher_PublishBlocksOnFullBuffer(t *testing.T) {
+	publisher := NewStreamPublisher(1)
+	defer publisher.Close()
+	_, unsubscribe := publisher.Subscribe("todo-items")
+
+	require.NoError(t, publisher.Publish(context.Background(), "todo-items", map[string]interface{}{"id": "1"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := publisher.Publish(ctx, "todo-items", map[string]interface{}{"id": "2"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done := make(chan error)
+	go func() {
+		done <- publisher.Publish(context.Background(), "todo-items", map[string]interface{}{"id": "3"})
+	}()
+	unsubscribe()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribing did not release the blocked publisher")
+	}
+}
+
+func TestStreamPublisher_Close(t *testing.T) {
+	publisher := NewStreamPublisher(0)
+	ch, _ := publisher.Subscribe("todo-items")
+
+	require.NoError(t, publisher.Close())
+	_, open := <-ch
+	assert.False(t, open)
+
+	err := publisher.Publish(context.Background(), "todo-items", map[string]interface{}{"id": "1"})
+	assert.ErrorIs(t, err, ErrPublisherClosed)
+	require.NoError(t, publisher.Close())
+}
@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository implements the APIKeyRepository interface using MySQL with GORM
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new MySQL APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create inserts a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	result := conn(ctx, r.db).Create(key)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// GetByHash retrieves an API key by the hash of the key. It is not scoped to a tenant
+// since it runs before the caller is authenticated.
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	result := conn(ctx, r.db).Where("key_hash = ?", keyHash).First(&key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewAppError("API_KEY_NOT_FOUND", "api key not found", http.StatusNotFound, nil)
+		}
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+// List retrieves the API keys of the caller's tenant, newest first
+func (r *APIKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	result := tenantConn(ctx, r.db).Order("created_at DESC").Find(&keys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return keys, nil
+}
+
+// Revoke marks an API key of the caller's tenant as revoked. Revoking an already revoked key keeps its original revocation time.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return apperrors.NewAppError("INVALID_ID", "invalid api key id", http.StatusBadRequest, nil)
+	}
+	var key domain.APIKey
+	result := tenantConn(ctx, r.db).Where("id = ?", parsedID).First(&key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return apperrors.NewAppError("API_KEY_NOT_FOUND", "api key not found", http.StatusNotFound, nil)
+		}
+		return result.Error
+	}
+	if key.IsRevoked() {
+		return nil
+	}
+	return conn(ctx, r.db).Model(&key).Update("revoked_at", revokedAt).Error
+}
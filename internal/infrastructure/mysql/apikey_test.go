@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepository_GetByHash(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAPIKeyRepository(db)
+
+	key, secret, err := domain.NewAPIKey(domain.DefaultTenantID, "ci")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), key))
+
+	retrieved, err := repo.GetByHash(context.Background(), domain.HashAPIKey(secret))
+	assert.NoError(t, err)
+	assert.Equal(t, key.ID, retrieved.ID)
+	assert.Equal(t, "ci", retrieved.Name)
+	assert.False(t, retrieved.IsRevoked())
+
+	// Test not found
+	_, err = repo.GetByHash(context.Background(), domain.HashAPIKey("ice_unknown"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAPIKeyRepository(db)
+
+	key, secret, err := domain.NewAPIKey(domain.DefaultTenantID, "ci")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), key))
+
+	revokedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, repo.Revoke(context.Background(), key.ID.String(), revokedAt))
+	require.NoError(t, repo.Revoke(context.Background(), key.ID.String(), revokedAt.Add(time.Hour)))
+
+	retrieved, err := repo.GetByHash(context.Background(), domain.HashAPIKey(secret))
+	require.NoError(t, err)
+	require.True(t, retrieved.IsRevoked())
+	assert.True(t, revokedAt.Equal(*retrieved.RevokedAt), "revoking twice should keep the first revocation time")
+
+	err = repo.Revoke(context.Background(), uuid.New().String(), revokedAt)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestAPIKeyRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAPIKeyRepository(db)
+
+	first, _, err := domain.NewAPIKey(domain.DefaultTenantID, "first")
+	require.NoError(t, err)
+	first.CreatedAt = time.Now().Add(-time.Hour)
+	require.NoError(t, repo.Create(context.Background(), first))
+	second, _, err := domain.NewAPIKey(domain.DefaultTenantID, "second")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), second))
+
+	other, _, err := domain.NewAPIKey("acme", "other tenant")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), other))
+
+	keys, err := repo.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "second", keys[0].Name)
+	assert.Equal(t, "first", keys[1].Name)
+
+	// Keys of other tenants can be neither listed nor revoked
+	err = repo.Revoke(context.Background(), other.ID.String(), time.Now())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
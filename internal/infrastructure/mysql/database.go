@@ -2,7 +2,6 @@ package mysql
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	mysqldriver "gorm.io/driver/mysql"
@@ -10,20 +9,26 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-// NewDatabase creates a new database connection using environment variables
-func NewDatabase() (*gorm.DB, error) {
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
+// Config holds the MySQL connection and pool settings
+type Config struct {
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD"`
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            int           `yaml:"port" env:"DB_PORT" default:"3306"`
+	Name            string        `yaml:"name" env:"DB_NAME"`
+	MaxOpenConns    int           `yaml:"maxOpenConns" env:"DB_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns    int           `yaml:"maxIdleConns" env:"DB_MAX_IDLE_CONNS" default:"5"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime" env:"DB_CONN_MAX_LIFETIME" default:"5m"`
+}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		dbUser,
-		dbPassword,
-		dbHost,
-		dbPort,
-		dbName,
+// NewDatabase creates a new database connection and verifies it
+func NewDatabase(cfg Config) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Name,
 	)
 
 	db, err := gorm.Open(mysqldriver.Open(dsn), &gorm.Config{
@@ -39,8 +44,46 @@ func NewDatabase() (*gorm.DB, error) {
 	if err := sqlDB.Ping(); err != nil {
 		return nil, err
 	}
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	return db, nil
 }
+
+// CloseDatabase closes the connection pool behind db
+func CloseDatabase(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// registerStatementCallbacks registers <prefix>:before_<operation> and
+// <prefix>:after_<operation> callbacks around GORM's create, query, update, delete,
+// row and raw callbacks
+func registerStatementCallbacks(db *gorm.DB, prefix string, before, after func(db *gorm.DB, operation string)) error {
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, p := range processors {
+		operation := p.operation
+		if err := p.before(prefix+":before_"+operation, func(db *gorm.DB) { before(db, operation) }); err != nil {
+			return err
+		}
+		if err := p.after(prefix+":after_"+operation, func(db *gorm.DB) { after(db, operation) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
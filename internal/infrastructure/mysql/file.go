@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FileRepository implements the FileRepository interface using MySQL with GORM
+type FileRepository struct {
+	db *gorm.DB
+}
+
+// NewFileRepository creates a new MySQL FileRepository
+func NewFileRepository(db *gorm.DB) *FileRepository {
+	return &FileRepository{db: db}
+}
+
+// Create inserts the metadata of an uploaded file
+func (r *FileRepository) Create(ctx context.Context, file *domain.File) error {
+	result := conn(ctx, r.db).Create(file)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// GetByID retrieves the metadata of a file of the caller's tenant by its ID
+func (r *FileRepository) GetByID(ctx context.Context, id string) (*domain.File, error) {
+	var file domain.File
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperrors.NewAppError("INVALID_ID", "invalid file id", http.StatusBadRequest, nil)
+	}
+	result := tenantConn(ctx, r.db).Where("id = ?", parsedID).First(&file)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewAppError("FILE_NOT_FOUND", "file not found", http.StatusNotFound, nil)
+		}
+		return nil, result.Error
+	}
+	return &file, nil
+}
+
+// Exists reports whether metadata for the given file ID is stored for the caller's tenant
+func (r *FileRepository) Exists(ctx context.Context, id string) (bool, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return false, nil
+	}
+	var count int64
+	result := tenantConn(ctx, r.db).Model(&domain.File{}).Where("id = ?", parsedID).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
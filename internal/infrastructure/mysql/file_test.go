@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRepository_GetByID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewFileRepository(db)
+
+	file := &domain.File{
+		ID:               uuid.New(),
+		OriginalFilename: "notes.txt",
+		Size:             12,
+		MimeType:         "text/plain; charset=utf-8",
+		SHA256:           "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72",
+	}
+	err := repo.Create(context.Background(), file)
+	require.NoError(t, err)
+
+	retrieved, err := repo.GetByID(context.Background(), file.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, file.OriginalFilename, retrieved.OriginalFilename)
+	assert.Equal(t, file.Size, retrieved.Size)
+	assert.Equal(t, file.SHA256, retrieved.SHA256)
+
+	// Test not found
+	_, err = repo.GetByID(context.Background(), uuid.New().String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestFileRepository_Exists(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewFileRepository(db)
+
+	file := &domain.File{
+		ID:               uuid.New(),
+		OriginalFilename: "notes.txt",
+		Size:             12,
+		MimeType:         "text/plain; charset=utf-8",
+		SHA256:           "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72",
+	}
+	require.NoError(t, repo.Create(context.Background(), file))
+
+	exists, err := repo.Exists(context.Background(), file.ID.String())
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.Exists(context.Background(), uuid.New().String())
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = repo.Exists(context.Background(), "not-a-uuid")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestFileRepository_TenantIsolation(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewFileRepository(db)
+
+	acme := domain.WithPrincipal(context.Background(), &domain.Principal{ID: "user-1", TenantID: "acme"})
+	globex := domain.WithPrincipal(context.Background(), &domain.Principal{ID: "user-2", TenantID: "globex"})
+
+	file := &domain.File{
+		ID:               uuid.New(),
+		OriginalFilename: "notes.txt",
+		Size:             12,
+		MimeType:         "text/plain; charset=utf-8",
+		SHA256:           "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72",
+	}
+	file.AssignOwner(domain.PrincipalFromContext(acme))
+	require.NoError(t, repo.Create(acme, file))
+
+	exists, err := repo.Exists(acme, file.ID.String())
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.Exists(globex, file.ID.String())
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = repo.GetByID(globex, file.ID.String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,26 @@
+package mysql
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// HealthChecker implements the HealthChecker interface by pinging the database
+type HealthChecker struct {
+	db *gorm.DB
+}
+
+// NewHealthChecker creates a new HealthChecker
+func NewHealthChecker(db *gorm.DB) *HealthChecker {
+	return &HealthChecker{db: db}
+}
+
+// Check pings the database over a pooled connection
+func (h *HealthChecker) Check(ctx context.Context) error {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,43 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+// metricsStartKey is the statement instance key holding the query start time
+const metricsStartKey = "metrics:start"
+
+// RegisterMetrics records the duration of every query run through db and exposes the
+// statistics of its connection pool
+func RegisterMetrics(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := metrics.RegisterDBStats(sqlDB, "mysql"); err != nil {
+		return err
+	}
+
+	return registerStatementCallbacks(db, "metrics", startQueryTimer, observeQuery)
+}
+
+// startQueryTimer stores the start time of a statement
+func startQueryTimer(db *gorm.DB, _ string) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+// observeQuery records the duration of a statement started by startQueryTimer
+func observeQuery(db *gorm.DB, operation string) {
+	value, ok := db.InstanceGet(metricsStartKey)
+	if !ok {
+		return
+	}
+	start, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+	metrics.ObserveDBQuery(operation, db.Statement.Table, time.Since(start))
+}
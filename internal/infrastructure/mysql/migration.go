@@ -7,7 +7,7 @@ import (
 
 // RunMigrations runs GORM AutoMigrate to create/update database schema
 func RunMigrations(db *gorm.DB) error {
-	if err := db.AutoMigrate(&domain.TodoItem{}); err != nil {
+	if err := db.AutoMigrate(&domain.TodoItem{}, &domain.OutboxMessage{}, &domain.File{}, &domain.APIKey{}, &domain.RoleAssignment{}); err != nil {
 		return err
 	}
 	return nil
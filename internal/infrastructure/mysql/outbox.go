@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxLastErrorLength = 1000
+)
+
+// OutboxRepository implements the OutboxRepository interface using MySQL with GORM
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new MySQL OutboxRepository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Add inserts a new outbox message, joining the transaction in ctx if any
+func (r *OutboxRepository) Add(ctx context.Context, message *domain.OutboxMessage) error {
+	result := conn(ctx, r.db).Create(message)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// FetchPending returns due, unleased messages in Seq order, taking only the oldest
+// unfailed message of each aggregate. Later messages of an aggregate stay hidden until
+// that one is deleted, so they cannot overtake it even while another relay holds its
+// row lock. Inside a transaction the rows stay locked and are skipped by concurrent
+// relays until it completes.
+func (r *OutboxRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	now := time.Now()
+	db := conn(ctx, r.db)
+	// A plain consistent read: rows locked by other relays still count as earlier messages
+	earlier := db.Session(&gorm.Session{NewDB: true}).
+		Table("outbox_messages AS earlier").
+		Select("1").
+		Where("earlier.aggregate_key = outbox_messages.aggregate_key").
+		Where("earlier.seq < outbox_messages.seq").
+		Where("earlier.failed_at IS NULL")
+
+	var messages []*domain.OutboxMessage
+	result := db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("failed_at IS NULL").
+		Where("next_attempt_at <= ?", now).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Where("aggregate_key = '' OR NOT EXISTS (?)", earlier).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return messages, nil
+}
+
+// Lease hides messages from FetchPending until the given time, so that they can be
+// published outside the transaction that fetched them
+func (r *OutboxRepository) Lease(ctx context.Context, ids []string, until time.Time) error {
+	result := conn(ctx, r.db).Model(&domain.OutboxMessage{}).Where("id IN ?", ids).Update("locked_until", until)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// Delete removes a message once it has been relayed
+func (r *OutboxRepository) Delete(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).Where("id = ?", id).Delete(&domain.OutboxMessage{})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// MarkFailed records a failed relay attempt, ends its lease and schedules the next one
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error {
+	result := conn(ctx, r.db).Model(&domain.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      truncateLastError(lastError),
+		"next_attempt_at": nextAttemptAt,
+		"locked_until":    nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// MarkExhausted records the last failed relay attempt of a message that ran out of
+// attempts. The message is kept with failed_at set but never relayed again.
+func (r *OutboxRepository) MarkExhausted(ctx context.Context, id string, lastError string) error {
+	result := conn(ctx, r.db).Model(&domain.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   truncateLastError(lastError),
+		"locked_until": nil,
+		"failed_at":    time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// truncateLastError shortens an error message to fit the last_error column
+func truncateLastError(lastError string) string {
+	if len(lastError) > maxLastErrorLength {
+		return lastError[:maxLastErrorLength]
+	}
+	return lastError
+}
@@ -0,0 +1,151 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository_FetchPending(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	due := domain.NewOutboxMessage("todo-items", "1", []byte(`{"id":"1"}`))
+	require.NoError(t, repo.Add(ctx, due))
+	later := domain.NewOutboxMessage("todo-items", "2", []byte(`{"id":"2"}`))
+	later.NextAttemptAt = time.Now().Add(time.Hour)
+	require.NoError(t, repo.Add(ctx, later))
+
+	pending, err := repo.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, due.ID, pending[0].ID)
+
+	require.NoError(t, repo.MarkFailed(ctx, due.ID.String(), "redis down", time.Now().Add(time.Hour)))
+	pending, err = repo.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	require.NoError(t, repo.Delete(ctx, due.ID.String()))
+}
+
+func TestOutboxRepository_FetchPendingKeepsAggregateOrder(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	first := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"1"}`))
+	require.NoError(t, repo.Add(ctx, first))
+	second := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"2"}`))
+	require.NoError(t, repo.Add(ctx, second))
+	other := domain.NewOutboxMessage("todo-items", "todo-2", []byte(`{"id":"3"}`))
+	require.NoError(t, repo.Add(ctx, other))
+
+	// Only the oldest message of each aggregate is returned
+	pending, err := repo.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, first.ID, pending[0].ID)
+	assert.Equal(t, other.ID, pending[1].ID)
+	assert.Less(t, pending[0].Seq, pending[1].Seq)
+
+	// Leased messages are hidden and hold back the later messages of their aggregate
+	require.NoError(t, repo.Lease(ctx, []string{first.ID.String(), other.ID.String()}, time.Now().Add(time.Minute)))
+	pending, err = repo.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// So do messages waiting for a retry
+	require.NoError(t, repo.MarkFailed(ctx, first.ID.String(), "redis down", time.Now().Add(time.Hour)))
+	pending, err = repo.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	require.NoError(t, repo.Delete(ctx, first.ID.String()))
+	pending, err = repo.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, second.ID, pending[0].ID)
+}
+
+func TestOutboxRepository_FetchPendingHeldByUncommittedLease(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewOutboxRepository(db)
+	txManager := NewTransactionManager(db)
+	ctx := context.Background()
+
+	first := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"1"}`))
+	require.NoError(t, repo.Add(ctx, first))
+	second := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"2"}`))
+	require.NoError(t, repo.Add(ctx, second))
+
+	// While one relay holds the row lock of the first message, another relay must not
+	// pick up the second
+	err := txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		pending, err := repo.FetchPending(txCtx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, first.ID, pending[0].ID)
+
+		concurrent, err := repo.FetchPending(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, concurrent)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestOutboxRepository_MarkExhausted(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	poison := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"1"}`))
+	require.NoError(t, repo.Add(ctx, poison))
+	next := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"2"}`))
+	require.NoError(t, repo.Add(ctx, next))
+
+	// A message that ran out of attempts is kept but no longer holds back its aggregate
+	require.NoError(t, repo.MarkExhausted(ctx, poison.ID.String(), "invalid payload"))
+	pending, err := repo.FetchPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, next.ID, pending[0].ID)
+
+	var stored domain.OutboxMessage
+	require.NoError(t, db.First(&stored, "id = ?", poison.ID.String()).Error)
+	assert.NotNil(t, stored.FailedAt)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, "invalid payload", stored.LastError)
+}
+
+func TestTransactionManager_Rollback(t *testing.T) {
+	db := setupTestDB(t)
+	txManager := NewTransactionManager(db)
+	todoRepo := NewTodoRepository(db)
+	outboxRepo := NewOutboxRepository(db)
+
+	todo := domain.NewTodoItem("Test description", time.Now().Add(24*time.Hour), "")
+	err := txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := todoRepo.Create(ctx, todo); err != nil {
+			return err
+		}
+		if err := outboxRepo.Add(ctx, domain.NewOutboxMessage("todo-items", "", []byte(`{}`))); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+
+	_, err = todoRepo.GetByID(context.Background(), todo.ID.String())
+	assert.Error(t, err)
+	pending, err := outboxRepo.FetchPending(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoleRepository implements the RoleRepository interface using MySQL with GORM
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new MySQL RoleRepository
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// Get retrieves the role assignment of a principal of the caller's tenant
+func (r *RoleRepository) Get(ctx context.Context, principalType domain.PrincipalType, principalID string) (*domain.RoleAssignment, error) {
+	var assignment domain.RoleAssignment
+	result := tenantConn(ctx, r.db).
+		Where("principal_type = ? AND principal_id = ?", principalType, principalID).
+		First(&assignment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewAppError("ROLE_ASSIGNMENT_NOT_FOUND", "role assignment not found", http.StatusNotFound, nil)
+		}
+		return nil, result.Error
+	}
+	return &assignment, nil
+}
+
+// Assign inserts a role assignment or updates the role of an existing one
+func (r *RoleRepository) Assign(ctx context.Context, assignment *domain.RoleAssignment) error {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(assignment)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// List retrieves the role assignments of the caller's tenant
+func (r *RoleRepository) List(ctx context.Context) ([]*domain.RoleAssignment, error) {
+	var assignments []*domain.RoleAssignment
+	result := tenantConn(ctx, r.db).Order("principal_type, principal_id").Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+// Delete removes the role assignment of a principal of the caller's tenant
+func (r *RoleRepository) Delete(ctx context.Context, principalType domain.PrincipalType, principalID string) error {
+	result := tenantConn(ctx, r.db).
+		Where("principal_type = ? AND principal_id = ?", principalType, principalID).
+		Delete(&domain.RoleAssignment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.NewAppError("ROLE_ASSIGNMENT_NOT_FOUND", "role assignment not found", http.StatusNotFound, nil)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleRepository_Assign(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRoleRepository(db)
+	ctx := context.Background()
+
+	assignment := &domain.RoleAssignment{
+		TenantID:      domain.DefaultTenantID,
+		PrincipalType: domain.PrincipalUser,
+		PrincipalID:   "user-1",
+		Role:          domain.RoleViewer,
+	}
+	require.NoError(t, repo.Assign(ctx, assignment))
+
+	// Assigning again replaces the role
+	require.NoError(t, repo.Assign(ctx, &domain.RoleAssignment{
+		TenantID:      domain.DefaultTenantID,
+		PrincipalType: domain.PrincipalUser,
+		PrincipalID:   "user-1",
+		Role:          domain.RoleEditor,
+	}))
+
+	retrieved, err := repo.Get(ctx, domain.PrincipalUser, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleEditor, retrieved.Role)
+
+	assignments, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, assignments, 1)
+
+	// Test not found
+	_, err = repo.Get(ctx, domain.PrincipalAPIKey, "user-1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestRoleRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRoleRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Assign(ctx, &domain.RoleAssignment{
+		TenantID:      domain.DefaultTenantID,
+		PrincipalType: domain.PrincipalUser,
+		PrincipalID:   "user-1",
+		Role:          domain.RoleAdmin,
+	}))
+
+	// Assignments of other tenants are invisible
+	acme := domain.WithPrincipal(ctx, &domain.Principal{ID: "admin", TenantID: "acme"})
+	_, err := repo.Get(acme, domain.PrincipalUser, "user-1")
+	assert.Error(t, err)
+	err = repo.Delete(acme, domain.PrincipalUser, "user-1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	require.NoError(t, repo.Delete(ctx, domain.PrincipalUser, "user-1"))
+	_, err = repo.Get(ctx, domain.PrincipalUser, "user-1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,13 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"gorm.io/gorm"
+)
+
+// tenantConn returns conn(ctx, db) restricted to rows of the tenant of ctx
+func tenantConn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return conn(ctx, db).Where("tenant_id = ?", domain.TenantFromContext(ctx))
+}
@@ -3,12 +3,15 @@ package mysql
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
 	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TodoRepository implements the TodoRepository interface using MySQL with GORM
@@ -23,21 +26,32 @@ func NewTodoRepository(db *gorm.DB) *TodoRepository {
 
 // Create inserts a new todo item into the database
 func (r *TodoRepository) Create(ctx context.Context, item *domain.TodoItem) error {
-	result := r.db.WithContext(ctx).Create(item)
+	result := conn(ctx, r.db).Create(item)
 	if result.Error != nil {
 		return result.Error
 	}
 	return nil
 }
 
-// GetByID retrieves a todo item by its ID
+// GetByID retrieves a todo item of the caller's tenant by its ID
 func (r *TodoRepository) GetByID(ctx context.Context, id string) (*domain.TodoItem, error) {
+	return r.getByID(tenantConn(ctx, r.db), id)
+}
+
+// GetByIDForUpdate retrieves a todo item of the caller's tenant by its ID and locks its row
+// until the surrounding transaction ends
+func (r *TodoRepository) GetByIDForUpdate(ctx context.Context, id string) (*domain.TodoItem, error) {
+	return r.getByID(tenantConn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+// getByID retrieves the todo item with the given ID using tx
+func (r *TodoRepository) getByID(tx *gorm.DB, id string) (*domain.TodoItem, error) {
 	var item domain.TodoItem
 	parsedID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, apperrors.NewAppError("INVALID_ID", "invalid todo item id", http.StatusBadRequest, nil)
 	}
-	result := r.db.WithContext(ctx).Where("id = ?", parsedID).First(&item)
+	result := tx.Where("id = ?", parsedID).First(&item)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil)
@@ -46,3 +60,89 @@ func (r *TodoRepository) GetByID(ctx context.Context, id string) (*domain.TodoIt
 	}
 	return &item, nil
 }
+
+// Update persists the mutable fields of an existing todo item of the caller's tenant
+func (r *TodoRepository) Update(ctx context.Context, item *domain.TodoItem) error {
+	result := tenantConn(ctx, r.db).Model(item).Select("*").Omit("id", "tenant_id", "owner_id", "created_at").Updates(item)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// Delete removes a todo item of the caller's tenant by its ID
+func (r *TodoRepository) Delete(ctx context.Context, id string) error {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return apperrors.NewAppError("INVALID_ID", "invalid todo item id", http.StatusBadRequest, nil)
+	}
+	result := tenantConn(ctx, r.db).Where("id = ?", parsedID).Delete(&domain.TodoItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil)
+	}
+	return nil
+}
+
+// sortColumns maps sortable fields to their database columns
+var sortColumns = map[repository.SortField]string{
+	repository.SortByDueDate:   "due_date",
+	repository.SortByCreatedAt: "created_at",
+}
+
+// List retrieves a page of the caller's tenant's todo items using keyset pagination on (sort column, id)
+func (r *TodoRepository) List(ctx context.Context, query repository.ListQuery) ([]*domain.TodoItem, error) {
+	column, ok := sortColumns[query.SortBy]
+	if !ok {
+		column = sortColumns[repository.SortByDueDate]
+	}
+	tx := tenantConn(ctx, r.db).Model(&domain.TodoItem{})
+	if query.DueBefore != nil {
+		tx = tx.Where("due_date < ?", *query.DueBefore)
+	}
+	if query.DueAfter != nil {
+		tx = tx.Where("due_date > ?", *query.DueAfter)
+	}
+	if query.CreatedBefore != nil {
+		tx = tx.Where("created_at < ?", *query.CreatedBefore)
+	}
+	if query.CreatedAfter != nil {
+		tx = tx.Where("created_at > ?", *query.CreatedAfter)
+	}
+	if query.UpdatedBefore != nil {
+		tx = tx.Where("updated_at < ?", *query.UpdatedBefore)
+	}
+	if query.UpdatedAfter != nil {
+		tx = tx.Where("updated_at > ?", *query.UpdatedAfter)
+	}
+	if query.HasAttachment != nil {
+		if *query.HasAttachment {
+			tx = tx.Where("file_id IS NOT NULL AND file_id <> ''")
+		} else {
+			tx = tx.Where("(file_id IS NULL OR file_id = '')")
+		}
+	}
+
+	direction, comparator := "ASC", ">"
+	if query.Descending {
+		direction, comparator = "DESC", "<"
+	}
+	if query.After != nil {
+		tx = tx.Where(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, comparator),
+			query.After.SortValue, query.After.SortValue, query.After.ID,
+		)
+	}
+	tx = tx.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction))
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+
+	var items []*domain.TodoItem
+	if err := tx.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
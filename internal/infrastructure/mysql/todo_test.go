@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,12 +33,16 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// AutoMigrate to create tables
-	err = db.AutoMigrate(&domain.TodoItem{})
+	err = db.AutoMigrate(&domain.TodoItem{}, &domain.OutboxMessage{}, &domain.File{}, &domain.APIKey{}, &domain.RoleAssignment{})
 	require.NoError(t, err)
 
 	// Clean up
 	t.Cleanup(func() {
 		db.Exec("DROP TABLE IF EXISTS todo_items")
+		db.Exec("DROP TABLE IF EXISTS outbox_messages")
+		db.Exec("DROP TABLE IF EXISTS files")
+		db.Exec("DROP TABLE IF EXISTS api_keys")
+		db.Exec("DROP TABLE IF EXISTS role_assignments")
 		sqlDB.Close()
 	})
 
@@ -80,3 +85,154 @@ func TestTodoRepository_GetByID(t *testing.T) {
 	assert.Contains(t, err.Error(), "not found")
 }
 
+func TestTodoRepository_GetByIDForUpdate(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTodoRepository(db)
+	txManager := NewTransactionManager(db)
+
+	todo := domain.NewTodoItem("Test description", time.Now().Add(24*time.Hour), "file-123")
+	err := repo.Create(context.Background(), todo)
+	require.NoError(t, err)
+
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		retrieved, err := repo.GetByIDForUpdate(ctx, todo.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, todo.ID, retrieved.ID)
+
+		// Another transaction cannot take the lock while it is held
+		var locked int64
+		err = db.Transaction(func(other *gorm.DB) error {
+			return other.Raw("SELECT COUNT(*) FROM todo_items WHERE id = ? FOR UPDATE NOWAIT", todo.ID).Scan(&locked).Error
+		})
+		assert.Error(t, err)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.GetByIDForUpdate(context.Background(), uuid.New().String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestTodoRepository_Update(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTodoRepository(db)
+
+	todo := domain.NewTodoItem("Test description", time.Now().Add(24*time.Hour), "file-123")
+	err := repo.Create(context.Background(), todo)
+	require.NoError(t, err)
+
+	todo.Description = "Updated description"
+	todo.FileID = ""
+	err = repo.Update(context.Background(), todo)
+	assert.NoError(t, err)
+
+	retrieved, err := repo.GetByID(context.Background(), todo.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated description", retrieved.Description)
+	assert.Empty(t, retrieved.FileID)
+}
+
+func TestTodoRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTodoRepository(db)
+
+	todo := domain.NewTodoItem("Test description", time.Now().Add(24*time.Hour), "file-123")
+	err := repo.Create(context.Background(), todo)
+	require.NoError(t, err)
+
+	err = repo.Delete(context.Background(), todo.ID.String())
+	assert.NoError(t, err)
+
+	_, err = repo.GetByID(context.Background(), todo.ID.String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	// Test not found
+	err = repo.Delete(context.Background(), todo.ID.String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestTodoRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTodoRepository(db)
+
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		fileID := ""
+		if i%2 == 0 {
+			fileID = "file-123"
+		}
+		todo := domain.NewTodoItem("Test description", now.Add(time.Duration(i)*time.Hour), fileID)
+		require.NoError(t, repo.Create(context.Background(), todo))
+	}
+
+	firstPage, err := repo.List(context.Background(), repository.ListQuery{SortBy: repository.SortByDueDate, Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, firstPage, 3)
+
+	last := firstPage[len(firstPage)-1]
+	secondPage, err := repo.List(context.Background(), repository.ListQuery{
+		SortBy: repository.SortByDueDate,
+		After:  &repository.ListCursor{SortValue: last.DueDate, ID: last.ID},
+		Limit:  3,
+	})
+	require.NoError(t, err)
+	assert.Len(t, secondPage, 2)
+	assert.True(t, secondPage[0].DueDate.After(last.DueDate))
+
+	hasAttachment := true
+	withFiles, err := repo.List(context.Background(), repository.ListQuery{HasAttachment: &hasAttachment})
+	require.NoError(t, err)
+	assert.Len(t, withFiles, 2)
+
+	dueBefore := now.Add(150 * time.Minute)
+	dueSoon, err := repo.List(context.Background(), repository.ListQuery{DueBefore: &dueBefore, Descending: true})
+	require.NoError(t, err)
+	assert.Len(t, dueSoon, 2)
+}
+
+func TestTodoRepository_TenantIsolation(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTodoRepository(db)
+
+	acme := domain.WithPrincipal(context.Background(), &domain.Principal{ID: "user-1", TenantID: "acme"})
+	globex := domain.WithPrincipal(context.Background(), &domain.Principal{ID: "user-2", TenantID: "globex"})
+
+	todo := domain.NewTodoItem("Acme task", time.Now().Add(24*time.Hour), "")
+	todo.AssignOwner(domain.PrincipalFromContext(acme))
+	require.NoError(t, repo.Create(acme, todo))
+
+	retrieved, err := repo.GetByID(acme, todo.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "acme", retrieved.TenantID)
+	assert.Equal(t, "user-1", retrieved.OwnerID)
+
+	_, err = repo.GetByID(globex, todo.ID.String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	items, err := repo.List(globex, repository.ListQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	err = repo.Delete(globex, todo.ID.String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	// Updates from another tenant leave the item untouched
+	todo.Description = "Changed by globex"
+	require.NoError(t, repo.Update(globex, todo))
+	retrieved, err = repo.GetByID(acme, todo.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Acme task", retrieved.Description)
+
+	// Items created without a principal belong to the default tenant
+	other := domain.NewTodoItem("Default task", time.Now().Add(24*time.Hour), "")
+	require.NoError(t, repo.Create(context.Background(), other))
+	items, err = repo.List(context.Background(), repository.ListQuery{})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, domain.DefaultTenantID, items[0].TenantID)
+}
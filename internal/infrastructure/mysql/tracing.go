@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"errors"
+
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tracingSpanKey is the statement instance key holding the statement's span
+const tracingSpanKey = "tracing:span"
+
+// tracer starts the spans of database statements
+var tracer = otel.Tracer("github.com/ar-agahian/ice-assignment/internal/infrastructure/mysql")
+
+// RegisterTracing runs every statement executed through db in a client span that is a
+// child of the span in the statement's context
+func RegisterTracing(db *gorm.DB) error {
+	return registerStatementCallbacks(db, "tracing", startStatementSpan, endStatementSpan)
+}
+
+// startStatementSpan starts the span of a statement
+func startStatementSpan(db *gorm.DB, operation string) {
+	name := operation
+	if db.Statement.Table != "" {
+		name += " " + db.Statement.Table
+	}
+	ctx, span := tracer.Start(db.Statement.Context, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameMySQL, semconv.DBOperationName(operation)),
+	)
+	db.Statement.Context = ctx
+	db.InstanceSet(tracingSpanKey, span)
+}
+
+// endStatementSpan records the executed SQL and outcome on the statement's span and
+// ends it. A missing record is a result, not a failure.
+func endStatementSpan(db *gorm.DB, _ string) {
+	value, ok := db.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(semconv.DBQueryText(db.Statement.SQL.String()))
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key under which the active transaction is stored
+type txKey struct{}
+
+// TransactionManager implements the ITransactionManager interface using GORM transactions
+type TransactionManager struct {
+	db *gorm.DB
+}
+
+// NewTransactionManager creates a new MySQL TransactionManager
+func NewTransactionManager(db *gorm.DB) *TransactionManager {
+	return &TransactionManager{db: db}
+}
+
+// WithinTransaction runs fn in a transaction, joining one already present in ctx
+func (m *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction stored in ctx, or db when there is none
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,106 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// StreamPublisher implements the StreamPublisher interface using NATS JetStream.
+// Each stream is published on the subject of the same name, stored in a JetStream
+// stream that is created on first use.
+type StreamPublisher struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	streams sync.Map
+}
+
+// Config holds the NATS connection settings
+type Config struct {
+	URL string `yaml:"url" env:"NATS_URL" default:"nats://127.0.0.1:4222"`
+}
+
+// NewStreamPublisher creates a new NATS JetStream StreamPublisher connected to cfg.URL
+func NewStreamPublisher(ctx context.Context, cfg Config) (*StreamPublisher, error) {
+	url := cfg.URL
+	if url == "" {
+		url = nats.DefaultURL
+	}
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := js.AccountInfo(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &StreamPublisher{conn: conn, js: js}, nil
+}
+
+// Publish publishes a JSON-encoded message to a JetStream subject. Event envelopes are
+// published with their id as Nats-Msg-Id so that JetStream drops retried duplicates.
+func (p *StreamPublisher) Publish(ctx context.Context, stream string, data map[string]interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := p.ensureStream(ctx, stream); err != nil {
+		return err
+	}
+	var opts []jetstream.PublishOpt
+	if id, ok := data["id"].(string); ok && data["type"] != nil {
+		opts = append(opts, jetstream.WithMsgID(id))
+	}
+	_, err = p.js.PublishMsg(ctx, &nats.Msg{Subject: stream, Data: jsonData}, opts...)
+	return err
+}
+
+// ensureStream creates the JetStream stream capturing a subject unless it already
+// exists; existing streams keep their configuration
+func (p *StreamPublisher) ensureStream(ctx context.Context, subject string) error {
+	if _, ok := p.streams.Load(subject); ok {
+		return nil
+	}
+	name := StreamName(subject)
+	_, err := p.js.Stream(ctx, name)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		_, err = p.js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:     name,
+			Subjects: []string{subject},
+		})
+		if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	p.streams.Store(subject, struct{}{})
+	return nil
+}
+
+// StreamName returns the JetStream stream name for a subject; stream names cannot contain dots
+func StreamName(subject string) string {
+	return strings.ReplaceAll(subject, ".", "_")
+}
+
+// Check fetches the JetStream account info and implements the HealthChecker interface
+func (p *StreamPublisher) Check(ctx context.Context) error {
+	_, err := p.js.AccountInfo(ctx)
+	return err
+}
+
+// Close drains and closes the NATS connection
+func (p *StreamPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,132 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/streamtest"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runServer starts an embedded JetStream-enabled NATS server and returns a config for it
+func runServer(t *testing.T) Config {
+	return Config{URL: startServer(t).ClientURL()}
+}
+
+// startServer starts an embedded JetStream-enabled NATS server that is shut down after the test
+func startServer(t *testing.T) *server.Server {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+func setupTestPublisher(t *testing.T, cfg Config) *StreamPublisher {
+	publisher, err := NewStreamPublisher(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { publisher.Close() })
+	return publisher
+}
+
+// readMessages fetches the first n messages of a subject's JetStream stream
+func readMessages(t *testing.T, publisher *StreamPublisher, subject string, n int) []map[string]interface{} {
+	ctx, cancel := context.WithTimeout(context.Background(), streamtest.ReadTimeout)
+	defer cancel()
+	stream, err := publisher.js.Stream(ctx, StreamName(subject))
+	require.NoError(t, err)
+	consumer, err := stream.OrderedConsumer(ctx, jetstream.OrderedConsumerConfig{})
+	require.NoError(t, err)
+
+	messages := make([]map[string]interface{}, 0, n)
+	for len(messages) < n && ctx.Err() == nil {
+		batch, err := consumer.Fetch(n-len(messages), jetstream.FetchMaxWait(time.Second))
+		require.NoError(t, err)
+		for msg := range batch.Messages() {
+			var data map[string]interface{}
+			require.NoError(t, json.Unmarshal(msg.Data(), &data))
+			messages = append(messages, data)
+		}
+	}
+	require.Len(t, messages, n)
+	return messages
+}
+
+func TestStreamPublisher_Conformance(t *testing.T) {
+	cfg := runServer(t)
+	streamtest.RunPublisherConformance(t, func(t *testing.T) streamtest.Backend {
+		publisher := setupTestPublisher(t, cfg)
+		return streamtest.Backend{
+			Publisher: publisher,
+			Read: func(t *testing.T, stream string, n int) []map[string]interface{} {
+				return readMessages(t, publisher, stream, n)
+			},
+		}
+	})
+}
+
+func TestStreamPublisher_DeduplicatesEvents(t *testing.T) {
+	cfg := runServer(t)
+	publisher := setupTestPublisher(t, cfg)
+	ctx := context.Background()
+	event := map[string]interface{}{"id": "event-1", "type": "todo.created"}
+
+	require.NoError(t, publisher.Publish(ctx, "todo-items", event))
+	require.NoError(t, publisher.Publish(ctx, "todo-items", event))
+
+	stream, err := publisher.js.Stream(ctx, StreamName("todo-items"))
+	require.NoError(t, err)
+	info, err := stream.Info(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), info.State.Msgs, "retried envelopes should be stored once")
+}
+
+func TestStreamPublisher_KeepsExistingStreamConfig(t *testing.T) {
+	cfg := runServer(t)
+	publisher := setupTestPublisher(t, cfg)
+	ctx := context.Background()
+	_, err := publisher.js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     StreamName("todo.items"),
+		Subjects: []string{"todo.items"},
+		MaxMsgs:  10,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.Publish(ctx, "todo.items", map[string]interface{}{"id": "1"}))
+
+	stream, err := publisher.js.Stream(ctx, "todo_items")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stream.CachedInfo().Config.MaxMsgs)
+}
+
+func TestStreamPublisher_Check(t *testing.T) {
+	srv := startServer(t)
+	publisher := setupTestPublisher(t, Config{URL: srv.ClientURL()})
+
+	assert.NoError(t, publisher.Check(context.Background()))
+
+	srv.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Error(t, publisher.Check(ctx))
+}
+
+func TestNewStreamPublisher_Unavailable(t *testing.T) {
+	_, err := NewStreamPublisher(context.Background(), Config{URL: "nats://127.0.0.1:1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Config holds the Redis connection and stream settings
+type Config struct {
+	Addr     string `yaml:"addr" env:"REDIS_ADDR" default:"localhost:6379"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"REDIS_DB"`
+	// StreamEncoding selects how published messages are encoded
+	StreamEncoding StreamEncoding `yaml:"streamEncoding" env:"REDIS_STREAM_ENCODING" default:"data"`
+	// CloudEventsSource is the source attribute of published CloudEvents
+	CloudEventsSource string `yaml:"cloudEventsSource" env:"CLOUDEVENTS_SOURCE" default:"/ice-assignment"`
+	// StreamRetention lists per-stream retention policies in the format accepted by
+	// ParseRetentionPolicies
+	StreamRetention    string        `yaml:"streamRetention" env:"REDIS_STREAM_RETENTION"`
+	StreamTrimInterval time.Duration `yaml:"streamTrimInterval" env:"REDIS_STREAM_TRIM_INTERVAL" default:"1m"`
+}
+
+// newClient creates a Redis client and verifies the connection
+func newClient(ctx context.Context, cfg Config) (*redis.Client, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
+		return nil, err
+	}
+	return rdb, nil
+}
@@ -0,0 +1,638 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultConcurrency   = 1
+	defaultBatchSize     = 10
+	defaultBlockTimeout  = 2 * time.Second
+	defaultClaimMinIdle  = time.Minute
+	defaultClaimInterval = 5 * time.Second
+	defaultStartID       = "$"
+	readErrorBackoff     = time.Second
+
+	defaultMaxDeliveries  = 5
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+)
+
+// claimOwnedScript claims pending messages for a consumer only if it still owns them,
+// setting their delivery counts, and returns the claimed IDs. Claiming resets the idle
+// time, so other consumers do not take the messages over. KEYS is the stream, ARGV the
+// group and consumer followed by pairs of message ID and delivery count.
+var claimOwnedScript = redis.NewScript(`
+local claimed = {}
+for i = 3, #ARGV, 2 do
+	local entry = redis.call('XPENDING', KEYS[1], ARGV[1], ARGV[i], ARGV[i], 1)[1]
+	if entry and entry[2] == ARGV[2] then
+		redis.call('XCLAIM', KEYS[1], ARGV[1], ARGV[2], 0, ARGV[i], 'RETRYCOUNT', ARGV[i + 1], 'JUSTID')
+		table.insert(claimed, ARGV[i])
+	end
+end
+return claimed
+`)
+
+// ConsumerConfig configures a StreamConsumer. Zero values fall back to defaults.
+type ConsumerConfig struct {
+	// Group is the consumer group shared by all instances of a service
+	Group string
+	// Consumer uniquely names this instance within the group
+	Consumer string
+	// Concurrency is the number of messages handled in parallel
+	Concurrency int
+	// BatchSize is the maximum number of messages fetched per read
+	BatchSize int64
+	// BlockTimeout bounds how long a read waits for new messages
+	BlockTimeout time.Duration
+	// ClaimMinIdle is how long a message stays pending without its consumer checking
+	// in before another consumer may take it over. Consumers check in on the messages
+	// they handle or wait to retry every ClaimInterval, so it must exceed that.
+	ClaimMinIdle time.Duration
+	// ClaimInterval is how often pending messages are checked for redelivery
+	ClaimInterval time.Duration
+	// StartID is where a newly created group starts reading ("$" for new
+	// messages only, "0" for the full stream history)
+	StartID string
+}
+
+// StreamConsumer implements the StreamConsumer interface using Redis Streams consumer groups
+type StreamConsumer struct {
+	client   *redis.Client
+	config   ConsumerConfig
+	mu       sync.Mutex
+	handlers map[string]registration
+	// inFlight holds the delivery count of each message dispatched to or being handled
+	// by this consumer
+	inFlight map[string]int
+	retries  map[string]pendingRetry
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// registration is a handler together with its retry policy
+type registration struct {
+	handler client.MessageHandler
+	policy  client.RetryPolicy
+}
+
+// pendingRetry is a failed message waiting for its backoff to elapse before redelivery
+type pendingRetry struct {
+	id      string
+	attempt int
+	backoff time.Duration
+	due     time.Time
+}
+
+// NewStreamConsumer creates a new Redis StreamConsumer
+func NewStreamConsumer(ctx context.Context, cfg Config, config ConsumerConfig) (*StreamConsumer, error) {
+	if config.Group == "" || config.Consumer == "" {
+		return nil, errors.New("consumer group and consumer name are required")
+	}
+	rdb, err := newClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamConsumer(rdb, config), nil
+}
+
+// newStreamConsumer creates a StreamConsumer on an existing client
+func newStreamConsumer(rdb *redis.Client, config ConsumerConfig) *StreamConsumer {
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultConcurrency
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.BlockTimeout <= 0 {
+		config.BlockTimeout = defaultBlockTimeout
+	}
+	if config.ClaimMinIdle <= 0 {
+		config.ClaimMinIdle = defaultClaimMinIdle
+	}
+	if config.ClaimInterval <= 0 {
+		config.ClaimInterval = defaultClaimInterval
+	}
+	if config.StartID == "" {
+		config.StartID = defaultStartID
+	}
+	return &StreamConsumer{
+		client:   rdb,
+		config:   config,
+		handlers: make(map[string]registration),
+		inFlight: make(map[string]int),
+		retries:  make(map[string]pendingRetry),
+	}
+}
+
+// Handle registers the handler for a stream. Handlers must be registered before Run.
+func (c *StreamConsumer) Handle(stream string, handler client.MessageHandler, policy client.RetryPolicy) {
+	if policy.MaxDeliveries <= 0 {
+		policy.MaxDeliveries = defaultMaxDeliveries
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaultInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultMaxBackoff
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[stream] = registration{handler: handler, policy: policy}
+}
+
+// Run consumes all registered streams until Stop is called or ctx is cancelled.
+// It returns once every in-flight message has been handled, after which the consumer
+// can be run again.
+func (c *StreamConsumer) Run(ctx context.Context) error {
+	streams := c.streams()
+	if len(streams) == 0 {
+		return errors.New("no stream handlers registered")
+	}
+
+	// Registered before any Redis call, so that Stop waits for this run however early
+	// it is called
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		cancel()
+		return errors.New("consumer is already running")
+	}
+	c.cancel = cancel
+	c.done = done
+	c.mu.Unlock()
+	defer func() {
+		cancel()
+		c.mu.Lock()
+		c.cancel = nil
+		c.done = nil
+		c.mu.Unlock()
+		close(done)
+	}()
+
+	for _, stream := range streams {
+		if err := c.ensureGroup(runCtx, stream); err != nil {
+			if runCtx.Err() != nil {
+				// Stopped before consuming anything
+				return nil
+			}
+			return err
+		}
+	}
+
+	// Handlers run on a context that outlives Stop so in-flight work can finish
+	handlerCtx := context.WithoutCancel(ctx)
+	jobs := make(chan *client.StreamMessage)
+	var workers sync.WaitGroup
+	for i := 0; i < c.config.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for msg := range jobs {
+				c.process(handlerCtx, msg)
+			}
+		}()
+	}
+
+	var fetchers sync.WaitGroup
+	fetchers.Add(2)
+	go func() {
+		defer fetchers.Done()
+		c.readLoop(runCtx, streams, jobs)
+	}()
+	go func() {
+		defer fetchers.Done()
+		c.claimLoop(runCtx, streams, jobs)
+	}()
+	fetchers.Wait()
+	close(jobs)
+	workers.Wait()
+	return nil
+}
+
+// Stop stops fetching new messages and waits until Run has returned, or until ctx is
+// done. It returns immediately when the consumer is not running.
+func (c *StreamConsumer) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close closes the Redis connection
+func (c *StreamConsumer) Close() error {
+	return c.client.Close()
+}
+
+// streams returns the registered stream names in a stable order
+func (c *StreamConsumer) streams() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	streams := make([]string, 0, len(c.handlers))
+	for stream := range c.handlers {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+	return streams
+}
+
+// registration returns the handler registered for a stream
+func (c *StreamConsumer) registration(stream string) registration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.handlers[stream]
+}
+
+// setInFlight marks a message as dispatched to or being handled by this consumer
+func (c *StreamConsumer) setInFlight(msg *client.StreamMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight[msg.Stream+"/"+msg.ID] = msg.Attempt
+}
+
+// clearInFlight unmarks a message once it has been handled or was not dispatched
+func (c *StreamConsumer) clearInFlight(stream, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inFlight, stream+"/"+id)
+}
+
+// ownsLocally reports whether a message is in flight or waiting for a retry on this consumer
+func (c *StreamConsumer) ownsLocally(stream, id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, inFlight := c.inFlight[stream+"/"+id]
+	_, retrying := c.retries[stream+"/"+id]
+	return inFlight || retrying
+}
+
+// ownedMessages returns the delivery counts of the messages of a stream that are in
+// flight or waiting for a retry on this consumer, by message ID
+func (c *StreamConsumer) ownedMessages(stream string) map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	owned := make(map[string]int)
+	for key, attempt := range c.inFlight {
+		if id, ok := strings.CutPrefix(key, stream+"/"); ok {
+			owned[id] = attempt
+		}
+	}
+	for key, retry := range c.retries {
+		if strings.HasPrefix(key, stream+"/") {
+			owned[retry.id] = retry.attempt
+		}
+	}
+	return owned
+}
+
+// scheduleRetry redelivers a failed message once backoff has elapsed
+func (c *StreamConsumer) scheduleRetry(msg *client.StreamMessage, backoff time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retries[msg.Stream+"/"+msg.ID] = pendingRetry{
+		id:      msg.ID,
+		attempt: msg.Attempt,
+		backoff: backoff,
+		due:     time.Now().Add(backoff),
+	}
+}
+
+// dueRetries removes and returns the retries of a stream whose backoff has elapsed
+func (c *StreamConsumer) dueRetries(stream string) []pendingRetry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	var due []pendingRetry
+	for key, retry := range c.retries {
+		if strings.HasPrefix(key, stream+"/") && !now.Before(retry.due) {
+			due = append(due, retry)
+			delete(c.retries, key)
+		}
+	}
+	return due
+}
+
+// cancelRetry drops the scheduled retry of a message, if any
+func (c *StreamConsumer) cancelRetry(stream, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.retries, stream+"/"+id)
+}
+
+// claimOwned claims the given messages for this consumer, setting their delivery
+// counts, unless another consumer took them over, and returns the IDs it claimed
+func (c *StreamConsumer) claimOwned(ctx context.Context, stream string, deliveries map[string]int) (map[string]bool, error) {
+	claimed := make(map[string]bool, len(deliveries))
+	if len(deliveries) == 0 {
+		return claimed, nil
+	}
+	args := make([]interface{}, 0, 2+2*len(deliveries))
+	args = append(args, c.config.Group, c.config.Consumer)
+	for id, count := range deliveries {
+		args = append(args, id, count)
+	}
+	ids, err := claimOwnedScript.Run(ctx, c.client, []string{stream}, args...).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		claimed[id] = true
+	}
+	return claimed, nil
+}
+
+// ensureGroup creates the consumer group (and stream) if it does not exist yet
+func (c *StreamConsumer) ensureGroup(ctx context.Context, stream string) error {
+	err := c.client.XGroupCreateMkStream(ctx, stream, c.config.Group, c.config.StartID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("create consumer group %s on %s: %w", c.config.Group, stream, err)
+	}
+	return nil
+}
+
+// readLoop fetches new messages for this consumer and dispatches them to the workers
+func (c *StreamConsumer) readLoop(ctx context.Context, streams []string, jobs chan<- *client.StreamMessage) {
+	args := make([]string, 0, len(streams)*2)
+	args = append(args, streams...)
+	for range streams {
+		args = append(args, ">")
+	}
+	for ctx.Err() == nil {
+		result, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.config.Group,
+			Consumer: c.config.Consumer,
+			Streams:  args,
+			Count:    c.config.BatchSize,
+			Block:    c.config.BlockTimeout,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			log.Printf("stream consumer: read: %v", err)
+			sleep(ctx, readErrorBackoff)
+			continue
+		}
+		for _, stream := range result {
+			for _, message := range stream.Messages {
+				if !c.dispatch(ctx, jobs, stream.Stream, message, 1) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// claimLoop periodically checks in on this consumer's messages, redelivers failed
+// messages and takes over messages of dead consumers
+func (c *StreamConsumer) claimLoop(ctx context.Context, streams []string, jobs chan<- *client.StreamMessage) {
+	ticker := time.NewTicker(c.config.ClaimInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, stream := range streams {
+			if !c.claimPending(ctx, stream, jobs) {
+				return
+			}
+		}
+	}
+}
+
+// claimPending keeps the messages this consumer handles or waits to retry, redelivers
+// its failed messages whose backoff has elapsed and takes over messages left pending
+// for ClaimMinIdle, e.g. by a consumer that died; it returns false once ctx is done
+func (c *StreamConsumer) claimPending(ctx context.Context, stream string, jobs chan<- *client.StreamMessage) bool {
+	return c.keepOwned(ctx, stream) && c.redeliverRetries(ctx, stream, jobs) && c.claimIdle(ctx, stream, jobs)
+}
+
+// keepOwned resets the idle time of the messages of a stream that this consumer
+// handles or waits to retry, so that no other consumer takes them over however long
+// the handler or backoff takes. Retries of messages another consumer took over in the
+// meantime are dropped.
+func (c *StreamConsumer) keepOwned(ctx context.Context, stream string) bool {
+	owned := c.ownedMessages(stream)
+	claimed, err := c.claimOwned(ctx, stream, owned)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		log.Printf("stream consumer: keep %s: %v", stream, err)
+		return true
+	}
+	for id := range owned {
+		if !claimed[id] {
+			c.cancelRetry(stream, id)
+		}
+	}
+	return true
+}
+
+// redeliverRetries claims and dispatches the failed messages of a stream whose backoff
+// has elapsed. A message that was taken over by another consumer in the meantime is
+// left to that consumer.
+func (c *StreamConsumer) redeliverRetries(ctx context.Context, stream string, jobs chan<- *client.StreamMessage) bool {
+	due := c.dueRetries(stream)
+	deliveries := make(map[string]int, len(due))
+	for _, retry := range due {
+		deliveries[retry.id] = retry.attempt + 1
+	}
+	claimed, err := c.claimOwned(ctx, stream, deliveries)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		// The messages stay pending and are claimed again after ClaimMinIdle
+		log.Printf("stream consumer: claim %s: %v", stream, err)
+		return true
+	}
+	for _, retry := range due {
+		if !claimed[retry.id] {
+			continue
+		}
+		messages, err := c.client.XRange(ctx, stream, retry.id, retry.id).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+			log.Printf("stream consumer: read %s/%s: %v", stream, retry.id, err)
+			continue
+		}
+		for _, message := range messages {
+			if !c.dispatch(ctx, jobs, stream, message, retry.attempt+1) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// claimIdle takes over the messages of a stream that stayed pending for ClaimMinIdle and
+// dead-letters those that exhausted their delivery attempts. Messages this consumer
+// handles or waits to retry are left alone.
+func (c *StreamConsumer) claimIdle(ctx context.Context, stream string, jobs chan<- *client.StreamMessage) bool {
+	policy := c.registration(stream).policy
+	start := "-"
+	for {
+		entries, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: stream,
+			Group:  c.config.Group,
+			Idle:   c.config.ClaimMinIdle,
+			Start:  start,
+			End:    "+",
+			Count:  c.config.BatchSize,
+		}).Result()
+		var messages []redis.XMessage
+		deliveries := make(map[string]int64, len(entries))
+		if err == nil {
+			ids := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				if entry.Consumer == c.config.Consumer && c.ownsLocally(stream, entry.ID) {
+					continue
+				}
+				ids = append(ids, entry.ID)
+				// Claiming counts as another delivery
+				deliveries[entry.ID] = entry.RetryCount + 1
+			}
+			if len(ids) > 0 {
+				messages, err = c.client.XClaim(ctx, &redis.XClaimArgs{
+					Stream:   stream,
+					Group:    c.config.Group,
+					Consumer: c.config.Consumer,
+					MinIdle:  c.config.ClaimMinIdle,
+					Messages: ids,
+				}).Result()
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+			log.Printf("stream consumer: claim %s: %v", stream, err)
+			return true
+		}
+		for _, message := range messages {
+			count := deliveries[message.ID]
+			if count > int64(policy.MaxDeliveries) {
+				c.deadLetter(ctx, newStreamMessage(stream, message, int(count-1)), "delivery limit exceeded")
+				continue
+			}
+			if !c.dispatch(ctx, jobs, stream, message, int(count)) {
+				return false
+			}
+		}
+		if int64(len(entries)) < c.config.BatchSize {
+			return true
+		}
+		start = nextStreamID(entries[len(entries)-1].ID)
+	}
+}
+
+// retryBackoff returns how long a message delivered the given number of times waits before redelivery
+func retryBackoff(policy client.RetryPolicy, deliveries int64) time.Duration {
+	backoff := policy.InitialBackoff
+	for i := int64(1); i < deliveries && backoff < policy.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > policy.MaxBackoff {
+		return policy.MaxBackoff
+	}
+	return backoff
+}
+
+// process runs the stream's handler and acknowledges the message on success.
+// A message that fails its last allowed attempt is dead-lettered; others are retried
+// once their backoff has elapsed.
+func (c *StreamConsumer) process(ctx context.Context, msg *client.StreamMessage) {
+	defer c.clearInFlight(msg.Stream, msg.ID)
+	reg := c.registration(msg.Stream)
+	if err := safeHandle(ctx, reg.handler, msg); err != nil {
+		log.Printf("stream consumer: handle %s/%s (attempt %d): %v", msg.Stream, msg.ID, msg.Attempt, err)
+		if msg.Attempt >= reg.policy.MaxDeliveries {
+			c.deadLetter(ctx, msg, err.Error())
+			return
+		}
+		c.scheduleRetry(msg, retryBackoff(reg.policy, int64(msg.Attempt)))
+		return
+	}
+	if err := c.client.XAck(ctx, msg.Stream, c.config.Group, msg.ID).Err(); err != nil {
+		log.Printf("stream consumer: ack %s/%s: %v", msg.Stream, msg.ID, err)
+	}
+}
+
+// safeHandle invokes handler, turning a panic into an error
+func safeHandle(ctx context.Context, handler client.MessageHandler, msg *client.StreamMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	return handler(ctx, msg)
+}
+
+// deadLetter moves a message to its dead-letter stream
+func (c *StreamConsumer) deadLetter(ctx context.Context, msg *client.StreamMessage, reason string) {
+	if err := deadLetter(ctx, c.client, c.config.Group, msg, reason); err != nil {
+		log.Printf("stream consumer: %v", err)
+		return
+	}
+	log.Printf("stream consumer: dead-lettered %s/%s after %d attempts: %s", msg.Stream, msg.ID, msg.Attempt, reason)
+}
+
+// dispatch hands a message to the workers; it returns false once ctx is done
+func (c *StreamConsumer) dispatch(ctx context.Context, jobs chan<- *client.StreamMessage, stream string, message redis.XMessage, attempt int) bool {
+	msg := newStreamMessage(stream, message, attempt)
+	c.setInFlight(msg)
+	select {
+	case jobs <- msg:
+		return true
+	case <-ctx.Done():
+		c.clearInFlight(stream, message.ID)
+		return false
+	}
+}
+
+// newStreamMessage converts a Redis stream entry into a StreamMessage
+func newStreamMessage(stream string, message redis.XMessage, attempt int) *client.StreamMessage {
+	return &client.StreamMessage{
+		ID:      message.ID,
+		Stream:  stream,
+		Values:  message.Values,
+		Attempt: attempt,
+	}
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,353 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestConsumer(t *testing.T, config ConsumerConfig) (*StreamPublisher, *StreamConsumer, string) {
+	ctx := context.Background()
+
+	// Skip if Redis is not available
+	publisher, err := NewStreamPublisher(ctx, testConfig)
+	if err != nil {
+		t.Skipf("Skipping test: Redis not available: %v", err)
+	}
+	t.Cleanup(func() { publisher.Close() })
+
+	stream := "test-stream-" + uuid.NewString()
+	config.Group = "test-group"
+	config.Consumer = "test-consumer"
+	config.StartID = "0"
+	consumer, err := NewStreamConsumer(ctx, testConfig, config)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		consumer.client.Del(ctx, stream)
+		consumer.Close()
+	})
+	return publisher, consumer, stream
+}
+
+func TestStreamConsumer_Run(t *testing.T) {
+	ctx := context.Background()
+	publisher, consumer, stream := setupTestConsumer(t, ConsumerConfig{Concurrency: 4})
+
+	const messages = 10
+	received := make(chan *client.StreamMessage, messages)
+	consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+		received <- msg
+		return nil
+	}, client.RetryPolicy{})
+	for i := 0; i < messages; i++ {
+		require.NoError(t, publisher.Publish(ctx, stream, map[string]interface{}{"n": i}))
+	}
+
+	go consumer.Run(ctx)
+	for i := 0; i < messages; i++ {
+		select {
+		case msg := <-received:
+			assert.Equal(t, stream, msg.Stream)
+			assert.Contains(t, msg.Values, "data")
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d of %d messages", i, messages)
+		}
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, consumer.Stop(stopCtx))
+
+	pending, err := consumer.client.XPending(ctx, stream, "test-group").Result()
+	require.NoError(t, err)
+	assert.Zero(t, pending.Count, "all messages should be acknowledged")
+}
+
+func TestStreamConsumer_RedeliversFailedMessages(t *testing.T) {
+	ctx := context.Background()
+	publisher, consumer, stream := setupTestConsumer(t, ConsumerConfig{
+		ClaimInterval: 100 * time.Millisecond,
+	})
+
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("transient failure")
+		}
+		assert.Equal(t, 2, msg.Attempt)
+		close(done)
+		return nil
+	}, client.RetryPolicy{InitialBackoff: 100 * time.Millisecond})
+	require.NoError(t, publisher.Publish(ctx, stream, map[string]interface{}{"id": "1"}))
+
+	go consumer.Run(ctx)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed message was not redelivered")
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, consumer.Stop(stopCtx))
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestStreamConsumer_StopWaitsForInFlightHandlers(t *testing.T) {
+	ctx := context.Background()
+	publisher, consumer, stream := setupTestConsumer(t, ConsumerConfig{})
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		finished.Store(true)
+		return nil
+	}, client.RetryPolicy{})
+	require.NoError(t, publisher.Publish(ctx, stream, map[string]interface{}{"id": "1"}))
+
+	go consumer.Run(ctx)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler was not invoked")
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, consumer.Stop(stopCtx))
+	assert.True(t, finished.Load())
+}
+
+func TestStreamConsumer_DeadLettersExhaustedMessages(t *testing.T) {
+	ctx := context.Background()
+	publisher, consumer, stream := setupTestConsumer(t, ConsumerConfig{
+		ClaimInterval: 100 * time.Millisecond,
+	})
+	t.Cleanup(func() { consumer.client.Del(ctx, DeadLetterStream(stream)) })
+
+	var attempts atomic.Int32
+	consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+		attempts.Add(1)
+		return errors.New("permanent failure")
+	}, client.RetryPolicy{MaxDeliveries: 3, InitialBackoff: 50 * time.Millisecond})
+	require.NoError(t, publisher.Publish(ctx, stream, map[string]interface{}{"id": "1"}))
+
+	go consumer.Run(ctx)
+	store := NewDeadLetterStore(publisher)
+	require.Eventually(t, func() bool {
+		letters, err := store.List(ctx, stream, "", 10)
+		return err == nil && len(letters) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, consumer.Stop(stopCtx))
+	assert.Equal(t, int32(3), attempts.Load())
+
+	letters, err := store.List(ctx, stream, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, stream, letters[0].Stream)
+	assert.Equal(t, "permanent failure", letters[0].Error)
+	assert.Equal(t, 3, letters[0].Attempts)
+	assert.Equal(t, `{"id":"1"}`, letters[0].Values["data"])
+
+	pending, err := consumer.client.XPending(ctx, stream, "test-group").Result()
+	require.NoError(t, err)
+	assert.Zero(t, pending.Count, "dead-lettered messages should be acknowledged")
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := client.RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, retryBackoff(policy, 1))
+	assert.Equal(t, 2*time.Second, retryBackoff(policy, 2))
+	assert.Equal(t, 4*time.Second, retryBackoff(policy, 3))
+	assert.Equal(t, 5*time.Second, retryBackoff(policy, 4))
+	assert.Equal(t, 5*time.Second, retryBackoff(policy, 100))
+}
+
+func TestStreamConsumer_RunWithoutHandlers(t *testing.T) {
+	consumer := newStreamConsumer(nil, ConsumerConfig{Group: "g", Consumer: "c"})
+	err := consumer.Run(context.Background())
+	assert.EqualError(t, err, "no stream handlers registered")
+}
+
+func TestStreamConsumer_ClaimsIdleMessages(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxDeliveries int
+		wantHandled   bool
+	}{
+		{name: "redelivers messages of a dead consumer", maxDeliveries: 3, wantHandled: true},
+		{name: "dead-letters exhausted messages", maxDeliveries: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			publisher, consumer, stream := setupTestConsumer(t, ConsumerConfig{
+				ClaimMinIdle:  100 * time.Millisecond,
+				ClaimInterval: 100 * time.Millisecond,
+			})
+			t.Cleanup(func() { consumer.client.Del(ctx, DeadLetterStream(stream)) })
+			require.NoError(t, publisher.Publish(ctx, stream, map[string]interface{}{"id": "1"}))
+			// A consumer that read the message and died before acknowledging it
+			require.NoError(t, consumer.client.XGroupCreate(ctx, stream, "test-group", "0").Err())
+			require.NoError(t, consumer.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    "test-group",
+				Consumer: "dead-consumer",
+				Streams:  []string{stream, ">"},
+			}).Err())
+
+			handled := make(chan int, 1)
+			consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+				handled <- msg.Attempt
+				return nil
+			}, client.RetryPolicy{MaxDeliveries: tt.maxDeliveries})
+
+			go consumer.Run(ctx)
+			if tt.wantHandled {
+				select {
+				case attempt := <-handled:
+					assert.Equal(t, 2, attempt)
+				case <-time.After(5 * time.Second):
+					t.Fatal("idle message was not claimed")
+				}
+			} else {
+				store := NewDeadLetterStore(publisher)
+				require.Eventually(t, func() bool {
+					letters, err := store.List(ctx, stream, "", 10)
+					return err == nil && len(letters) == 1 && letters[0].Attempts == 1
+				}, 5*time.Second, 50*time.Millisecond)
+				assert.Empty(t, handled)
+			}
+
+			stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			require.NoError(t, consumer.Stop(stopCtx))
+		})
+	}
+}
+
+func TestStreamConsumer_KeepsOwnedMessages(t *testing.T) {
+	tests := []struct {
+		name        string
+		handleDelay time.Duration
+		policy      client.RetryPolicy
+	}{
+		{name: "slow handler", handleDelay: 600 * time.Millisecond},
+		{name: "backoff longer than ClaimMinIdle", policy: client.RetryPolicy{InitialBackoff: 600 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			publisher, consumer, stream := setupTestConsumer(t, ConsumerConfig{
+				ClaimMinIdle:  200 * time.Millisecond,
+				ClaimInterval: 50 * time.Millisecond,
+			})
+
+			var attempts atomic.Int32
+			done := make(chan int, 1)
+			consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+				time.Sleep(tt.handleDelay)
+				if tt.policy.InitialBackoff > 0 && attempts.Add(1) == 1 {
+					return errors.New("transient failure")
+				}
+				done <- msg.Attempt
+				return nil
+			}, tt.policy)
+			require.NoError(t, publisher.Publish(ctx, stream, map[string]interface{}{"id": "1"}))
+
+			go consumer.Run(ctx)
+			// Another consumer of the group looking for abandoned messages finds none
+			deadline := time.After(500 * time.Millisecond)
+			for polling := true; polling; {
+				select {
+				case <-deadline:
+					polling = false
+				case <-time.After(50 * time.Millisecond):
+					claimed, _, err := consumer.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+						Stream:   stream,
+						Group:    "test-group",
+						Consumer: "other-consumer",
+						MinIdle:  200 * time.Millisecond,
+						Start:    "0-0",
+					}).Result()
+					require.NoError(t, err)
+					require.Empty(t, claimed)
+				}
+			}
+
+			select {
+			case attempt := <-done:
+				expected := 1
+				if tt.policy.InitialBackoff > 0 {
+					expected = 2
+				}
+				assert.Equal(t, expected, attempt)
+			case <-time.After(5 * time.Second):
+				t.Fatal("message was not handled")
+			}
+
+			stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			require.NoError(t, consumer.Stop(stopCtx))
+		})
+	}
+}
+
+func TestStreamConsumer_Restart(t *testing.T) {
+	ctx := context.Background()
+	publisher, consumer, stream := setupTestConsumer(t, ConsumerConfig{})
+
+	received := make(chan string, 2)
+	consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+		received <- msg.ID
+		return nil
+	}, client.RetryPolicy{})
+
+	for run := 1; run <= 2; run++ {
+		require.NoError(t, publisher.Publish(ctx, stream, map[string]interface{}{"run": run}))
+		errCh := make(chan error, 1)
+		go func() { errCh <- consumer.Run(ctx) }()
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("run %d received no message", run)
+		}
+
+		stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		require.NoError(t, consumer.Stop(stopCtx))
+		cancel()
+		select {
+		case err := <-errCh:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatalf("run %d still running after Stop", run)
+		}
+	}
+}
+
+func TestStreamConsumer_RunStoppedBeforeStart(t *testing.T) {
+	_, consumer, stream := setupTestConsumer(t, ConsumerConfig{})
+	consumer.Handle(stream, func(ctx context.Context, msg *client.StreamMessage) error {
+		return nil
+	}, client.RetryPolicy{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, consumer.Run(ctx))
+	assert.NoError(t, consumer.Stop(context.Background()))
+}
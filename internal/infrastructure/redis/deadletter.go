@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const deadLetterSuffix = ".dlq"
+
+// replayScript removes a dead-letter entry and republishes its values only if it was
+// still there, so that concurrent replays of an entry publish it once. KEYS are the
+// dead-letter and original streams, ARGV the entry ID followed by the field/value pairs.
+var replayScript = redis.NewScript(`
+if redis.call('XDEL', KEYS[1], ARGV[1]) == 0 then
+	return false
+end
+return redis.call('XADD', KEYS[2], '*', unpack(ARGV, 2))
+`)
+
+// DeadLetterStream returns the name of a stream's dead-letter stream
+func DeadLetterStream(stream string) string {
+	return stream + deadLetterSuffix
+}
+
+// DeadLetterStore implements the DeadLetterStore interface on Redis dead-letter streams
+type DeadLetterStore struct {
+	client *redis.Client
+}
+
+// NewDeadLetterStore creates a new DeadLetterStore sharing the publisher's Redis connection
+func NewDeadLetterStore(publisher *StreamPublisher) *DeadLetterStore {
+	return &DeadLetterStore{client: publisher.client}
+}
+
+// List returns up to limit dead-lettered entries after the given entry ID
+func (s *DeadLetterStore) List(ctx context.Context, stream string, after string, limit int64) ([]*client.DeadLetter, error) {
+	start := "-"
+	if after != "" {
+		start = "(" + after
+	}
+	messages, err := s.client.XRangeN(ctx, DeadLetterStream(stream), start, "+", limit).Result()
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]*client.DeadLetter, 0, len(messages))
+	for _, message := range messages {
+		letters = append(letters, decodeDeadLetter(message))
+	}
+	return letters, nil
+}
+
+// Replay republishes a dead-lettered entry to its original stream and removes it from the
+// dead-letter stream. Of concurrent replays of the same entry only one succeeds; the others
+// find it gone.
+func (s *DeadLetterStore) Replay(ctx context.Context, stream string, id string) (string, error) {
+	dlq := DeadLetterStream(stream)
+	messages, err := s.client.XRange(ctx, dlq, id, id).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "", errDeadLetterNotFound()
+	}
+	letter := decodeDeadLetter(messages[0])
+
+	fields := make([]string, 0, len(letter.Values))
+	for field := range letter.Values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	args := make([]interface{}, 0, 1+2*len(fields))
+	args = append(args, id)
+	for _, field := range fields {
+		args = append(args, field, letter.Values[field])
+	}
+
+	newID, err := replayScript.Run(ctx, s.client, []string{dlq, letter.Stream}, args...).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", errDeadLetterNotFound()
+	}
+	if err != nil {
+		return "", err
+	}
+	return newID, nil
+}
+
+// errDeadLetterNotFound returns the error for a dead-letter entry that does not exist
+func errDeadLetterNotFound() error {
+	return apperrors.NewAppError("DEAD_LETTER_NOT_FOUND", "dead letter not found", http.StatusNotFound, nil)
+}
+
+// Purge removes the given entries, or the whole dead-letter stream when ids is empty
+func (s *DeadLetterStore) Purge(ctx context.Context, stream string, ids []string) (int64, error) {
+	dlq := DeadLetterStream(stream)
+	if len(ids) > 0 {
+		return s.client.XDel(ctx, dlq, ids...).Result()
+	}
+	var length *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		length = pipe.XLen(ctx, dlq)
+		pipe.Del(ctx, dlq)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return length.Val(), nil
+}
+
+// deadLetter moves a message to its dead-letter stream and acknowledges it in one transaction
+func deadLetter(ctx context.Context, rdb *redis.Client, group string, msg *client.StreamMessage, reason string) error {
+	payload, err := json.Marshal(msg.Values)
+	if err != nil {
+		return err
+	}
+	_, err = rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: DeadLetterStream(msg.Stream),
+			Values: map[string]interface{}{
+				"stream":    msg.Stream,
+				"messageId": msg.ID,
+				"group":     group,
+				"payload":   string(payload),
+				"error":     reason,
+				"attempts":  msg.Attempt,
+				"failedAt":  time.Now().UTC().Format(time.RFC3339Nano),
+			},
+		})
+		pipe.XAck(ctx, msg.Stream, group, msg.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("dead-letter %s/%s: %w", msg.Stream, msg.ID, err)
+	}
+	return nil
+}
+
+// decodeDeadLetter converts a dead-letter stream entry into a DeadLetter
+func decodeDeadLetter(message redis.XMessage) *client.DeadLetter {
+	field := func(name string) string {
+		value, _ := message.Values[name].(string)
+		return value
+	}
+	letter := &client.DeadLetter{
+		ID:        message.ID,
+		Stream:    field("stream"),
+		MessageID: field("messageId"),
+		Group:     field("group"),
+		Error:     field("error"),
+	}
+	letter.Attempts, _ = strconv.Atoi(field("attempts"))
+	letter.FailedAt, _ = time.Parse(time.RFC3339Nano, field("failedAt"))
+	if err := json.Unmarshal([]byte(field("payload")), &letter.Values); err != nil {
+		letter.Values = map[string]interface{}{}
+	}
+	return letter
+}
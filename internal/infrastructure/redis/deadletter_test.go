@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterStore(t *testing.T) {
+	ctx := context.Background()
+
+	// Skip if Redis is not available
+	publisher, err := NewStreamPublisher(ctx, testConfig)
+	if err != nil {
+		t.Skipf("Skipping test: Redis not available: %v", err)
+	}
+	defer publisher.Close()
+
+	stream := "test-stream-" + uuid.NewString()
+	defer publisher.client.Del(ctx, stream, DeadLetterStream(stream))
+
+	for _, id := range []string{"1-0", "2-0", "3-0", "4-0"} {
+		err := deadLetter(ctx, publisher.client, "test-group", &client.StreamMessage{
+			ID:      id,
+			Stream:  stream,
+			Values:  map[string]interface{}{"data": `{"id":"` + id + `"}`},
+			Attempt: 5,
+		}, "boom")
+		require.NoError(t, err)
+	}
+	store := NewDeadLetterStore(publisher)
+
+	t.Run("list pages through entries", func(t *testing.T) {
+		first, err := store.List(ctx, stream, "", 2)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		assert.Equal(t, "1-0", first[0].MessageID)
+		assert.Equal(t, "boom", first[0].Error)
+		assert.Equal(t, 5, first[0].Attempts)
+		assert.False(t, first[0].FailedAt.IsZero())
+
+		rest, err := store.List(ctx, stream, first[1].ID, 2)
+		require.NoError(t, err)
+		require.Len(t, rest, 2)
+		assert.Equal(t, "3-0", rest[0].MessageID)
+	})
+
+	t.Run("replay republishes the original payload", func(t *testing.T) {
+		letters, err := store.List(ctx, stream, "", 1)
+		require.NoError(t, err)
+
+		newID, err := store.Replay(ctx, stream, letters[0].ID)
+		require.NoError(t, err)
+		messages, err := publisher.client.XRange(ctx, stream, newID, newID).Result()
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, `{"id":"1-0"}`, messages[0].Values["data"])
+
+		_, err = store.Replay(ctx, stream, letters[0].ID)
+		appErr, ok := apperrors.AsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, "DEAD_LETTER_NOT_FOUND", appErr.Code)
+	})
+
+	t.Run("concurrent replays publish once", func(t *testing.T) {
+		letters, err := store.List(ctx, stream, "", 1)
+		require.NoError(t, err)
+		before, err := publisher.client.XLen(ctx, stream).Result()
+		require.NoError(t, err)
+
+		const replays = 5
+		var wg sync.WaitGroup
+		var succeeded atomic.Int32
+		for i := 0; i < replays; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := store.Replay(ctx, stream, letters[0].ID); err == nil {
+					succeeded.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), succeeded.Load())
+		after, err := publisher.client.XLen(ctx, stream).Result()
+		require.NoError(t, err)
+		assert.Equal(t, before+1, after)
+	})
+
+	t.Run("purge removes entries", func(t *testing.T) {
+		letters, err := store.List(ctx, stream, "", 10)
+		require.NoError(t, err)
+		require.Len(t, letters, 2)
+
+		removed, err := store.Purge(ctx, stream, []string{letters[0].ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		removed, err = store.Purge(ctx, stream, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		letters, err = store.List(ctx, stream, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, letters)
+	})
+}
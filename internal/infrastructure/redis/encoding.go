@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/pkg/cloudevents"
+	"github.com/ar-agahian/ice-assignment/schemas"
+	"github.com/google/uuid"
+)
+
+// StreamEncoding selects how published messages are mapped to stream entry fields
+type StreamEncoding string
+
+const (
+	// EncodingData writes the message as JSON into a single data field
+	EncodingData StreamEncoding = "data"
+	// EncodingCloudEventsStructured writes a CloudEvents 1.0 JSON document into the data field
+	EncodingCloudEventsStructured StreamEncoding = "cloudevents-structured"
+	// EncodingCloudEventsBinary writes CloudEvents attributes as ce_ fields and the payload into the data field
+	EncodingCloudEventsBinary StreamEncoding = "cloudevents-binary"
+
+	defaultCloudEventsSource = "/ice-assignment"
+)
+
+// ParseStreamEncoding parses a stream encoding name; an empty name selects EncodingData
+func ParseStreamEncoding(name string) (StreamEncoding, error) {
+	switch encoding := StreamEncoding(name); encoding {
+	case "":
+		return EncodingData, nil
+	case EncodingData, EncodingCloudEventsStructured, EncodingCloudEventsBinary:
+		return encoding, nil
+	default:
+		return "", fmt.Errorf("unknown stream encoding %q", name)
+	}
+}
+
+// encodeMessage maps a message to stream entry fields using the given encoding
+func encodeMessage(encoding StreamEncoding, source, stream string, data map[string]interface{}) (map[string]interface{}, error) {
+	switch encoding {
+	case EncodingCloudEventsStructured:
+		event, err := newCloudEvent(source, stream, data)
+		if err != nil {
+			return nil, err
+		}
+		return cloudevents.EncodeStructured(event)
+	case EncodingCloudEventsBinary:
+		event, err := newCloudEvent(source, stream, data)
+		if err != nil {
+			return nil, err
+		}
+		return cloudevents.EncodeBinary(event)
+	default:
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"data": string(jsonData),
+		}, nil
+	}
+}
+
+// newCloudEvent maps an event envelope (id, type, schemaVersion, occurredAt, tenantId,
+// correlationId, traceparent, tracestate, payload) to a CloudEvent. Messages without an envelope are sent
+// whole as the event data, typed after the stream.
+func newCloudEvent(source, stream string, data map[string]interface{}) (*cloudevents.Event, error) {
+	str := func(name string) string {
+		value, _ := data[name].(string)
+		return value
+	}
+	event := &cloudevents.Event{
+		SpecVersion:     cloudevents.SpecVersion,
+		ID:              str("id"),
+		Source:          source,
+		Type:            str("type"),
+		DataContentType: "application/json",
+		TenantID:        str("tenantId"),
+		CorrelationID:   str("correlationId"),
+		TraceParent:     str("traceparent"),
+		TraceState:      str("tracestate"),
+	}
+
+	payload, isEnvelope := data["payload"]
+	if event.Type == "" || !isEnvelope {
+		event.ID = uuid.New().String()
+		event.Type = stream
+		now := time.Now().UTC()
+		event.Time = &now
+		payload = data
+	} else {
+		if occurredAt, err := time.Parse(time.RFC3339Nano, str("occurredAt")); err == nil {
+			event.Time = &occurredAt
+		}
+		if version, ok := data["schemaVersion"].(float64); ok {
+			event.SchemaVersion = int(version)
+		} else if version, ok := data["schemaVersion"].(int); ok {
+			event.SchemaVersion = version
+		}
+		if event.SchemaVersion > 0 {
+			event.DataSchema = schemas.EventSchemaURL(event.Type, event.SchemaVersion)
+		}
+		if fields, ok := payload.(map[string]interface{}); ok {
+			event.Subject, _ = fields["id"].(string)
+		}
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	event.Data = raw
+	return event, nil
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/pkg/cloudevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envelope is an event envelope as the outbox relay hands it to the publisher
+var envelope = map[string]interface{}{
+	"id":            "6f0c3a4e-2b8e-4d2a-9a57-2f7c1e0b9d11",
+	"type":          "todo.created",
+	"schemaVersion": float64(1),
+	"occurredAt":    "2024-06-10T06:13:20Z",
+	"tenantId":      "acme",
+	"correlationId": "req-123",
+	"payload":       map[string]interface{}{"id": "todo-1", "description": "test"},
+}
+
+func TestParseStreamEncoding(t *testing.T) {
+	encoding, err := ParseStreamEncoding("")
+	assert.NoError(t, err)
+	assert.Equal(t, EncodingData, encoding)
+
+	encoding, err = ParseStreamEncoding("cloudevents-binary")
+	assert.NoError(t, err)
+	assert.Equal(t, EncodingCloudEventsBinary, encoding)
+
+	_, err = ParseStreamEncoding("protobuf")
+	assert.Error(t, err)
+}
+
+func TestEncodeMessage_Data(t *testing.T) {
+	values, err := encodeMessage(EncodingData, "/src", "todo-items", envelope)
+	require.NoError(t, err)
+	assert.Len(t, values, 1)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(values["data"].(string)), &decoded))
+	assert.Equal(t, envelope, decoded)
+}
+
+func TestEncodeMessage_CloudEvents(t *testing.T) {
+	for _, encoding := range []StreamEncoding{EncodingCloudEventsStructured, EncodingCloudEventsBinary} {
+		t.Run(string(encoding), func(t *testing.T) {
+			values, err := encodeMessage(encoding, "/src", "todo-items", envelope)
+			require.NoError(t, err)
+
+			event, ok, err := cloudevents.Decode(values)
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, "6f0c3a4e-2b8e-4d2a-9a57-2f7c1e0b9d11", event.ID)
+			assert.Equal(t, "/src", event.Source)
+			assert.Equal(t, "todo.created", event.Type)
+			assert.Equal(t, "todo-1", event.Subject)
+			assert.Equal(t, "2024-06-10T06:13:20Z", event.Time.Format("2006-01-02T15:04:05Z07:00"))
+			assert.Equal(t, "acme", event.TenantID)
+			assert.Equal(t, "req-123", event.CorrelationID)
+			assert.Equal(t, 1, event.SchemaVersion)
+			assert.Equal(t, "https://github.com/ar-agahian/ice-assignment/schemas/events/todo.created.v1.json", event.DataSchema)
+			assert.JSONEq(t, `{"id":"todo-1","description":"test"}`, string(event.Data))
+		})
+	}
+}
+
+func TestEncodeMessage_CloudEventsWithoutEnvelope(t *testing.T) {
+	values, err := encodeMessage(EncodingCloudEventsBinary, "/src", "todo-items", map[string]interface{}{"id": "1"})
+	require.NoError(t, err)
+
+	event, ok, err := cloudevents.Decode(values)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, "todo-items", event.Type)
+	assert.NotNil(t, event.Time)
+	assert.JSONEq(t, `{"id":"1"}`, string(event.Data))
+}
@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	idempotencyKeyPrefix  = "idempotency:"
+	defaultIdempotencyTTL = 24 * time.Hour
+	// idempotencyLockTTL bounds how long a crashed request keeps its key reserved; running
+	// requests extend it periodically
+	idempotencyLockTTL = 5 * time.Minute
+)
+
+// extendScript resets the expiry of a key only while it holds a reservation, so that a
+// late extension cannot shorten the TTL of a stored response
+var extendScript = redis.NewScript(`
+local record = redis.call('GET', KEYS[1])
+if record and string.find(record, '"completed":false', 1, true) then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// IdempotencyStore implements the IdempotencyStore interface with Redis keys that expire after a TTL
+type IdempotencyStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// idempotencyRecord is the JSON representation of a stored record
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// NewIdempotencyStore creates a new IdempotencyStore keeping responses for ttl
+func NewIdempotencyStore(ctx context.Context, cfg Config, ttl time.Duration) (*IdempotencyStore, error) {
+	rdb, err := newClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	return &IdempotencyStore{client: rdb, ttl: ttl}, nil
+}
+
+// Reserve claims a key with SET NX, returning the existing record when the key is taken
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, fingerprint string) (*client.IdempotencyRecord, error) {
+	data, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, data, idempotencyLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The key expired between SET NX and GET; try again
+		return s.Reserve(ctx, key, fingerprint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record idempotencyRecord
+	if err := json.Unmarshal(existing, &record); err != nil {
+		return nil, err
+	}
+	return &client.IdempotencyRecord{
+		Fingerprint: record.Fingerprint,
+		Completed:   record.Completed,
+		StatusCode:  record.StatusCode,
+		ContentType: record.ContentType,
+		Body:        record.Body,
+	}, nil
+}
+
+// Complete stores the response under the key for the store's TTL
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record *client.IdempotencyRecord) error {
+	data, err := json.Marshal(idempotencyRecord{
+		Fingerprint: record.Fingerprint,
+		Completed:   true,
+		StatusCode:  record.StatusCode,
+		ContentType: record.ContentType,
+		Body:        record.Body,
+	})
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, idempotencyKeyPrefix+key, data, s.ttl).Err()
+}
+
+// Extend resets the expiry of a reserved key to the lock TTL. Keys that hold a response
+// or no longer exist are left alone.
+func (s *IdempotencyStore) Extend(ctx context.Context, key string) error {
+	return extendScript.Run(ctx, s.client, []string{idempotencyKeyPrefix + key}, idempotencyLockTTL.Milliseconds()).Err()
+}
+
+// Release deletes a reserved key
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
+
+// Check sends a PING over the store's connection and implements the HealthChecker interface
+func (s *IdempotencyStore) Check(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close closes the Redis connection
+func (s *IdempotencyStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestIdempotencyStore(t *testing.T) *IdempotencyStore {
+	// Skip if Redis is not available
+	store, err := NewIdempotencyStore(context.Background(), testConfig, time.Hour)
+	if err != nil {
+		t.Skipf("Skipping test: Redis not available: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestIdempotencyStore(t)
+	key := "test-key-" + uuid.NewString()
+	t.Cleanup(func() { store.Release(ctx, key) })
+
+	record, err := store.Reserve(ctx, key, "fingerprint")
+	require.NoError(t, err)
+	assert.Nil(t, record, "a free key should be reserved")
+
+	record, err = store.Reserve(ctx, key, "fingerprint")
+	require.NoError(t, err)
+	assert.Equal(t, &client.IdempotencyRecord{Fingerprint: "fingerprint"}, record)
+	ttl, err := store.client.TTL(ctx, idempotencyKeyPrefix+key).Result()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ttl, idempotencyLockTTL)
+
+	require.NoError(t, store.client.Expire(ctx, idempotencyKeyPrefix+key, time.Minute).Err())
+	require.NoError(t, store.Extend(ctx, key))
+	ttl, err = store.client.TTL(ctx, idempotencyKeyPrefix+key).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Minute, "extending should renew the reservation")
+
+	require.NoError(t, store.Complete(ctx, key, &client.IdempotencyRecord{
+		Fingerprint: "fingerprint",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":"1"}`),
+	}))
+	record, err = store.Reserve(ctx, key, "other")
+	require.NoError(t, err)
+	assert.Equal(t, &client.IdempotencyRecord{
+		Fingerprint: "fingerprint",
+		Completed:   true,
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":"1"}`),
+	}, record)
+	ttl, err = store.client.TTL(ctx, idempotencyKeyPrefix+key).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, idempotencyLockTTL)
+	assert.LessOrEqual(t, ttl, time.Hour)
+	require.NoError(t, store.Extend(ctx, key))
+	ttl, err = store.client.TTL(ctx, idempotencyKeyPrefix+key).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, idempotencyLockTTL, "extending should not shorten a stored response")
+
+	require.NoError(t, store.Release(ctx, key))
+	record, err = store.Reserve(ctx, key, "fingerprint")
+	require.NoError(t, err)
+	assert.Nil(t, record, "a released key should be reserved again")
+}
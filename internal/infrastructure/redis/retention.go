@@ -0,0 +1,258 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultTrimInterval = time.Minute
+
+// RetentionPolicy bounds how many entries a stream keeps. Entries are only trimmed once
+// every consumer group has read and acknowledged them, so a lagging group can keep a
+// stream above its limit.
+type RetentionPolicy struct {
+	// MaxLen keeps roughly the newest MaxLen entries
+	MaxLen int64
+	// MaxAge keeps entries newer than MaxAge
+	MaxAge time.Duration
+}
+
+// ParseRetentionPolicies parses a comma-separated list of per-stream retention policies
+// such as "todo-items=maxlen:100000,audit=maxage:168h"
+func ParseRetentionPolicies(value string) (map[string]RetentionPolicy, error) {
+	policies := make(map[string]RetentionPolicy)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		stream, rule, ok := strings.Cut(entry, "=")
+		if !ok || stream == "" {
+			return nil, fmt.Errorf("invalid stream retention %q", entry)
+		}
+		kind, limit, _ := strings.Cut(rule, ":")
+		var policy RetentionPolicy
+		switch kind {
+		case "maxlen":
+			maxLen, err := strconv.ParseInt(limit, 10, 64)
+			if err != nil || maxLen <= 0 {
+				return nil, fmt.Errorf("invalid maxlen for stream %s: %q", stream, limit)
+			}
+			policy.MaxLen = maxLen
+		case "maxage":
+			maxAge, err := time.ParseDuration(limit)
+			if err != nil || maxAge <= 0 {
+				return nil, fmt.Errorf("invalid maxage for stream %s: %q", stream, limit)
+			}
+			policy.MaxAge = maxAge
+		default:
+			return nil, fmt.Errorf("unknown retention %q for stream %s, expected maxlen or maxage", kind, stream)
+		}
+		policies[stream] = policy
+	}
+	return policies, nil
+}
+
+// StreamTrimmer periodically trims streams according to their retention policies
+// without dropping entries that a consumer group has not processed yet
+type StreamTrimmer struct {
+	client   *redis.Client
+	policies map[string]RetentionPolicy
+	interval time.Duration
+}
+
+// NewStreamTrimmer creates a new StreamTrimmer sharing the publisher's Redis connection
+func NewStreamTrimmer(publisher *StreamPublisher, policies map[string]RetentionPolicy, interval time.Duration) *StreamTrimmer {
+	if interval <= 0 {
+		interval = defaultTrimInterval
+	}
+	return &StreamTrimmer{
+		client:   publisher.client,
+		policies: policies,
+		interval: interval,
+	}
+}
+
+// Run trims all streams with a retention policy every interval until ctx is cancelled
+func (t *StreamTrimmer) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		for stream := range t.policies {
+			if _, err := t.Trim(ctx, stream); err != nil && ctx.Err() == nil {
+				log.Printf("stream trimmer: %s: %v", stream, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Trim applies a stream's retention policy and returns the number of removed entries.
+// Trimming is approximate: Redis only removes whole internal nodes, so a stream may keep
+// slightly more entries than its policy allows.
+func (t *StreamTrimmer) Trim(ctx context.Context, stream string) (int64, error) {
+	policy, ok := t.policies[stream]
+	if !ok {
+		return 0, nil
+	}
+	floor, err := t.unprocessedFloor(ctx, stream)
+	if err != nil || floor == noEntries {
+		return 0, err
+	}
+
+	if policy.MaxLen > 0 {
+		if floor != "" {
+			// Trimming to MaxLen is only safe when it keeps every unprocessed entry
+			unprocessed, err := t.client.XRangeN(ctx, stream, floor, "+", policy.MaxLen+1).Result()
+			if err != nil {
+				return 0, err
+			}
+			if int64(len(unprocessed)) > policy.MaxLen {
+				return t.client.XTrimMinIDApprox(ctx, stream, floor, 0).Result()
+			}
+		}
+		return t.client.XTrimMaxLenApprox(ctx, stream, policy.MaxLen, 0).Result()
+	}
+
+	minID := fmt.Sprintf("%d-0", time.Now().Add(-policy.MaxAge).UnixMilli())
+	if floor != "" && compareStreamIDs(floor, minID) < 0 {
+		minID = floor
+	}
+	return t.client.XTrimMinIDApprox(ctx, stream, minID, 0).Result()
+}
+
+// noEntries is returned by unprocessedFloor for streams that do not exist
+const noEntries = "-"
+
+// unprocessedFloor returns the lowest entry ID that some consumer group has not yet
+// acknowledged, or an empty string when the stream has no consumer groups
+func (t *StreamTrimmer) unprocessedFloor(ctx context.Context, stream string) (string, error) {
+	groups, err := t.client.XInfoGroups(ctx, stream).Result()
+	if isNoSuchKey(err) {
+		return noEntries, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	floor := ""
+	for _, group := range groups {
+		lowest := nextStreamID(group.LastDeliveredID)
+		if group.Pending > 0 {
+			pending, err := t.client.XPending(ctx, stream, group.Name).Result()
+			if err != nil {
+				return "", err
+			}
+			if compareStreamIDs(pending.Lower, lowest) < 0 {
+				lowest = pending.Lower
+			}
+		}
+		if floor == "" || compareStreamIDs(lowest, floor) < 0 {
+			floor = lowest
+		}
+	}
+	return floor, nil
+}
+
+// StreamInspector implements the StreamInspector interface on Redis streams
+type StreamInspector struct {
+	client *redis.Client
+}
+
+// NewStreamInspector creates a new StreamInspector sharing the publisher's Redis connection
+func NewStreamInspector(publisher *StreamPublisher) *StreamInspector {
+	return &StreamInspector{client: publisher.client}
+}
+
+// Info returns the length of a stream and the progress of its consumer groups.
+// Streams that do not exist are reported as empty.
+func (i *StreamInspector) Info(ctx context.Context, stream string) (*client.StreamInfo, error) {
+	info := &client.StreamInfo{Stream: stream, Groups: []client.ConsumerGroupInfo{}}
+	streamInfo, err := i.client.XInfoStream(ctx, stream).Result()
+	if isNoSuchKey(err) {
+		return info, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	info.Length = streamInfo.Length
+	info.FirstID = streamInfo.FirstEntry.ID
+	info.LastID = streamInfo.LastEntry.ID
+
+	groups, err := i.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		info.Groups = append(info.Groups, client.ConsumerGroupInfo{
+			Name:            group.Name,
+			Consumers:       group.Consumers,
+			LastDeliveredID: group.LastDeliveredID,
+			Pending:         group.Pending,
+			Lag:             group.Lag,
+		})
+	}
+	return info, nil
+}
+
+// RegisterStreamMetrics exposes the length and consumer group lag of streams, read
+// through inspector on every scrape
+func RegisterStreamMetrics(inspector *StreamInspector, streams []string) error {
+	return metrics.RegisterStreamStats(streams, func(ctx context.Context, stream string) (*metrics.StreamStats, error) {
+		info, err := inspector.Info(ctx, stream)
+		if err != nil {
+			return nil, err
+		}
+		stats := &metrics.StreamStats{Length: info.Length, GroupLag: make(map[string]int64, len(info.Groups))}
+		for _, group := range info.Groups {
+			if group.Lag >= 0 {
+				stats.GroupLag[group.Name] = group.Lag
+			}
+		}
+		return stats, nil
+	})
+}
+
+// isNoSuchKey reports whether err is Redis' error for a missing stream
+func isNoSuchKey(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no such key")
+}
+
+// parseStreamID splits a stream entry ID into its millisecond and sequence parts
+func parseStreamID(id string) (uint64, uint64) {
+	ms, seq, _ := strings.Cut(id, "-")
+	msPart, _ := strconv.ParseUint(ms, 10, 64)
+	seqPart, _ := strconv.ParseUint(seq, 10, 64)
+	return msPart, seqPart
+}
+
+// compareStreamIDs returns -1, 0 or 1 when a is lower than, equal to or greater than b
+func compareStreamIDs(a, b string) int {
+	aMs, aSeq := parseStreamID(a)
+	bMs, bSeq := parseStreamID(b)
+	switch {
+	case aMs < bMs || (aMs == bMs && aSeq < bSeq):
+		return -1
+	case aMs == bMs && aSeq == bSeq:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// nextStreamID returns the smallest entry ID greater than id
+func nextStreamID(id string) string {
+	ms, seq := parseStreamID(id)
+	return fmt.Sprintf("%d-%d", ms, seq+1)
+}
@@ -0,0 +1,235 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestStream(t *testing.T, entries int) (*StreamPublisher, string, []string) {
+	ctx := context.Background()
+
+	// Skip if Redis is not available
+	publisher, err := NewStreamPublisher(ctx, testConfig)
+	if err != nil {
+		t.Skipf("Skipping test: Redis not available: %v", err)
+	}
+	stream := "test-stream-" + uuid.NewString()
+	t.Cleanup(func() {
+		publisher.client.Del(ctx, stream)
+		publisher.Close()
+	})
+
+	ids := make([]string, 0, entries)
+	for i := 0; i < entries; i++ {
+		id, err := publisher.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			Values: map[string]interface{}{"n": i},
+		}).Result()
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	return publisher, stream, ids
+}
+
+// consume reads n entries with a consumer group and acknowledges the first acked of them
+func consume(t *testing.T, publisher *StreamPublisher, stream, group string, n, acked int) {
+	ctx := context.Background()
+	require.NoError(t, publisher.client.XGroupCreate(ctx, stream, group, "0").Err())
+	if n == 0 {
+		return
+	}
+	streams, err := publisher.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: "test-consumer",
+		Streams:  []string{stream, ">"},
+		Count:    int64(n),
+	}).Result()
+	require.NoError(t, err)
+	for _, message := range streams[0].Messages[:acked] {
+		require.NoError(t, publisher.client.XAck(ctx, stream, group, message.ID).Err())
+	}
+}
+
+func TestStreamTrimmer_Trim(t *testing.T) {
+	const entries = 300
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		setup  func(t *testing.T, publisher *StreamPublisher, stream string)
+		// kept is the index of the oldest entry that must survive trimming
+		kept       int
+		expectTrim bool
+	}{
+		{
+			name:       "maxlen without consumer groups",
+			policy:     RetentionPolicy{MaxLen: 10},
+			setup:      func(t *testing.T, publisher *StreamPublisher, stream string) {},
+			kept:       entries - 10,
+			expectTrim: true,
+		},
+		{
+			name:   "maxlen keeps undelivered entries",
+			policy: RetentionPolicy{MaxLen: 10},
+			setup: func(t *testing.T, publisher *StreamPublisher, stream string) {
+				consume(t, publisher, stream, "caught-up", entries, entries)
+				consume(t, publisher, stream, "lagging", 150, 150)
+			},
+			kept:       150,
+			expectTrim: true,
+		},
+		{
+			name:   "maxlen keeps pending entries",
+			policy: RetentionPolicy{MaxLen: 10},
+			setup: func(t *testing.T, publisher *StreamPublisher, stream string) {
+				consume(t, publisher, stream, "stuck", 250, 0)
+			},
+			kept: 0,
+		},
+		{
+			name:   "maxage keeps undelivered entries",
+			policy: RetentionPolicy{MaxAge: time.Nanosecond},
+			setup: func(t *testing.T, publisher *StreamPublisher, stream string) {
+				consume(t, publisher, stream, "lagging", 200, 200)
+			},
+			kept:       200,
+			expectTrim: true,
+		},
+		{
+			name:   "new consumer group keeps everything",
+			policy: RetentionPolicy{MaxAge: time.Nanosecond},
+			setup: func(t *testing.T, publisher *StreamPublisher, stream string) {
+				consume(t, publisher, stream, "new", 0, 0)
+			},
+			kept: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			publisher, stream, ids := setupTestStream(t, entries)
+			tt.setup(t, publisher, stream)
+
+			trimmer := NewStreamTrimmer(publisher, map[string]RetentionPolicy{stream: tt.policy}, 0)
+			trimmed, err := trimmer.Trim(ctx, stream)
+			require.NoError(t, err)
+
+			if tt.expectTrim {
+				assert.Positive(t, trimmed)
+			} else {
+				assert.Zero(t, trimmed)
+			}
+			assert.LessOrEqual(t, trimmed, int64(tt.kept), "trimmed entries that must be kept")
+			kept, err := publisher.client.XRange(ctx, stream, ids[tt.kept], "+").Result()
+			require.NoError(t, err)
+			assert.Len(t, kept, entries-tt.kept)
+		})
+	}
+}
+
+func TestStreamTrimmer_TrimWithoutPolicy(t *testing.T) {
+	publisher, stream, _ := setupTestStream(t, 1)
+	trimmer := NewStreamTrimmer(publisher, map[string]RetentionPolicy{}, 0)
+	trimmed, err := trimmer.Trim(context.Background(), stream)
+	assert.NoError(t, err)
+	assert.Zero(t, trimmed)
+}
+
+func TestStreamTrimmer_TrimMissingStream(t *testing.T) {
+	publisher, stream, _ := setupTestStream(t, 0)
+	trimmer := NewStreamTrimmer(publisher, map[string]RetentionPolicy{stream: {MaxLen: 10}}, 0)
+	trimmed, err := trimmer.Trim(context.Background(), stream)
+	assert.NoError(t, err)
+	assert.Zero(t, trimmed)
+}
+
+func TestStreamInspector_Info(t *testing.T) {
+	ctx := context.Background()
+	publisher, stream, ids := setupTestStream(t, 5)
+	consume(t, publisher, stream, "test-group", 3, 1)
+
+	info, err := NewStreamInspector(publisher).Info(ctx, stream)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), info.Length)
+	assert.Equal(t, ids[0], info.FirstID)
+	assert.Equal(t, ids[4], info.LastID)
+	require.Len(t, info.Groups, 1)
+	assert.Equal(t, "test-group", info.Groups[0].Name)
+	assert.Equal(t, ids[2], info.Groups[0].LastDeliveredID)
+	assert.Equal(t, int64(2), info.Groups[0].Pending)
+
+	missing, err := NewStreamInspector(publisher).Info(ctx, stream+"-missing")
+	require.NoError(t, err)
+	assert.Zero(t, missing.Length)
+	assert.Empty(t, missing.Groups)
+}
+
+func TestParseRetentionPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      map[string]RetentionPolicy
+		expectedError bool
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: map[string]RetentionPolicy{},
+		},
+		{
+			name:  "multiple streams",
+			value: "todo-items=maxlen:100000, audit=maxage:168h",
+			expected: map[string]RetentionPolicy{
+				"todo-items": {MaxLen: 100000},
+				"audit":      {MaxAge: 168 * time.Hour},
+			},
+		},
+		{
+			name:          "missing stream",
+			value:         "=maxlen:10",
+			expectedError: true,
+		},
+		{
+			name:          "invalid maxlen",
+			value:         "todo-items=maxlen:0",
+			expectedError: true,
+		},
+		{
+			name:          "invalid maxage",
+			value:         "todo-items=maxage:week",
+			expectedError: true,
+		},
+		{
+			name:          "unknown kind",
+			value:         "todo-items=maxsize:10",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := ParseRetentionPolicies(tt.value)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, policies)
+		})
+	}
+}
+
+func TestCompareStreamIDs(t *testing.T) {
+	assert.Equal(t, -1, compareStreamIDs("1-0", "2-0"))
+	assert.Equal(t, -1, compareStreamIDs("9-0", "10-0"))
+	assert.Equal(t, -1, compareStreamIDs("1-9", "1-10"))
+	assert.Equal(t, 0, compareStreamIDs("5-1", "5-1"))
+	assert.Equal(t, 1, compareStreamIDs("2-0", "1-5"))
+	assert.Equal(t, "7-4", nextStreamID("7-3"))
+}
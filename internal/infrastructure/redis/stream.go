@@ -2,48 +2,68 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
-	"os"
+	"time"
 
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer starts the spans of Redis commands
+var tracer = otel.Tracer("github.com/ar-agahian/ice-assignment/internal/infrastructure/redis")
+
 // StreamPublisher implements the StreamPublisher interface using Redis Streams
 type StreamPublisher struct {
-	client *redis.Client
+	client   *redis.Client
+	encoding StreamEncoding
+	source   string
 }
 
-// NewStreamPublisher creates a new Redis StreamPublisher
-func NewStreamPublisher(ctx context.Context) (*StreamPublisher, error) {
-	addr := os.Getenv("REDIS_ADDR")
-	password := os.Getenv("REDIS_PASSWORD")
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       0,
-	})
-	if err := rdb.Ping(ctx).Err(); err != nil {
+// NewStreamPublisher creates a new Redis StreamPublisher using the configured message
+// encoding and CloudEvents source
+func NewStreamPublisher(ctx context.Context, cfg Config) (*StreamPublisher, error) {
+	encoding, err := ParseStreamEncoding(string(cfg.StreamEncoding))
+	if err != nil {
+		return nil, err
+	}
+	source := cfg.CloudEventsSource
+	if source == "" {
+		source = defaultCloudEventsSource
+	}
+	rdb, err := newClient(ctx, cfg)
+	if err != nil {
 		return nil, err
 	}
-	return &StreamPublisher{client: rdb}, nil
+	return &StreamPublisher{client: rdb, encoding: encoding, source: source}, nil
 }
 
-// Publish publishes a message to a Redis stream
-func (p *StreamPublisher) Publish(ctx context.Context, stream string, data map[string]interface{}) error {
-	jsonData, err := json.Marshal(data)
+// Publish publishes a message to a Redis stream, recording the XADD latency and span
+func (p *StreamPublisher) Publish(ctx context.Context, stream string, data map[string]interface{}) (err error) {
+	values, err := encodeMessage(p.encoding, p.source, stream, data)
 	if err != nil {
 		return err
 	}
 	args := redis.XAddArgs{
 		Stream: stream,
-		Values: map[string]interface{}{
-			"data": string(jsonData),
-		},
-	}
-	if err := p.client.XAdd(ctx, &args).Err(); err != nil {
-		return err
+		Values: values,
 	}
-	return nil
+	ctx, span := tracer.Start(ctx, "XADD "+stream,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNameRedis, semconv.DBOperationName("XADD"), semconv.DBCollectionName(stream)),
+	)
+	defer func() { tracing.End(span, err) }()
+	start := time.Now()
+	err = p.client.XAdd(ctx, &args).Err()
+	metrics.ObserveRedisXAdd(stream, time.Since(start), err)
+	return err
+}
+
+// Check sends a PING over the publisher's connection and implements the HealthChecker interface
+func (p *StreamPublisher) Check(ctx context.Context) error {
+	return p.client.Ping(ctx).Err()
 }
 
 // Close closes the Redis connection
@@ -2,24 +2,13 @@ package redis
 
 import (
 	"context"
-	"os"
 	"testing"
 )
 
 func BenchmarkStreamPublisher_Publish(b *testing.B) {
 	ctx := context.Background()
 
-	// Set environment variables
-	os.Setenv("REDIS_ADDR", "localhost:6379")
-	os.Setenv("REDIS_PASSWORD", "")
-	os.Setenv("REDIS_DB", "0")
-	defer func() {
-		os.Unsetenv("REDIS_ADDR")
-		os.Unsetenv("REDIS_PASSWORD")
-		os.Unsetenv("REDIS_DB")
-	}()
-
-	publisher, err := NewStreamPublisher(ctx)
+	publisher, err := NewStreamPublisher(ctx, testConfig)
 	if err != nil {
 		b.Skipf("Skipping benchmark: Redis not available: %v", err)
 	}
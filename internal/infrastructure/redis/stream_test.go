@@ -2,27 +2,23 @@ package redis
 
 import (
 	"context"
-	"os"
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/ar-agahian/ice-assignment/internal/infrastructure/streamtest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// testConfig connects to the Redis instance started by docker-compose
+var testConfig = Config{Addr: "localhost:6379"}
+
 func TestStreamPublisher_Publish(t *testing.T) {
 	ctx := context.Background()
 
-	// Set environment variables
-	os.Setenv("REDIS_ADDR", "localhost:6379")
-	os.Setenv("REDIS_PASSWORD", "")
-	os.Setenv("REDIS_DB", "0")
-	defer func() {
-		os.Unsetenv("REDIS_ADDR")
-		os.Unsetenv("REDIS_PASSWORD")
-		os.Unsetenv("REDIS_DB")
-	}()
-
 	// Skip if Redis is not available
-	publisher, err := NewStreamPublisher(ctx)
+	publisher, err := NewStreamPublisher(ctx, testConfig)
 	if err != nil {
 		t.Skipf("Skipping test: Redis not available: %v", err)
 	}
@@ -39,3 +35,51 @@ func TestStreamPublisher_Publish(t *testing.T) {
 	}
 }
 
+func TestStreamPublisher_Check(t *testing.T) {
+	// Skip if Redis is not available
+	publisher, err := NewStreamPublisher(context.Background(), testConfig)
+	if err != nil {
+		t.Skipf("Skipping test: Redis not available: %v", err)
+	}
+	defer publisher.Close()
+
+	assert.NoError(t, publisher.Check(context.Background()))
+}
+
+func TestStreamPublisher_Conformance(t *testing.T) {
+	streamtest.RunPublisherConformance(t, func(t *testing.T) streamtest.Backend {
+		// Skip if Redis is not available
+		publisher, err := NewStreamPublisher(context.Background(), testConfig)
+		if err != nil {
+			t.Skipf("Skipping test: Redis not available: %v", err)
+		}
+		var streams []string
+		t.Cleanup(func() {
+			publisher.client.Del(context.Background(), streams...)
+			publisher.Close()
+		})
+		return streamtest.Backend{
+			Publisher: publisher,
+			Read: func(t *testing.T, stream string, n int) []map[string]interface{} {
+				streams = append(streams, stream)
+				var entries []map[string]interface{}
+				require.Eventually(t, func() bool {
+					messages, err := publisher.client.XRange(context.Background(), stream, "-", "+").Result()
+					if err != nil || len(messages) < n {
+						return false
+					}
+					entries = entries[:0]
+					for _, message := range messages[:n] {
+						var data map[string]interface{}
+						if err := json.Unmarshal([]byte(message.Values["data"].(string)), &data); err != nil {
+							return false
+						}
+						entries = append(entries, data)
+					}
+					return true
+				}, streamtest.ReadTimeout, 10*time.Millisecond)
+				return entries
+			},
+		}
+	})
+}
@@ -2,40 +2,79 @@ package s3
 
 import (
 	"context"
+	"errors"
 	"io"
-	"os"
+	"mime"
+	"net/http"
+	"time"
 
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// FileStorage implements the FileStorage interface using AWS S3
+// S3 operations recorded in metrics and spans
+const (
+	operationPutObject    = "PutObject"
+	operationGetObject    = "GetObject"
+	operationDeleteObject = "DeleteObject"
+)
+
+// tracer starts the spans of S3 requests
+var tracer = otel.Tracer("github.com/ar-agahian/ice-assignment/internal/infrastructure/s3")
+
+// FileStorage implements the FileStorage interface using AWS S3. Objects are stored
+// under a prefix per tenant, so a file ID only resolves within the caller's tenant.
 type FileStorage struct {
 	client     *s3.Client
+	uploader   *manager.Uploader
 	bucketName string
 }
 
+// Config holds the S3 bucket settings. Credentials and region come from the AWS SDK's
+// default chain, e.g. AWS_ACCESS_KEY_ID and AWS_REGION.
+type Config struct {
+	Bucket string `yaml:"bucket" env:"S3_BUCKET_NAME"`
+	// Endpoint overrides the S3 endpoint and switches to path-style addressing, as
+	// needed by LocalStack
+	Endpoint string `yaml:"endpoint" env:"S3_ENDPOINT"`
+}
+
 // NewFileStorage creates a new S3 FileStorage
-func NewFileStorage(ctx context.Context) (*FileStorage, error) {
-	bucketName := os.Getenv("S3_BUCKET_NAME")
-	endpoint := os.Getenv("S3_ENDPOINT")
-	cfg, err := config.LoadDefaultConfig(ctx)
+func NewFileStorage(ctx context.Context, cfg Config) (*FileStorage, error) {
+	awsCfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if endpoint != "" {
-		cfg.BaseEndpoint = aws.String(endpoint)
+	if cfg.Endpoint != "" {
+		awsCfg.BaseEndpoint = aws.String(cfg.Endpoint)
 	}
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		if endpoint != "" {
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
 			o.UsePathStyle = true
 		}
 	})
+	// A single in-flight part keeps memory per upload bounded by PartSize
+	// regardless of how large the streamed body is.
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+		u.PartSize = manager.MinUploadPartSize
+		u.Concurrency = 1
+	})
 	storage := &FileStorage{
 		client:     client,
-		bucketName: bucketName,
+		uploader:   uploader,
+		bucketName: cfg.Bucket,
 	}
 	if err := storage.ensureBucket(ctx); err != nil {
 		return nil, err
@@ -43,15 +82,27 @@ func NewFileStorage(ctx context.Context) (*FileStorage, error) {
 	return storage, nil
 }
 
-// Upload uploads a file to S3 and returns the file ID
-func (s *FileStorage) Upload(ctx context.Context, file io.Reader, contentType string) (string, error) {
+// Upload streams a file of unknown length to S3 and returns the file ID.
+// The original filename is kept as the object's Content-Disposition.
+func (s *FileStorage) Upload(ctx context.Context, file io.Reader, contentType string, filename string) (_ string, err error) {
 	fileID := uuid.New().String()
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+	key := objectKey(ctx, fileID)
+	ctx, span := s.startSpan(ctx, operationPutObject, key)
+	defer func() { tracing.End(span, err) }()
+	body := &countingReader{r: file}
+	input := &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucketName),
-		Key:         aws.String(fileID),
-		Body:        file,
+		Key:         aws.String(key),
+		Body:        body,
 		ContentType: aws.String(contentType),
-	})
+	}
+	if filename != "" {
+		input.ContentDisposition = aws.String(mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	start := time.Now()
+	_, err = s.uploader.Upload(ctx, input)
+	metrics.ObserveS3Request(operationPutObject, time.Since(start), err)
+	metrics.AddS3Bytes(operationPutObject, body.n)
 	if err != nil {
 		return "", err
 	}
@@ -59,22 +110,154 @@ func (s *FileStorage) Upload(ctx context.Context, file io.Reader, contentType st
 }
 
 // Get retrieves a file from S3 by file ID
-func (s *FileStorage) Get(ctx context.Context, fileID string) ([]byte, error) {
-	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+func (s *FileStorage) Get(ctx context.Context, fileID string) (_ []byte, err error) {
+	key := objectKey(ctx, fileID)
+	ctx, span := s.startSpan(ctx, operationGetObject, key)
+	defer func() { tracing.End(span, err) }()
+	result, err := s.getObject(ctx, fileID, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(fileID),
+		Key:    aws.String(key),
 	})
 	if err != nil {
 		return nil, err
 	}
 	defer result.Body.Close()
 	data, err := io.ReadAll(result.Body)
+	metrics.AddS3Bytes(operationGetObject, int64(len(data)))
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
+// Open returns a streaming reader for a file, honouring Range and If-None-Match. The
+// recorded latency ends with the response headers; bytes are counted as the body is read.
+func (s *FileStorage) Open(ctx context.Context, fileID string, opts client.OpenFileOptions) (_ *client.FileObject, err error) {
+	key := objectKey(ctx, fileID)
+	ctx, span := s.startSpan(ctx, operationGetObject, key)
+	defer func() { tracing.End(span, err) }()
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	}
+	if opts.Range != "" {
+		input.Range = aws.String(opts.Range)
+	}
+	if opts.IfNoneMatch != "" {
+		input.IfNoneMatch = aws.String(opts.IfNoneMatch)
+	}
+	result, err := s.getObject(ctx, fileID, input)
+	if err != nil {
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) {
+			switch respErr.HTTPStatusCode() {
+			case http.StatusNotModified:
+				return &client.FileObject{
+					ETag:        respErr.Response.Header.Get("ETag"),
+					NotModified: true,
+				}, nil
+			case http.StatusNotFound:
+				return nil, apperrors.NewAppError("FILE_NOT_FOUND", "file not found", http.StatusNotFound, nil)
+			case http.StatusRequestedRangeNotSatisfiable:
+				return nil, apperrors.NewAppError("RANGE_NOT_SATISFIABLE", "requested range not satisfiable", http.StatusRequestedRangeNotSatisfiable, nil)
+			}
+		}
+		return nil, err
+	}
+	return &client.FileObject{
+		Body:               &countingReadCloser{ReadCloser: result.Body, operation: operationGetObject},
+		ContentType:        aws.ToString(result.ContentType),
+		ContentLength:      aws.ToInt64(result.ContentLength),
+		ContentRange:       aws.ToString(result.ContentRange),
+		ContentDisposition: aws.ToString(result.ContentDisposition),
+		ETag:               aws.ToString(result.ETag),
+		LastModified:       aws.ToTime(result.LastModified),
+	}, nil
+}
+
+// Delete removes a file from S3, including its legacy object for the default tenant
+func (s *FileStorage) Delete(ctx context.Context, fileID string) (err error) {
+	key := objectKey(ctx, fileID)
+	ctx, span := s.startSpan(ctx, operationDeleteObject, key)
+	defer func() { tracing.End(span, err) }()
+	keys := []string{key}
+	if legacyKey := legacyObjectKey(ctx, fileID); legacyKey != "" {
+		keys = append(keys, legacyKey)
+	}
+	for _, key := range keys {
+		if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.bucketName),
+			Key:    aws.String(key),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getObject runs GetObject and, when a file of the default tenant is missing, retries
+// with its legacy key. The recorded latency of each request ends with its headers.
+func (s *FileStorage) getObject(ctx context.Context, fileID string, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	start := time.Now()
+	result, err := s.client.GetObject(ctx, input)
+	metrics.ObserveS3Request(operationGetObject, time.Since(start), err)
+	legacyKey := legacyObjectKey(ctx, fileID)
+	if legacyKey == "" || !isNotFound(err) {
+		return result, err
+	}
+
+	legacyInput := *input
+	legacyInput.Key = aws.String(legacyKey)
+	start = time.Now()
+	result, err = s.client.GetObject(ctx, &legacyInput)
+	metrics.ObserveS3Request(operationGetObject, time.Since(start), err)
+	return result, err
+}
+
+// startSpan starts the client span of an S3 request for the object key
+func (s *FileStorage) startSpan(ctx context.Context, operation, key string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "S3."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("aws-api"),
+			semconv.RPCService("S3"),
+			semconv.RPCMethod(operation),
+			semconv.AWSS3Bucket(s.bucketName),
+			semconv.AWSS3Key(key),
+		),
+	)
+}
+
+// objectKey returns the key of a file within the prefix of the caller's tenant
+func objectKey(ctx context.Context, fileID string) string {
+	return domain.TenantFromContext(ctx) + "/" + fileID
+}
+
+// legacyObjectKey returns the unprefixed key that files uploaded before tenant prefixes
+// were stored under, or an empty string when the caller is not of the default tenant,
+// which those files belong to
+func legacyObjectKey(ctx context.Context, fileID string) string {
+	if domain.TenantFromContext(ctx) != domain.DefaultTenantID {
+		return ""
+	}
+	return fileID
+}
+
+// isNotFound reports whether err is an S3 response with status 404
+func isNotFound(err error) bool {
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
+
+// Check verifies that the bucket is reachable with HeadBucket and implements the
+// HealthChecker interface
+func (s *FileStorage) Check(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucketName),
+	})
+	return err
+}
+
 // ensureBucket creates the bucket if it doesn't exist
 func (s *FileStorage) ensureBucket(ctx context.Context) error {
 	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
@@ -90,3 +273,27 @@ func (s *FileStorage) ensureBucket(ctx context.Context) error {
 	})
 	return err
 }
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// countingReadCloser records the bytes read through it in metrics
+type countingReadCloser struct {
+	io.ReadCloser
+	operation string
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	metrics.AddS3Bytes(r.operation, int64(n))
+	return n, err
+}
@@ -2,34 +2,36 @@ package s3
 
 import (
 	"context"
-	"os"
+	"io"
 	"strings"
 	"testing"
 
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// testConfig points at the LocalStack instance started by docker-compose
+var testConfig = Config{Bucket: "test-bucket", Endpoint: "http://localhost:4566"}
+
 func TestFileStorage_Upload(t *testing.T) {
 	// This is a simplified test
 	// In a real scenario, you would use LocalStack or a proper S3 mock
 	ctx := context.Background()
 
-	// Set environment variables
-	os.Setenv("S3_BUCKET_NAME", "test-bucket")
-	os.Setenv("S3_ENDPOINT", "http://localhost:4566")
-	defer func() {
-		os.Unsetenv("S3_BUCKET_NAME")
-		os.Unsetenv("S3_ENDPOINT")
-	}()
-
 	// Skip if LocalStack is not available
-	storage, err := NewFileStorage(ctx)
+	storage, err := NewFileStorage(ctx, testConfig)
 	if err != nil {
 		t.Skipf("Skipping test: LocalStack not available: %v", err)
 	}
 
 	fileContent := strings.NewReader("test file content")
-	fileID, err := storage.Upload(ctx, fileContent, "text/plain")
+	fileID, err := storage.Upload(ctx, fileContent, "text/plain", "test.txt")
 
 	// If LocalStack is running, this should succeed
 	if err == nil {
@@ -40,22 +42,14 @@ func TestFileStorage_Upload(t *testing.T) {
 func TestFileStorage_Get(t *testing.T) {
 	ctx := context.Background()
 
-	// Set environment variables
-	os.Setenv("S3_BUCKET_NAME", "test-bucket")
-	os.Setenv("S3_ENDPOINT", "http://localhost:4566")
-	defer func() {
-		os.Unsetenv("S3_BUCKET_NAME")
-		os.Unsetenv("S3_ENDPOINT")
-	}()
-
-	storage, err := NewFileStorage(ctx)
+	storage, err := NewFileStorage(ctx, testConfig)
 	if err != nil {
 		t.Skipf("Skipping test: LocalStack not available: %v", err)
 	}
 
 	// First upload a file
 	fileContent := strings.NewReader("test file content")
-	fileID, err := storage.Upload(ctx, fileContent, "text/plain")
+	fileID, err := storage.Upload(ctx, fileContent, "text/plain", "test.txt")
 	if err != nil {
 		t.Skipf("Skipping test: Failed to upload file: %v", err)
 	}
@@ -66,3 +60,103 @@ func TestFileStorage_Get(t *testing.T) {
 		assert.Equal(t, "test file content", string(data))
 	}
 }
+
+func TestFileStorage_Open(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := NewFileStorage(ctx, testConfig)
+	if err != nil {
+		t.Skipf("Skipping test: LocalStack not available: %v", err)
+	}
+
+	fileID, err := storage.Upload(ctx, strings.NewReader("test file content"), "text/plain", "test.txt")
+	if err != nil {
+		t.Skipf("Skipping test: Failed to upload file: %v", err)
+	}
+
+	file, err := storage.Open(ctx, fileID, client.OpenFileOptions{Range: "bytes=0-3"})
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer file.Body.Close()
+	data, err := io.ReadAll(file.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", string(data))
+	assert.Equal(t, "bytes 0-3/17", file.ContentRange)
+	assert.Contains(t, file.ContentDisposition, "test.txt")
+
+	notModified, err := storage.Open(ctx, fileID, client.OpenFileOptions{IfNoneMatch: file.ETag})
+	assert.NoError(t, err)
+	assert.True(t, notModified.NotModified)
+}
+
+func TestFileStorage_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := NewFileStorage(ctx, testConfig)
+	if err != nil {
+		t.Skipf("Skipping test: LocalStack not available: %v", err)
+	}
+
+	acme := domain.WithPrincipal(ctx, &domain.Principal{ID: "user-1", TenantID: "acme"})
+	globex := domain.WithPrincipal(ctx, &domain.Principal{ID: "user-2", TenantID: "globex"})
+
+	fileID, err := storage.Upload(acme, strings.NewReader("test file content"), "text/plain", "test.txt")
+	if err != nil {
+		t.Skipf("Skipping test: Failed to upload file: %v", err)
+	}
+
+	data, err := storage.Get(acme, fileID)
+	assert.NoError(t, err)
+	assert.Equal(t, "test file content", string(data))
+
+	_, err = storage.Open(globex, fileID, client.OpenFileOptions{})
+	appErr, ok := apperrors.AsAppError(err)
+	assert.True(t, ok, "expected AppError")
+	if ok {
+		assert.Equal(t, "FILE_NOT_FOUND", appErr.Code)
+	}
+}
+
+func TestFileStorage_LegacyKeys(t *testing.T) {
+	ctx := context.Background()
+
+	storage, err := NewFileStorage(ctx, testConfig)
+	if err != nil {
+		t.Skipf("Skipping test: LocalStack not available: %v", err)
+	}
+
+	// A file uploaded before object keys were prefixed with the tenant
+	fileID := uuid.New().String()
+	_, err = storage.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(storage.bucketName),
+		Key:    aws.String(fileID),
+		Body:   strings.NewReader("legacy content"),
+	})
+	require.NoError(t, err)
+
+	data, err := storage.Get(ctx, fileID)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy content", string(data))
+
+	acme := domain.WithPrincipal(ctx, &domain.Principal{ID: "user-1", TenantID: "acme"})
+	_, err = storage.Open(acme, fileID, client.OpenFileOptions{})
+	appErr, ok := apperrors.AsAppError(err)
+	require.True(t, ok, "expected AppError")
+	assert.Equal(t, "FILE_NOT_FOUND", appErr.Code)
+
+	require.NoError(t, storage.Delete(ctx, fileID))
+	_, err = storage.Open(ctx, fileID, client.OpenFileOptions{})
+	appErr, ok = apperrors.AsAppError(err)
+	require.True(t, ok, "expected AppError")
+	assert.Equal(t, "FILE_NOT_FOUND", appErr.Code)
+}
+
+func TestObjectKey(t *testing.T) {
+	assert.Equal(t, "default/file-1", objectKey(context.Background(), "file-1"))
+	assert.Equal(t, "file-1", legacyObjectKey(context.Background(), "file-1"))
+
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{ID: "user-1", TenantID: "acme"})
+	assert.Equal(t, "acme/file-1", objectKey(ctx, "file-1"))
+	assert.Empty(t, legacyObjectKey(ctx, "file-1"))
+}
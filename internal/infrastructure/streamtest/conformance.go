@@ -0,0 +1,137 @@
+package streamtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Backend is a publisher under test together with a way to read back what it published
+type Backend struct {
+	Publisher client.IStreamPublisher
+	// Read returns the first n messages published to stream, in publish order,
+	// decoded back into the published data. It fails the test if they do not arrive in time.
+	Read func(t *testing.T, stream string, n int) []map[string]interface{}
+}
+
+// RunPublisherConformance runs the behaviour every IStreamPublisher implementation must
+// provide. Ordering is only required between events of the same entity, since
+// partitioned brokers order messages per key. newBackend is called once per subtest and must return a backend whose
+// streams are empty; it should skip the test when the broker is unavailable.
+func RunPublisherConformance(t *testing.T, newBackend func(t *testing.T) Backend) {
+	t.Run("round-trips an event envelope", func(t *testing.T) {
+		backend := newBackend(t)
+		stream := newStreamName()
+		event := map[string]interface{}{
+			"id":            uuid.NewString(),
+			"type":          "todo.created",
+			"schemaVersion": float64(1),
+			"occurredAt":    "2024-06-10T06:13:20Z",
+			"correlationId": "req-123",
+			"payload": map[string]interface{}{
+				"id":          uuid.NewString(),
+				"description": "conformance",
+				"fileId":      "",
+			},
+		}
+
+		require.NoError(t, backend.Publisher.Publish(context.Background(), stream, event))
+
+		messages := backend.Read(t, stream, 1)
+		require.Len(t, messages, 1)
+		assert.Equal(t, event, messages[0])
+	})
+
+	t.Run("preserves publish order of one entity's events", func(t *testing.T) {
+		backend := newBackend(t)
+		stream := newStreamName()
+		const count = 20
+		for i := 0; i < count; i++ {
+			require.NoError(t, backend.Publisher.Publish(context.Background(), stream, map[string]interface{}{
+				"id":      fmt.Sprintf("message-%02d", i),
+				"payload": map[string]interface{}{"id": "todo-1"},
+			}))
+		}
+
+		messages := backend.Read(t, stream, count)
+		require.Len(t, messages, count)
+		for i, message := range messages {
+			assert.Equal(t, fmt.Sprintf("message-%02d", i), message["id"])
+		}
+	})
+
+	t.Run("keeps streams separate", func(t *testing.T) {
+		backend := newBackend(t)
+		first, second := newStreamName(), newStreamName()
+		require.NoError(t, backend.Publisher.Publish(context.Background(), first, map[string]interface{}{"id": "first"}))
+		require.NoError(t, backend.Publisher.Publish(context.Background(), second, map[string]interface{}{"id": "second"}))
+
+		assert.Equal(t, "first", backend.Read(t, first, 1)[0]["id"])
+		assert.Equal(t, "second", backend.Read(t, second, 1)[0]["id"])
+	})
+
+	t.Run("supports concurrent publishers", func(t *testing.T) {
+		backend := newBackend(t)
+		stream := newStreamName()
+		const count = 50
+		var wg sync.WaitGroup
+		errs := make(chan error, count)
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs <- backend.Publisher.Publish(context.Background(), stream, map[string]interface{}{
+					"id": fmt.Sprintf("message-%02d", i),
+				})
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		messages := backend.Read(t, stream, count)
+		ids := make([]string, 0, len(messages))
+		for _, message := range messages {
+			ids = append(ids, message["id"].(string))
+		}
+		sort.Strings(ids)
+		for i, id := range ids {
+			assert.Equal(t, fmt.Sprintf("message-%02d", i), id)
+		}
+	})
+
+	t.Run("fails on a cancelled context", func(t *testing.T) {
+		backend := newBackend(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := backend.Publisher.Publish(ctx, newStreamName(), map[string]interface{}{"id": "cancelled"})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects data that cannot be encoded", func(t *testing.T) {
+		backend := newBackend(t)
+		err := backend.Publisher.Publish(context.Background(), newStreamName(), map[string]interface{}{
+			"id": make(chan int),
+		})
+		assert.Error(t, err)
+	})
+}
+
+// ReadTimeout bounds how long Read implementations wait for published messages
+const ReadTimeout = 10 * time.Second
+
+// newStreamName returns a unique stream name for a subtest
+func newStreamName() string {
+	return "conformance-" + uuid.NewString()[:8]
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// StreamMessage is a single message read from a stream
+type StreamMessage struct {
+	ID     string
+	Stream string
+	Values map[string]interface{}
+	// Attempt is the delivery attempt of this message, starting at 1
+	Attempt int
+}
+
+// MessageHandler processes a stream message. Returning an error leaves the
+// message pending so that it is redelivered.
+type MessageHandler func(ctx context.Context, msg *StreamMessage) error
+
+// RetryPolicy controls how failed messages are redelivered. Zero values fall back to defaults.
+type RetryPolicy struct {
+	// MaxDeliveries is the number of delivery attempts before a message is dead-lettered
+	MaxDeliveries int
+	// InitialBackoff is the delay before the first redelivery; it doubles on every attempt
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between redeliveries
+	MaxBackoff time.Duration
+}
+
+// IStreamConsumer defines the interface for consuming messages from streams
+type IStreamConsumer interface {
+	Handle(stream string, handler MessageHandler, policy RetryPolicy)
+	Run(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// DeadLetter is a message that exhausted its delivery attempts
+type DeadLetter struct {
+	// ID is the entry ID in the dead-letter stream
+	ID string
+	// Stream and MessageID identify the original message
+	Stream    string
+	MessageID string
+	Group     string
+	Values    map[string]interface{}
+	Error     string
+	Attempts  int
+	FailedAt  time.Time
+}
+
+// IDeadLetterStore defines the interface for inspecting and replaying dead-lettered messages
+type IDeadLetterStore interface {
+	// List returns up to limit entries of a stream's dead-letter queue after the given entry ID
+	List(ctx context.Context, stream string, after string, limit int64) ([]*DeadLetter, error)
+	// Replay republishes an entry to its original stream and removes it from the dead-letter queue
+	Replay(ctx context.Context, stream string, id string) (string, error)
+	// Purge removes the given entries, or the whole dead-letter queue when ids is empty
+	Purge(ctx context.Context, stream string, ids []string) (int64, error)
+}
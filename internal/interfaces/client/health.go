@@ -0,0 +1,11 @@
+package client
+
+import (
+	"context"
+)
+
+// IHealthChecker defines the interface for probing whether a dependency is reachable
+type IHealthChecker interface {
+	// Check returns an error when the dependency cannot serve requests
+	Check(ctx context.Context) error
+}
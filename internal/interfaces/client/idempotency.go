@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+)
+
+// IdempotencyRecord is the state stored under an idempotency key
+type IdempotencyRecord struct {
+	// Fingerprint identifies the request that first used the key
+	Fingerprint string
+	// Completed is false while the first request is still being processed
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// IIdempotencyStore defines the interface for storing responses by idempotency key
+type IIdempotencyStore interface {
+	// Reserve claims a key for a request with the given fingerprint. It returns nil when
+	// the key was free, or the record already stored under the key.
+	Reserve(ctx context.Context, key string, fingerprint string) (*IdempotencyRecord, error)
+	// Complete stores the response of the request that reserved the key
+	Complete(ctx context.Context, key string, record *IdempotencyRecord) error
+	// Extend renews the reservation of a key whose request is still being processed
+	Extend(ctx context.Context, key string) error
+	// Release frees a reserved key so that the request can be retried
+	Release(ctx context.Context, key string) error
+}
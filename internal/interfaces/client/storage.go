@@ -3,10 +3,32 @@ package client
 import (
 	"context"
 	"io"
+	"time"
 )
 
 // IFileStorage defines the interface for file storage operations
 type IFileStorage interface {
-	Upload(ctx context.Context, file io.Reader, contentType string) (fileID string, err error)
+	Upload(ctx context.Context, file io.Reader, contentType string, filename string) (fileID string, err error)
 	Get(ctx context.Context, fileID string) ([]byte, error)
+	Open(ctx context.Context, fileID string, opts OpenFileOptions) (*FileObject, error)
+	Delete(ctx context.Context, fileID string) error
+}
+
+// OpenFileOptions holds the conditional and partial request options for IFileStorage.Open
+type OpenFileOptions struct {
+	Range       string
+	IfNoneMatch string
+}
+
+// FileObject is a stored file whose body is streamed to the caller.
+// Body is nil when NotModified is set and must otherwise be closed by the caller.
+type FileObject struct {
+	Body               io.ReadCloser
+	ContentType        string
+	ContentLength      int64
+	ContentRange       string
+	ContentDisposition string
+	ETag               string
+	LastModified       time.Time
+	NotModified        bool
 }
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+)
+
+// StreamInfo describes the size of a stream and how far its consumer groups have read
+type StreamInfo struct {
+	Stream  string
+	Length  int64
+	FirstID string
+	LastID  string
+	Groups  []ConsumerGroupInfo
+}
+
+// ConsumerGroupInfo describes the progress of a consumer group on a stream
+type ConsumerGroupInfo struct {
+	Name            string
+	Consumers       int64
+	LastDeliveredID string
+	// Pending is the number of delivered but unacknowledged messages
+	Pending int64
+	// Lag is the number of messages not yet delivered to the group, or -1 when unknown
+	Lag int64
+}
+
+// IStreamInspector defines the interface for inspecting stream sizes and consumer progress
+type IStreamInspector interface {
+	Info(ctx context.Context, stream string) (*StreamInfo, error)
+}
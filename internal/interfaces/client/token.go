@@ -0,0 +1,13 @@
+package client
+
+import (
+	"context"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+)
+
+// ITokenVerifier defines the interface for verifying bearer tokens
+type ITokenVerifier interface {
+	// Verify checks a token's signature and claims and returns the principal it identifies
+	Verify(ctx context.Context, token string) (*domain.Principal, error)
+}
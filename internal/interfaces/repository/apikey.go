@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+)
+
+// IAPIKeyRepository defines the interface for API key persistence
+type IAPIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	// GetByHash returns the key stored under the given hash, including revoked keys
+	GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	// List returns all keys, newest first
+	List(ctx context.Context) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+)
+
+// IFileRepository defines the interface for file metadata persistence
+type IFileRepository interface {
+	Create(ctx context.Context, file *domain.File) error
+	GetByID(ctx context.Context, id string) (*domain.File, error)
+	Exists(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+)
+
+// IOutboxRepository defines the interface for transactional outbox persistence
+type IOutboxRepository interface {
+	Add(ctx context.Context, message *domain.OutboxMessage) error
+	FetchPending(ctx context.Context, limit int) ([]*domain.OutboxMessage, error)
+	Lease(ctx context.Context, ids []string, until time.Time) error
+	Delete(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error
+	MarkExhausted(ctx context.Context, id string, lastError string) error
+}
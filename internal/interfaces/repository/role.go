@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+)
+
+// IRoleRepository defines the interface for role assignment persistence. All methods
+// are scoped to the tenant of the principal in ctx.
+type IRoleRepository interface {
+	Get(ctx context.Context, principalType domain.PrincipalType, principalID string) (*domain.RoleAssignment, error)
+	// Assign creates the assignment or replaces the role of an existing one
+	Assign(ctx context.Context, assignment *domain.RoleAssignment) error
+	List(ctx context.Context) ([]*domain.RoleAssignment, error)
+	Delete(ctx context.Context, principalType domain.PrincipalType, principalID string) error
+}
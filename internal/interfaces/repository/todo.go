@@ -2,12 +2,48 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/google/uuid"
 )
 
 // ITodoRepository defines the interface for todo item persistence
 type ITodoRepository interface {
 	Create(ctx context.Context, item *domain.TodoItem) error
 	GetByID(ctx context.Context, id string) (*domain.TodoItem, error)
+	GetByIDForUpdate(ctx context.Context, id string) (*domain.TodoItem, error)
+	Update(ctx context.Context, item *domain.TodoItem) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, query ListQuery) ([]*domain.TodoItem, error)
+}
+
+// SortField identifies the column todo items are ordered by when listing
+type SortField string
+
+const (
+	SortByDueDate   SortField = "dueDate"
+	SortByCreatedAt SortField = "createdAt"
+)
+
+// ListCursor marks the position of the last item of the previous page
+type ListCursor struct {
+	SortValue time.Time
+	ID        uuid.UUID
+}
+
+// ListQuery describes a filtered, keyset-paginated listing of todo items.
+// Nil filters are not applied.
+type ListQuery struct {
+	DueBefore     *time.Time
+	DueAfter      *time.Time
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	HasAttachment *bool
+	SortBy        SortField
+	Descending    bool
+	After         *ListCursor
+	Limit         int
 }
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+)
+
+// ITransactionManager defines the interface for running repository calls atomically.
+// Repositories invoked with the context passed to fn take part in the transaction.
+type ITransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+)
+
+// AuthUseCase authenticates callers and manages API keys and role assignments
+type AuthUseCase struct {
+	apiKeyRepo    repository.IAPIKeyRepository
+	roleRepo      repository.IRoleRepository
+	txManager     repository.ITransactionManager
+	tokenVerifier client.ITokenVerifier
+	defaultRole   domain.Role
+	operators     map[string]bool
+}
+
+// NewAuthUseCase creates a new AuthUseCase. tokenVerifier may be nil, in which case
+// bearer tokens are rejected and only API keys are accepted. Principals without a role
+// assignment get defaultRole, which may be empty to grant them no permissions.
+// operators lists the "<type>:<id>" references of the principals that are operators.
+func NewAuthUseCase(
+	apiKeyRepo repository.IAPIKeyRepository,
+	roleRepo repository.IRoleRepository,
+	txManager repository.ITransactionManager,
+	tokenVerifier client.ITokenVerifier,
+	defaultRole domain.Role,
+	operators []string,
+) *AuthUseCase {
+	operatorSet := make(map[string]bool, len(operators))
+	for _, ref := range operators {
+		operatorSet[ref] = true
+	}
+	return &AuthUseCase{
+		apiKeyRepo:    apiKeyRepo,
+		roleRepo:      roleRepo,
+		txManager:     txManager,
+		tokenVerifier: tokenVerifier,
+		defaultRole:   defaultRole,
+		operators:     operatorSet,
+	}
+}
+
+// CreateAPIKeyRequest represents the request to create an API key.
+// A non-empty Role is assigned to the new key.
+type CreateAPIKeyRequest struct {
+	Name string
+	Role domain.Role
+}
+
+// CreateAPIKeyResult holds a new API key; Key is only available at creation time
+type CreateAPIKeyResult struct {
+	APIKey *domain.APIKey
+	Key    string
+	Role   domain.Role
+}
+
+// AssignRoleRequest represents the request to assign a role to a principal
+type AssignRoleRequest struct {
+	PrincipalType domain.PrincipalType
+	PrincipalID   string
+	Role          domain.Role
+}
+
+// AuthenticateToken verifies a JWT bearer token
+func (uc *AuthUseCase) AuthenticateToken(ctx context.Context, token string) (*domain.Principal, error) {
+	if uc.tokenVerifier == nil {
+		return nil, newUnauthorizedError("bearer tokens are not accepted")
+	}
+	principal, err := uc.tokenVerifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return uc.resolveRole(ctx, principal)
+}
+
+// AuthenticateAPIKey looks up an API key by its hash and rejects unknown and revoked keys
+func (uc *AuthUseCase) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	if !strings.HasPrefix(key, domain.APIKeyPrefix) {
+		return nil, newUnauthorizedError("invalid api key")
+	}
+	apiKey, err := uc.apiKeyRepo.GetByHash(ctx, domain.HashAPIKey(key))
+	if isNotFound(err) {
+		return nil, newUnauthorizedError("invalid api key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.IsRevoked() {
+		return nil, newUnauthorizedError("invalid api key")
+	}
+	return uc.resolveRole(ctx, apiKey.Principal())
+}
+
+// resolveRole sets the role assigned to an authenticated principal within its tenant and
+// marks configured operators
+func (uc *AuthUseCase) resolveRole(ctx context.Context, principal *domain.Principal) (*domain.Principal, error) {
+	principal.Operator = uc.operators[principal.Ref()]
+	assignment, err := uc.roleRepo.Get(domain.WithPrincipal(ctx, principal), principal.Type, principal.ID)
+	if isNotFound(err) {
+		principal.Role = uc.defaultRole
+		return principal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	principal.Role = assignment.Role
+	return principal, nil
+}
+
+// CreateAPIKey generates and stores a new API key in the caller's tenant. Assigning a
+// role to the key additionally requires the role:manage permission.
+func (uc *AuthUseCase) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*CreateAPIKeyResult, error) {
+	if err := Authorize(ctx, domain.PermissionAPIKeyManage); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 255 {
+		return nil, apperrors.NewAppError("INVALID_NAME", "name must be between 1 and 255 characters", http.StatusBadRequest, nil)
+	}
+	if req.Role != "" {
+		if err := Authorize(ctx, domain.PermissionRoleManage); err != nil {
+			return nil, err
+		}
+		if err := validateRole(req.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	tenantID := domain.TenantFromContext(ctx)
+	apiKey, key, err := domain.NewAPIKey(tenantID, name)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.apiKeyRepo.Create(ctx, apiKey); err != nil {
+			return err
+		}
+		if req.Role == "" {
+			return nil
+		}
+		return uc.roleRepo.Assign(ctx, &domain.RoleAssignment{
+			TenantID:      tenantID,
+			PrincipalType: domain.PrincipalAPIKey,
+			PrincipalID:   apiKey.ID.String(),
+			Role:          req.Role,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreateAPIKeyResult{APIKey: apiKey, Key: key, Role: req.Role}, nil
+}
+
+// ListAPIKeys returns the API keys of the caller's tenant, including revoked ones
+func (uc *AuthUseCase) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	if err := Authorize(ctx, domain.PermissionAPIKeyManage); err != nil {
+		return nil, err
+	}
+	return uc.apiKeyRepo.List(ctx)
+}
+
+// RevokeAPIKey revokes an API key so that it can no longer authenticate
+func (uc *AuthUseCase) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := Authorize(ctx, domain.PermissionAPIKeyManage); err != nil {
+		return err
+	}
+	return uc.apiKeyRepo.Revoke(ctx, id, time.Now())
+}
+
+// AssignRole assigns a role to a principal of the caller's tenant, replacing its current role
+func (uc *AuthUseCase) AssignRole(ctx context.Context, req AssignRoleRequest) (*domain.RoleAssignment, error) {
+	if err := Authorize(ctx, domain.PermissionRoleManage); err != nil {
+		return nil, err
+	}
+	if err := validatePrincipal(req.PrincipalType, req.PrincipalID); err != nil {
+		return nil, err
+	}
+	if err := validateRole(req.Role); err != nil {
+		return nil, err
+	}
+	assignment := &domain.RoleAssignment{
+		TenantID:      domain.TenantFromContext(ctx),
+		PrincipalType: req.PrincipalType,
+		PrincipalID:   req.PrincipalID,
+		Role:          req.Role,
+	}
+	if err := uc.roleRepo.Assign(ctx, assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// ListRoleAssignments returns the role assignments of the caller's tenant
+func (uc *AuthUseCase) ListRoleAssignments(ctx context.Context) ([]*domain.RoleAssignment, error) {
+	if err := Authorize(ctx, domain.PermissionRoleManage); err != nil {
+		return nil, err
+	}
+	return uc.roleRepo.List(ctx)
+}
+
+// RemoveRole removes the role assignment of a principal of the caller's tenant
+func (uc *AuthUseCase) RemoveRole(ctx context.Context, principalType domain.PrincipalType, principalID string) error {
+	if err := Authorize(ctx, domain.PermissionRoleManage); err != nil {
+		return err
+	}
+	if err := validatePrincipal(principalType, principalID); err != nil {
+		return err
+	}
+	return uc.roleRepo.Delete(ctx, principalType, principalID)
+}
+
+// validateRole ensures the role is a known role
+func validateRole(role domain.Role) error {
+	if !role.IsValid() {
+		return apperrors.NewAppError("INVALID_ROLE", "role must be one of viewer, editor or admin", http.StatusBadRequest, nil)
+	}
+	return nil
+}
+
+// validatePrincipal validates the type and ID of a principal
+func validatePrincipal(principalType domain.PrincipalType, principalID string) error {
+	if principalType != domain.PrincipalUser && principalType != domain.PrincipalAPIKey {
+		return apperrors.NewAppError("INVALID_PRINCIPAL_TYPE", "principal type must be user or api_key", http.StatusBadRequest, nil)
+	}
+	if principalID == "" || len(principalID) > 255 {
+		return apperrors.NewAppError("INVALID_PRINCIPAL_ID", "principal id must be between 1 and 255 characters", http.StatusBadRequest, nil)
+	}
+	return nil
+}
+
+// isNotFound reports whether err is an AppError with a 404 status
+func isNotFound(err error) bool {
+	appErr, ok := apperrors.AsAppError(err)
+	return ok && appErr.HTTPStatus == http.StatusNotFound
+}
+
+// newUnauthorizedError builds the error returned for missing or invalid credentials
+func newUnauthorizedError(message string) error {
+	return apperrors.NewAppError("UNAUTHORIZED", message, http.StatusUnauthorized, nil)
+}
@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// roleNotFound is returned by the role repository mock for principals without a role assignment
+var roleNotFound = apperrors.NewAppError("ROLE_ASSIGNMENT_NOT_FOUND", "role assignment not found", http.StatusNotFound, nil)
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	keyID := uuid.New()
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name          string
+		key           string
+		setupMocks    func(*mocks.MockIAPIKeyRepository, *mocks.MockIRoleRepository)
+		expectedRole  domain.Role
+		expectedError error
+	}{
+		{
+			name: "valid key",
+			key:  "ice_valid",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("GetByHash", mock.Anything, domain.HashAPIKey("ice_valid")).
+					Return(&domain.APIKey{ID: keyID, TenantID: "acme", Name: "ci"}, nil)
+				roleRepo.On("Get", mock.MatchedBy(func(ctx context.Context) bool {
+					return domain.TenantFromContext(ctx) == "acme"
+				}), domain.PrincipalAPIKey, keyID.String()).Return(&domain.RoleAssignment{Role: domain.RoleEditor}, nil)
+			},
+			expectedRole: domain.RoleEditor,
+		},
+		{
+			name: "key without role assignment",
+			key:  "ice_valid",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("GetByHash", mock.Anything, domain.HashAPIKey("ice_valid")).
+					Return(&domain.APIKey{ID: keyID, TenantID: "acme", Name: "ci"}, nil)
+				roleRepo.On("Get", mock.Anything, domain.PrincipalAPIKey, keyID.String()).Return(nil, roleNotFound)
+			},
+			expectedRole: domain.RoleViewer,
+		},
+		{
+			name: "missing prefix",
+			key:  "valid",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("UNAUTHORIZED", "invalid api key", http.StatusUnauthorized, nil),
+		},
+		{
+			name: "unknown key",
+			key:  "ice_unknown",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("GetByHash", mock.Anything, domain.HashAPIKey("ice_unknown")).
+					Return(nil, apperrors.NewAppError("API_KEY_NOT_FOUND", "api key not found", http.StatusNotFound, nil))
+			},
+			expectedError: apperrors.NewAppError("UNAUTHORIZED", "invalid api key", http.StatusUnauthorized, nil),
+		},
+		{
+			name: "revoked key",
+			key:  "ice_revoked",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("GetByHash", mock.Anything, domain.HashAPIKey("ice_revoked")).
+					Return(&domain.APIKey{ID: keyID, RevokedAt: &revokedAt}, nil)
+			},
+			expectedError: apperrors.NewAppError("UNAUTHORIZED", "invalid api key", http.StatusUnauthorized, nil),
+		},
+		{
+			name: "repository error",
+			key:  "ice_valid",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("GetByHash", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+		{
+			name: "role repository error",
+			key:  "ice_valid",
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("GetByHash", mock.Anything, mock.Anything).Return(&domain.APIKey{ID: keyID}, nil)
+				roleRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockIAPIKeyRepository(t)
+			roleRepo := mocks.NewMockIRoleRepository(t)
+			tt.setupMocks(repo, roleRepo)
+
+			uc := NewAuthUseCase(repo, roleRepo, newTransactionManager(t), nil, domain.RoleViewer, nil)
+			principal, err := uc.AuthenticateAPIKey(context.Background(), tt.key)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if appErr, ok := apperrors.AsAppError(tt.expectedError); ok {
+					actualErr, ok := apperrors.AsAppError(err)
+					assert.True(t, ok, "expected AppError")
+					assert.Equal(t, appErr.Code, actualErr.Code)
+				}
+				assert.Nil(t, principal)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &domain.Principal{
+					ID:       keyID.String(),
+					Type:     domain.PrincipalAPIKey,
+					Name:     "ci",
+					TenantID: "acme",
+					Role:     tt.expectedRole,
+				}, principal)
+			}
+		})
+	}
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	t.Run("verifies token", func(t *testing.T) {
+		verifier := mocks.NewMockITokenVerifier(t)
+		verifier.On("Verify", mock.Anything, "token").
+			Return(&domain.Principal{ID: "user-1", Type: domain.PrincipalUser, TenantID: domain.DefaultTenantID}, nil)
+		roleRepo := mocks.NewMockIRoleRepository(t)
+		roleRepo.On("Get", mock.Anything, domain.PrincipalUser, "user-1").Return(&domain.RoleAssignment{Role: domain.RoleAdmin}, nil)
+
+		uc := NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), roleRepo, newTransactionManager(t), verifier, "", nil)
+		principal, err := uc.AuthenticateToken(context.Background(), "token")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", principal.ID)
+		assert.Equal(t, domain.RoleAdmin, principal.Role)
+		assert.False(t, principal.Operator)
+	})
+
+	t.Run("marks operators", func(t *testing.T) {
+		verifier := mocks.NewMockITokenVerifier(t)
+		verifier.On("Verify", mock.Anything, "token").
+			Return(&domain.Principal{ID: "user-1", Type: domain.PrincipalUser, TenantID: domain.DefaultTenantID}, nil)
+		roleRepo := mocks.NewMockIRoleRepository(t)
+		roleRepo.On("Get", mock.Anything, domain.PrincipalUser, "user-1").Return(nil, roleNotFound)
+
+		uc := NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), roleRepo, newTransactionManager(t), verifier, "", []string{"api_key:user-1", "user:user-1"})
+		principal, err := uc.AuthenticateToken(context.Background(), "token")
+
+		assert.NoError(t, err)
+		assert.True(t, principal.Operator)
+		assert.True(t, principal.Can(domain.PermissionStreamAdmin))
+		assert.False(t, principal.Can(domain.PermissionTodoRead))
+	})
+
+	t.Run("no verifier configured", func(t *testing.T) {
+		uc := NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), mocks.NewMockIRoleRepository(t), newTransactionManager(t), nil, "", nil)
+		principal, err := uc.AuthenticateToken(context.Background(), "token")
+
+		appErr, ok := apperrors.AsAppError(err)
+		require.True(t, ok, "expected AppError")
+		assert.Equal(t, http.StatusUnauthorized, appErr.HTTPStatus)
+		assert.Nil(t, principal)
+	})
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		req           CreateAPIKeyRequest
+		setupMocks    func(*mocks.MockIAPIKeyRepository, *mocks.MockIRoleRepository)
+		expectedError error
+	}{
+		{
+			name: "valid name",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  CreateAPIKeyRequest{Name: "  ci  "},
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(key *domain.APIKey) bool {
+					return key.Name == "ci" && key.TenantID == domain.DefaultTenantID
+				})).Return(nil)
+			},
+		},
+		{
+			name: "with role",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  CreateAPIKeyRequest{Name: "ci", Role: domain.RoleEditor},
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+				roleRepo.On("Assign", mock.Anything, mock.MatchedBy(func(assignment *domain.RoleAssignment) bool {
+					return assignment.PrincipalType == domain.PrincipalAPIKey &&
+						assignment.Role == domain.RoleEditor &&
+						assignment.TenantID == domain.DefaultTenantID
+				})).Return(nil)
+			},
+		},
+		{
+			name: "invalid role",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  CreateAPIKeyRequest{Name: "ci", Role: "owner"},
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_ROLE", "role must be one of viewer, editor or admin", http.StatusBadRequest, nil),
+		},
+		{
+			name: "not an admin",
+			ctx:  contextWithRole(domain.RoleEditor),
+			req:  CreateAPIKeyRequest{Name: "ci"},
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, authorization fails early
+			},
+			expectedError: apperrors.NewAppError("FORBIDDEN", "missing permission apikey:manage", http.StatusForbidden, nil),
+		},
+		{
+			name: "blank name",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  CreateAPIKeyRequest{Name: "   "},
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_NAME", "name must be between 1 and 255 characters", http.StatusBadRequest, nil),
+		},
+		{
+			name: "repository error",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  CreateAPIKeyRequest{Name: "ci"},
+			setupMocks: func(repo *mocks.MockIAPIKeyRepository, roleRepo *mocks.MockIRoleRepository) {
+				repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockIAPIKeyRepository(t)
+			roleRepo := mocks.NewMockIRoleRepository(t)
+			tt.setupMocks(repo, roleRepo)
+
+			uc := NewAuthUseCase(repo, roleRepo, newTransactionManager(t), nil, "", nil)
+			result, err := uc.CreateAPIKey(tt.ctx, tt.req)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if appErr, ok := apperrors.AsAppError(tt.expectedError); ok {
+					actualErr, ok := apperrors.AsAppError(err)
+					assert.True(t, ok, "expected AppError")
+					assert.Equal(t, appErr.Code, actualErr.Code)
+				}
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.HashAPIKey(result.Key), result.APIKey.KeyHash)
+				assert.Equal(t, result.APIKey.Prefix, result.Key[:len(result.APIKey.Prefix)])
+				assert.Equal(t, tt.req.Role, result.Role)
+			}
+		})
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		req           AssignRoleRequest
+		setupMocks    func(*mocks.MockIRoleRepository)
+		expectedError error
+	}{
+		{
+			name: "assigns role",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  AssignRoleRequest{PrincipalType: domain.PrincipalUser, PrincipalID: "user-2", Role: domain.RoleViewer},
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				roleRepo.On("Assign", mock.Anything, &domain.RoleAssignment{
+					TenantID:      domain.DefaultTenantID,
+					PrincipalType: domain.PrincipalUser,
+					PrincipalID:   "user-2",
+					Role:          domain.RoleViewer,
+				}).Return(nil)
+			},
+		},
+		{
+			name: "unknown role",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  AssignRoleRequest{PrincipalType: domain.PrincipalUser, PrincipalID: "user-2", Role: "owner"},
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_ROLE", "role must be one of viewer, editor or admin", http.StatusBadRequest, nil),
+		},
+		{
+			name: "unknown principal type",
+			ctx:  contextWithRole(domain.RoleAdmin),
+			req:  AssignRoleRequest{PrincipalType: "group", PrincipalID: "user-2", Role: domain.RoleViewer},
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_PRINCIPAL_TYPE", "principal type must be user or api_key", http.StatusBadRequest, nil),
+		},
+		{
+			name: "not an admin",
+			ctx:  contextWithRole(domain.RoleEditor),
+			req:  AssignRoleRequest{PrincipalType: domain.PrincipalUser, PrincipalID: "user-1", Role: domain.RoleAdmin},
+			setupMocks: func(roleRepo *mocks.MockIRoleRepository) {
+				// No mocks needed, authorization fails early
+			},
+			expectedError: apperrors.NewAppError("FORBIDDEN", "missing permission role:manage", http.StatusForbidden, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleRepo := mocks.NewMockIRoleRepository(t)
+			tt.setupMocks(roleRepo)
+
+			uc := NewAuthUseCase(mocks.NewMockIAPIKeyRepository(t), roleRepo, newTransactionManager(t), nil, "", nil)
+			assignment, err := uc.AssignRole(tt.ctx, tt.req)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if appErr, ok := apperrors.AsAppError(tt.expectedError); ok {
+					actualErr, ok := apperrors.AsAppError(err)
+					assert.True(t, ok, "expected AppError")
+					assert.Equal(t, appErr.Code, actualErr.Code)
+				}
+				assert.Nil(t, assignment)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.req.Role, assignment.Role)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+)
+
+// Authorize returns a FORBIDDEN error unless the principal of ctx has the permission.
+// Contexts without a principal are denied.
+func Authorize(ctx context.Context, permission domain.Permission) error {
+	if !domain.PrincipalFromContext(ctx).Can(permission) {
+		return apperrors.NewAppError("FORBIDDEN", "missing permission "+string(permission), http.StatusForbidden, nil)
+	}
+	return nil
+}
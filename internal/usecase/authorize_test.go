@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contextWithRole returns a context carrying a principal of the default tenant with the given role
+func contextWithRole(role domain.Role) context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{
+		ID:       "user-1",
+		Type:     domain.PrincipalUser,
+		TenantID: domain.DefaultTenantID,
+		Role:     role,
+	})
+}
+
+// operatorContext returns a context carrying an operator of the default tenant without a role
+func operatorContext() context.Context {
+	return domain.WithPrincipal(context.Background(), &domain.Principal{
+		ID:       "operator-1",
+		Type:     domain.PrincipalUser,
+		TenantID: domain.DefaultTenantID,
+		Operator: true,
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		permission domain.Permission
+		allowed    bool
+	}{
+		{
+			name:       "viewer reads todos",
+			ctx:        contextWithRole(domain.RoleViewer),
+			permission: domain.PermissionTodoRead,
+			allowed:    true,
+		},
+		{
+			name:       "viewer cannot create todos",
+			ctx:        contextWithRole(domain.RoleViewer),
+			permission: domain.PermissionTodoCreate,
+		},
+		{
+			name:       "editor deletes todos",
+			ctx:        contextWithRole(domain.RoleEditor),
+			permission: domain.PermissionTodoDelete,
+			allowed:    true,
+		},
+		{
+			name:       "editor cannot manage api keys",
+			ctx:        contextWithRole(domain.RoleEditor),
+			permission: domain.PermissionAPIKeyManage,
+		},
+		{
+			name:       "admin manages roles",
+			ctx:        contextWithRole(domain.RoleAdmin),
+			permission: domain.PermissionRoleManage,
+			allowed:    true,
+		},
+		{
+			name:       "admin cannot administer streams",
+			ctx:        contextWithRole(domain.RoleAdmin),
+			permission: domain.PermissionStreamAdmin,
+		},
+		{
+			name:       "operator administers streams",
+			ctx:        operatorContext(),
+			permission: domain.PermissionStreamAdmin,
+			allowed:    true,
+		},
+		{
+			name:       "operator without role cannot read todos",
+			ctx:        operatorContext(),
+			permission: domain.PermissionTodoRead,
+		},
+		{
+			name:       "principal without role",
+			ctx:        contextWithRole(""),
+			permission: domain.PermissionTodoRead,
+		},
+		{
+			name:       "no principal",
+			ctx:        context.Background(),
+			permission: domain.PermissionTodoRead,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.ctx, tt.permission)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			appErr, ok := apperrors.AsAppError(err)
+			require.True(t, ok, "expected AppError")
+			assert.Equal(t, "FORBIDDEN", appErr.Code)
+			assert.Equal(t, http.StatusForbidden, appErr.HTTPStatus)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+)
+
+const (
+	defaultDeadLetterLimit = 50
+	maxDeadLetterLimit     = 500
+)
+
+// streamEntryID matches Redis stream entry IDs such as 1700000000000-0
+var streamEntryID = regexp.MustCompile(`^\d+-\d+$`)
+
+// DeadLetterUseCase handles inspection and replay of dead-lettered stream messages
+type DeadLetterUseCase struct {
+	store   client.IDeadLetterStore
+	streams map[string]bool
+}
+
+// NewDeadLetterUseCase creates a new DeadLetterUseCase
+func NewDeadLetterUseCase(store client.IDeadLetterStore) *DeadLetterUseCase {
+	return &DeadLetterUseCase{
+		store: store,
+		streams: map[string]bool{
+			todoItemsStream: true,
+		},
+	}
+}
+
+// ListDeadLettersRequest represents a request for a page of dead-lettered messages
+type ListDeadLettersRequest struct {
+	Stream string
+	After  string
+	Limit  int
+}
+
+// ListDeadLettersResult represents a page of dead-lettered messages
+type ListDeadLettersResult struct {
+	Items      []*client.DeadLetter
+	NextCursor string
+}
+
+// PurgeDeadLettersRequest represents a request to remove dead-lettered messages.
+// An empty IDs list purges the whole dead-letter queue.
+type PurgeDeadLettersRequest struct {
+	Stream string
+	IDs    []string
+}
+
+// ListDeadLetters returns a page of a stream's dead-lettered messages
+func (uc *DeadLetterUseCase) ListDeadLetters(ctx context.Context, req ListDeadLettersRequest) (*ListDeadLettersResult, error) {
+	if err := Authorize(ctx, domain.PermissionStreamAdmin); err != nil {
+		return nil, err
+	}
+	if err := uc.validateStream(req.Stream); err != nil {
+		return nil, err
+	}
+	if req.After != "" {
+		if err := validateEntryID(req.After); err != nil {
+			return nil, err
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDeadLetterLimit
+	}
+	if limit > maxDeadLetterLimit {
+		limit = maxDeadLetterLimit
+	}
+
+	letters, err := uc.store.List(ctx, req.Stream, req.After, int64(limit+1))
+	if err != nil {
+		return nil, err
+	}
+	result := &ListDeadLettersResult{Items: letters}
+	if len(letters) > limit {
+		result.Items = letters[:limit]
+		result.NextCursor = letters[limit-1].ID
+	}
+	return result, nil
+}
+
+// ReplayDeadLetter republishes a dead-lettered message to its original stream
+func (uc *DeadLetterUseCase) ReplayDeadLetter(ctx context.Context, stream string, id string) (string, error) {
+	if err := Authorize(ctx, domain.PermissionStreamAdmin); err != nil {
+		return "", err
+	}
+	if err := uc.validateStream(stream); err != nil {
+		return "", err
+	}
+	if err := validateEntryID(id); err != nil {
+		return "", err
+	}
+	return uc.store.Replay(ctx, stream, id)
+}
+
+// PurgeDeadLetters removes dead-lettered messages and returns how many were removed
+func (uc *DeadLetterUseCase) PurgeDeadLetters(ctx context.Context, req PurgeDeadLettersRequest) (int64, error) {
+	if err := Authorize(ctx, domain.PermissionStreamAdmin); err != nil {
+		return 0, err
+	}
+	if err := uc.validateStream(req.Stream); err != nil {
+		return 0, err
+	}
+	for _, id := range req.IDs {
+		if err := validateEntryID(id); err != nil {
+			return 0, err
+		}
+	}
+	return uc.store.Purge(ctx, req.Stream, req.IDs)
+}
+
+// DeleteDeadLetter removes a single dead-lettered message
+func (uc *DeadLetterUseCase) DeleteDeadLetter(ctx context.Context, stream string, id string) error {
+	deleted, err := uc.PurgeDeadLetters(ctx, PurgeDeadLettersRequest{Stream: stream, IDs: []string{id}})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return apperrors.NewAppError("DEAD_LETTER_NOT_FOUND", "dead letter not found", http.StatusNotFound, nil)
+	}
+	return nil
+}
+
+// validateStream ensures the stream has a dead-letter queue
+func (uc *DeadLetterUseCase) validateStream(stream string) error {
+	if !uc.streams[stream] {
+		return apperrors.NewAppError("STREAM_NOT_FOUND", "stream not found", http.StatusNotFound, nil)
+	}
+	return nil
+}
+
+// validateEntryID validates a stream entry ID
+func validateEntryID(id string) error {
+	if !streamEntryID.MatchString(id) {
+		return apperrors.NewAppError("INVALID_ID", "invalid entry id", http.StatusBadRequest, nil)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListDeadLetters(t *testing.T) {
+	tests := []struct {
+		name               string
+		req                ListDeadLettersRequest
+		setupMocks         func(*mocks.MockIDeadLetterStore)
+		expectedCount      int
+		expectedNextCursor string
+		expectedError      error
+	}{
+		{
+			name: "default limit",
+			req:  ListDeadLettersRequest{Stream: "todo-items"},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("List", mock.Anything, "todo-items", "", int64(defaultDeadLetterLimit+1)).
+					Return([]*client.DeadLetter{{ID: "1-0"}, {ID: "2-0"}}, nil)
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "more entries than limit",
+			req:  ListDeadLettersRequest{Stream: "todo-items", After: "1-0", Limit: 2},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("List", mock.Anything, "todo-items", "1-0", int64(3)).
+					Return([]*client.DeadLetter{{ID: "2-0"}, {ID: "3-0"}, {ID: "4-0"}}, nil)
+			},
+			expectedCount:      2,
+			expectedNextCursor: "3-0",
+		},
+		{
+			name: "limit is capped",
+			req:  ListDeadLettersRequest{Stream: "todo-items", Limit: 10000},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("List", mock.Anything, "todo-items", "", int64(maxDeadLetterLimit+1)).
+					Return([]*client.DeadLetter{}, nil)
+			},
+		},
+		{
+			name: "unknown stream",
+			req:  ListDeadLettersRequest{Stream: "other"},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("STREAM_NOT_FOUND", "stream not found", http.StatusNotFound, nil),
+		},
+		{
+			name: "invalid cursor",
+			req:  ListDeadLettersRequest{Stream: "todo-items", After: "abc"},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_ID", "invalid entry id", http.StatusBadRequest, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewMockIDeadLetterStore(t)
+			tt.setupMocks(store)
+
+			uc := NewDeadLetterUseCase(store)
+			result, err := uc.ListDeadLetters(operatorContext(), tt.req)
+
+			if tt.expectedError != nil {
+				appErr, _ := apperrors.AsAppError(tt.expectedError)
+				actualErr, ok := apperrors.AsAppError(err)
+				assert.True(t, ok, "expected AppError")
+				assert.Equal(t, appErr.Code, actualErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result.Items, tt.expectedCount)
+				assert.Equal(t, tt.expectedNextCursor, result.NextCursor)
+			}
+		})
+	}
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	tests := []struct {
+		name          string
+		stream        string
+		id            string
+		setupMocks    func(*mocks.MockIDeadLetterStore)
+		expectedError error
+	}{
+		{
+			name:   "successful replay",
+			stream: "todo-items",
+			id:     "1-0",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Replay", mock.Anything, "todo-items", "1-0").Return("5-0", nil)
+			},
+		},
+		{
+			name:   "not found",
+			stream: "todo-items",
+			id:     "1-0",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Replay", mock.Anything, "todo-items", "1-0").
+					Return("", apperrors.NewAppError("DEAD_LETTER_NOT_FOUND", "dead letter not found", http.StatusNotFound, nil))
+			},
+			expectedError: apperrors.NewAppError("DEAD_LETTER_NOT_FOUND", "dead letter not found", http.StatusNotFound, nil),
+		},
+		{
+			name:   "invalid id",
+			stream: "todo-items",
+			id:     "1",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_ID", "invalid entry id", http.StatusBadRequest, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewMockIDeadLetterStore(t)
+			tt.setupMocks(store)
+
+			uc := NewDeadLetterUseCase(store)
+			id, err := uc.ReplayDeadLetter(operatorContext(), tt.stream, tt.id)
+
+			if tt.expectedError != nil {
+				appErr, _ := apperrors.AsAppError(tt.expectedError)
+				actualErr, ok := apperrors.AsAppError(err)
+				assert.True(t, ok, "expected AppError")
+				assert.Equal(t, appErr.Code, actualErr.Code)
+				assert.Empty(t, id)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "5-0", id)
+			}
+		})
+	}
+}
+
+func TestPurgeDeadLetters(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           PurgeDeadLettersRequest
+		setupMocks    func(*mocks.MockIDeadLetterStore)
+		expectedError bool
+	}{
+		{
+			name: "purge all",
+			req:  PurgeDeadLettersRequest{Stream: "todo-items"},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string(nil)).Return(int64(3), nil)
+			},
+		},
+		{
+			name: "purge selected entries",
+			req:  PurgeDeadLettersRequest{Stream: "todo-items", IDs: []string{"1-0"}},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string{"1-0"}).Return(int64(1), nil)
+			},
+		},
+		{
+			name: "store error",
+			req:  PurgeDeadLettersRequest{Stream: "todo-items"},
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string(nil)).Return(int64(0), errors.New("redis error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewMockIDeadLetterStore(t)
+			tt.setupMocks(store)
+
+			uc := NewDeadLetterUseCase(store)
+			_, err := uc.PurgeDeadLetters(operatorContext(), tt.req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeleteDeadLetter(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		setupMocks    func(*mocks.MockIDeadLetterStore)
+		expectedError error
+	}{
+		{
+			name: "deleted",
+			id:   "1-0",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string{"1-0"}).Return(int64(1), nil)
+			},
+		},
+		{
+			name: "not found",
+			id:   "1-0",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				store.On("Purge", mock.Anything, "todo-items", []string{"1-0"}).Return(int64(0), nil)
+			},
+			expectedError: apperrors.NewAppError("DEAD_LETTER_NOT_FOUND", "dead letter not found", http.StatusNotFound, nil),
+		},
+		{
+			name: "invalid id",
+			id:   "abc",
+			setupMocks: func(store *mocks.MockIDeadLetterStore) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_ID", "invalid entry id", http.StatusBadRequest, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewMockIDeadLetterStore(t)
+			tt.setupMocks(store)
+
+			uc := NewDeadLetterUseCase(store)
+			err := uc.DeleteDeadLetter(operatorContext(), "todo-items", tt.id)
+
+			if tt.expectedError != nil {
+				appErr, _ := apperrors.AsAppError(tt.expectedError)
+				actualErr, ok := apperrors.AsAppError(err)
+				assert.True(t, ok, "expected AppError")
+				assert.Equal(t, appErr.Code, actualErr.Code)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,22 +1,34 @@
 package usecase
 
 import (
+	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"mime"
 	"net/http"
 
+	"github.com/ar-agahian/ice-assignment/internal/domain"
 	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
 	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+	"github.com/google/uuid"
 )
 
 const (
-	maxFileSize = 10 * 1024 * 1024
+	defaultMaxFileSize = 10 * 1024 * 1024
+	// sniffLen is the number of bytes http.DetectContentType considers
+	sniffLen = 512
 )
 
 var (
-	allowedMimeTypes = []string{
+	defaultAllowedMimeTypes = []string{
 		"image/jpeg",
 		"image/jpg",
 		"image/png",
@@ -24,46 +36,85 @@ var (
 		"application/pdf",
 		"text/plain",
 	}
+
+	errFileTooLarge = errors.New("file exceeds maximum allowed size")
 )
 
+// UploadConfig bounds the files accepted for upload. Zero values fall back to a 10 MB
+// limit and common image, PDF and plain text types.
+type UploadConfig struct {
+	MaxSize          int64    `yaml:"maxSize" env:"UPLOAD_MAX_SIZE"`
+	AllowedMimeTypes []string `yaml:"allowedMimeTypes" env:"UPLOAD_ALLOWED_MIME_TYPES"`
+}
+
 // FileUseCase handles file upload business logic
 type FileUseCase struct {
-	storageRepo client.IFileStorage
+	storageRepo      client.IFileStorage
+	fileRepo         repository.IFileRepository
+	maxFileSize      int64
+	allowedMimeTypes []string
 }
 
 // NewFileUseCase creates a new FileUseCase
-func NewFileUseCase(storageRepo client.IFileStorage) *FileUseCase {
+func NewFileUseCase(storageRepo client.IFileStorage, fileRepo repository.IFileRepository, cfg UploadConfig) *FileUseCase {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = defaultMaxFileSize
+	}
+	if len(cfg.AllowedMimeTypes) == 0 {
+		cfg.AllowedMimeTypes = defaultAllowedMimeTypes
+	}
 	return &FileUseCase{
-		storageRepo: storageRepo,
+		storageRepo:      storageRepo,
+		fileRepo:         fileRepo,
+		maxFileSize:      cfg.MaxSize,
+		allowedMimeTypes: cfg.AllowedMimeTypes,
 	}
 }
 
-// UploadFileRequest represents the request to upload a file
+// MaxFileSize returns the largest accepted upload in bytes
+func (uc *FileUseCase) MaxFileSize() int64 {
+	return uc.maxFileSize
+}
+
+// UploadFileRequest represents the request to upload a file.
+// Size is the size declared by the client, or 0 when unknown; the actual
+// number of bytes read from File is enforced independently. UploadedBy
+// defaults to the ID of the authenticated principal.
 type UploadFileRequest struct {
-	File        io.Reader
-	ContentType string
-	Size        int64
-	Filename    string
+	File       io.Reader
+	Size       int64
+	Filename   string
+	UploadedBy string
 }
 
-// UploadFile uploads a file to S3 and returns the file ID
-func (uc *FileUseCase) UploadFile(ctx context.Context, req UploadFileRequest) (string, error) {
+// UploadFile streams a file to S3, records its metadata and returns the file ID.
+// The content type is detected from the first bytes of the stream and the size
+// limit is enforced while the body is being read, so the file is never buffered
+// as a whole.
+func (uc *FileUseCase) UploadFile(ctx context.Context, req UploadFileRequest) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "FileUseCase.UploadFile")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionAssetCreate); err != nil {
+		return "", err
+	}
 	if req.File == nil {
 		return "", apperrors.NewAppError("FILE_REQUIRED", "file is required", http.StatusBadRequest, nil)
 	}
-	if req.Size == 0 {
-		return "", apperrors.NewAppError("FILE_EMPTY", "file cannot be empty", http.StatusBadRequest, nil)
+	if req.Size > uc.maxFileSize {
+		return "", uc.newFileTooLargeError(nil)
 	}
-	if req.Size > maxFileSize {
-		return "", apperrors.NewAppError(
-			"FILE_TOO_LARGE",
-			fmt.Sprintf("file size exceeds maximum allowed size of %d bytes", maxFileSize),
-			http.StatusBadRequest,
-			nil,
-		)
+
+	body := bufio.NewReaderSize(req.File, sniffLen)
+	head, err := body.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", apperrors.NewAppError("INVALID_INPUT", "invalid input", http.StatusBadRequest, err)
+	}
+	if len(head) == 0 {
+		return "", apperrors.NewAppError("FILE_EMPTY", "file cannot be empty", http.StatusBadRequest, nil)
 	}
 
-	if !uc.isValidContentType(req.ContentType) {
+	contentType := http.DetectContentType(head)
+	if !uc.isValidContentType(contentType) {
 		return "", apperrors.NewAppError(
 			"INVALID_FILE_TYPE",
 			"file type not allowed",
@@ -71,13 +122,79 @@ func (uc *FileUseCase) UploadFile(ctx context.Context, req UploadFileRequest) (s
 			nil,
 		)
 	}
-	fileID, err := uc.storageRepo.Upload(ctx, req.File, req.ContentType)
+
+	limited := &limitedReader{r: body, limit: uc.maxFileSize}
+	hasher := sha256.New()
+	fileID, err := uc.storageRepo.Upload(ctx, io.TeeReader(limited, hasher), contentType, req.Filename)
+	if limited.exceeded() {
+		return "", uc.newFileTooLargeError(err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	parsedID, err := uuid.Parse(fileID)
 	if err != nil {
 		return "", err
 	}
+	uploadedBy := req.UploadedBy
+	if principal := domain.PrincipalFromContext(ctx); uploadedBy == "" && principal != nil {
+		uploadedBy = principal.ID
+	}
+	file := &domain.File{
+		ID:               parsedID,
+		OriginalFilename: req.Filename,
+		Size:             limited.n,
+		MimeType:         contentType,
+		SHA256:           hex.EncodeToString(hasher.Sum(nil)),
+		UploadedBy:       uploadedBy,
+	}
+	file.AssignOwner(domain.PrincipalFromContext(ctx))
+	if err := uc.fileRepo.Create(ctx, file); err != nil {
+		// Without metadata the object is unreachable, so do not leave it behind
+		if deleteErr := uc.storageRepo.Delete(ctx, fileID); deleteErr != nil {
+			log.Printf("failed to delete orphaned file %s: %v", fileID, deleteErr)
+		}
+		return "", err
+	}
+	metrics.IncUploads(contentType)
 	return fileID, nil
 }
 
+// GetFileMetadata retrieves the stored metadata of a file
+func (uc *FileUseCase) GetFileMetadata(ctx context.Context, fileID string) (_ *domain.File, err error) {
+	ctx, span := tracer.Start(ctx, "FileUseCase.GetFileMetadata")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionAssetRead); err != nil {
+		return nil, err
+	}
+	return uc.fileRepo.GetByID(ctx, fileID)
+}
+
+// DownloadFileRequest represents the request to download a file
+type DownloadFileRequest struct {
+	FileID      string
+	Range       string
+	IfNoneMatch string
+}
+
+// DownloadFile opens a stored file for streaming. The caller must close the
+// returned body unless the object is reported as not modified.
+func (uc *FileUseCase) DownloadFile(ctx context.Context, req DownloadFileRequest) (_ *client.FileObject, err error) {
+	ctx, span := tracer.Start(ctx, "FileUseCase.DownloadFile")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionAssetRead); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(req.FileID); err != nil {
+		return nil, apperrors.NewAppError("INVALID_ID", "invalid file id", http.StatusBadRequest, nil)
+	}
+	return uc.storageRepo.Open(ctx, req.FileID, client.OpenFileOptions{
+		Range:       req.Range,
+		IfNoneMatch: req.IfNoneMatch,
+	})
+}
+
 // isValidContentType checks if the content type is allowed (business rule)
 func (uc *FileUseCase) isValidContentType(contentType string) bool {
 	if contentType == "" {
@@ -89,10 +206,50 @@ func (uc *FileUseCase) isValidContentType(contentType string) bool {
 		return false
 	}
 
-	for _, allowed := range allowedMimeTypes {
+	for _, allowed := range uc.allowedMimeTypes {
 		if mediaType == allowed {
 			return true
 		}
 	}
 	return false
 }
+
+// newFileTooLargeError builds the error returned when an upload exceeds the size limit
+func (uc *FileUseCase) newFileTooLargeError(err error) error {
+	return apperrors.NewAppError(
+		"FILE_TOO_LARGE",
+		fmt.Sprintf("file size exceeds maximum allowed size of %d bytes", uc.maxFileSize),
+		http.StatusBadRequest,
+		err,
+	)
+}
+
+// limitedReader counts the bytes read through it and fails once more than
+// limit bytes have been consumed, aborting the upload it feeds.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+// Read implements io.Reader
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.exceeded() {
+		return 0, errFileTooLarge
+	}
+	// Allow reading one byte past the limit so an exact-size file still succeeds
+	if remaining := l.limit - l.n + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.exceeded() {
+		return 0, errFileTooLarge
+	}
+	return n, err
+}
+
+// exceeded reports whether more than limit bytes have been read
+func (l *limitedReader) exceeded() bool {
+	return l.n > l.limit
+}
@@ -4,43 +4,78 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
 
-	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
 	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestUploadFile(t *testing.T) {
+	fileID := uuid.New().String()
 	tests := []struct {
 		name          string
 		req           UploadFileRequest
-		setupMocks    func(*mocks.MockIFileStorage)
+		config        UploadConfig
+		principal     *domain.Principal
+		setupMocks    func(*mocks.MockIFileStorage, *mocks.MockIFileRepository)
 		expectedError error
 	}{
 		{
 			name: "successful upload",
 			req: UploadFileRequest{
-				File:        strings.NewReader("test content"),
-				ContentType: "text/plain",
-				Size:        12,
+				File:     strings.NewReader("test content"),
+				Size:     12,
+				Filename: "notes.txt",
 			},
-			setupMocks: func(storage *mocks.MockIFileStorage) {
-				storage.On("Upload", mock.Anything, mock.Anything, "text/plain").Return("file-123", nil)
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				storage.On("Upload", mock.Anything, mock.Anything, "text/plain; charset=utf-8", "notes.txt").
+					Return(func(ctx context.Context, file io.Reader, contentType string, filename string) (string, error) {
+						_, err := io.Copy(io.Discard, file)
+						return fileID, err
+					})
+				fileRepo.On("Create", mock.Anything, mock.MatchedBy(func(file *domain.File) bool {
+					return file.ID.String() == fileID &&
+						file.OriginalFilename == "notes.txt" &&
+						file.Size == 12 &&
+						file.MimeType == "text/plain; charset=utf-8" &&
+						file.SHA256 == "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+				})).Return(nil)
 			},
 			expectedError: nil,
 		},
+		{
+			name: "records the uploading principal",
+			req: UploadFileRequest{
+				File:     strings.NewReader("test content"),
+				Filename: "notes.txt",
+			},
+			principal: &domain.Principal{ID: "user-1", Type: domain.PrincipalUser, TenantID: "acme", Role: domain.RoleEditor},
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				storage.On("Upload", mock.Anything, mock.Anything, "text/plain; charset=utf-8", "notes.txt").
+					Return(func(ctx context.Context, file io.Reader, contentType string, filename string) (string, error) {
+						_, err := io.Copy(io.Discard, file)
+						return fileID, err
+					})
+				fileRepo.On("Create", mock.Anything, mock.MatchedBy(func(file *domain.File) bool {
+					return file.UploadedBy == "user-1" && file.OwnerID == "user-1" && file.TenantID == "acme"
+				})).Return(nil)
+			},
+		},
 		{
 			name: "nil file",
 			req: UploadFileRequest{
-				File:        nil,
-				ContentType: "text/plain",
-				Size:        4,
+				File: nil,
+				Size: 4,
 			},
-			setupMocks: func(storage *mocks.MockIFileStorage) {
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedError: apperrors.NewAppError("FILE_REQUIRED", "file is required", http.StatusBadRequest, nil),
@@ -48,11 +83,10 @@ func TestUploadFile(t *testing.T) {
 		{
 			name: "empty file",
 			req: UploadFileRequest{
-				File:        strings.NewReader(""),
-				ContentType: "text/plain",
-				Size:        0,
+				File: strings.NewReader(""),
+				Size: 0,
 			},
-			setupMocks: func(storage *mocks.MockIFileStorage) {
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedError: apperrors.NewAppError("FILE_EMPTY", "file cannot be empty", http.StatusBadRequest, nil),
@@ -60,50 +94,122 @@ func TestUploadFile(t *testing.T) {
 		{
 			name: "file too large",
 			req: UploadFileRequest{
-				File:        strings.NewReader("test"),
-				ContentType: "text/plain",
-				Size:        11 * 1024 * 1024, // 11MB
+				File: strings.NewReader("test"),
+				Size: 11 * 1024 * 1024, // 11MB
 			},
-			setupMocks: func(storage *mocks.MockIFileStorage) {
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedError: apperrors.NewAppError("FILE_TOO_LARGE", "file size exceeds maximum allowed size of 10485760 bytes", http.StatusBadRequest, nil),
 		},
 		{
 			name: "invalid content type",
-			// Use binary content that will be detected as application/octet-stream,
-			// which will cause fallback to provided ContentType (which is invalid)
+			// Binary content is detected as application/octet-stream, which is not allowed
 			req: UploadFileRequest{
-				File:        bytes.NewReader([]byte{0x4D, 0x5A, 0x90, 0x00}), // MZ header (executable)
-				ContentType: "application/x-msdownload",
-				Size:        4,
+				File: bytes.NewReader([]byte{0x4D, 0x5A, 0x90, 0x00}), // MZ header (executable)
+				Size: 4,
 			},
-			setupMocks: func(storage *mocks.MockIFileStorage) {
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_FILE_TYPE", "file type not allowed", http.StatusBadRequest, nil),
+		},
+		{
+			name: "configured size limit",
+			req: UploadFileRequest{
+				File: strings.NewReader("test content"),
+				Size: 12,
+			},
+			config: UploadConfig{MaxSize: 8},
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("FILE_TOO_LARGE", "file size exceeds maximum allowed size of 8 bytes", http.StatusBadRequest, nil),
+		},
+		{
+			name: "configured content types",
+			req: UploadFileRequest{
+				File: strings.NewReader("test content"),
+				Size: 12,
+			},
+			config: UploadConfig{AllowedMimeTypes: []string{"application/pdf"}},
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedError: apperrors.NewAppError("INVALID_FILE_TYPE", "file type not allowed", http.StatusBadRequest, nil),
 		},
+		{
+			name: "stream exceeds size limit",
+			req: UploadFileRequest{
+				File: strings.NewReader(strings.Repeat("a", 10*1024*1024+1)),
+				Size: 4, // declared size is not trusted
+			},
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				storage.On("Upload", mock.Anything, mock.Anything, "text/plain; charset=utf-8", mock.Anything).
+					Return(func(ctx context.Context, file io.Reader, contentType string, filename string) (string, error) {
+						_, err := io.Copy(io.Discard, file)
+						return "", err
+					})
+			},
+			expectedError: apperrors.NewAppError("FILE_TOO_LARGE", "file size exceeds maximum allowed size of 10485760 bytes", http.StatusBadRequest, nil),
+		},
+		{
+			name: "stream at size limit",
+			req: UploadFileRequest{
+				File: strings.NewReader(strings.Repeat("a", 10*1024*1024)),
+			},
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				storage.On("Upload", mock.Anything, mock.Anything, "text/plain; charset=utf-8", mock.Anything).
+					Return(func(ctx context.Context, file io.Reader, contentType string, filename string) (string, error) {
+						n, err := io.Copy(io.Discard, file)
+						if err != nil || n != 10*1024*1024 {
+							return "", errors.New("unexpected body")
+						}
+						return fileID, nil
+					})
+				fileRepo.On("Create", mock.Anything, mock.MatchedBy(func(file *domain.File) bool {
+					return file.Size == 10*1024*1024
+				})).Return(nil)
+			},
+			expectedError: nil,
+		},
 		{
 			name: "storage error",
 			req: UploadFileRequest{
-				File:        strings.NewReader("test"),
-				ContentType: "text/plain",
-				Size:        4,
+				File: strings.NewReader("test"),
+				Size: 4,
 			},
-			setupMocks: func(storage *mocks.MockIFileStorage) {
-				storage.On("Upload", mock.Anything, mock.Anything, "text/plain").Return("", errors.New("storage error"))
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				storage.On("Upload", mock.Anything, mock.Anything, "text/plain; charset=utf-8", mock.Anything).Return("", errors.New("storage error"))
 			},
 			expectedError: errors.New("storage error"),
 		},
+		{
+			name: "metadata error removes stored object",
+			req: UploadFileRequest{
+				File: strings.NewReader("test"),
+			},
+			setupMocks: func(storage *mocks.MockIFileStorage, fileRepo *mocks.MockIFileRepository) {
+				storage.On("Upload", mock.Anything, mock.Anything, "text/plain; charset=utf-8", mock.Anything).Return(fileID, nil)
+				fileRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.File")).Return(errors.New("db error"))
+				storage.On("Delete", mock.Anything, fileID).Return(nil)
+			},
+			expectedError: errors.New("db error"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			storage := mocks.NewMockIFileStorage(t)
-			tt.setupMocks(storage)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(storage, fileRepo)
 
-			uc := NewFileUseCase(storage)
-			fileID, err := uc.UploadFile(context.Background(), tt.req)
+			uc := NewFileUseCase(storage, fileRepo, tt.config)
+			ctx := contextWithRole(domain.RoleEditor)
+			if tt.principal != nil {
+				ctx = domain.WithPrincipal(ctx, tt.principal)
+			}
+			fileID, err := uc.UploadFile(ctx, tt.req)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -124,3 +230,80 @@ func TestUploadFile(t *testing.T) {
 	}
 }
 
+func TestDownloadFile(t *testing.T) {
+	fileID := uuid.New().String()
+	tests := []struct {
+		name          string
+		req           DownloadFileRequest
+		setupMocks    func(*mocks.MockIFileStorage)
+		expectedError error
+	}{
+		{
+			name: "successful download with range",
+			req: DownloadFileRequest{
+				FileID:      fileID,
+				Range:       "bytes=0-3",
+				IfNoneMatch: `"etag"`,
+			},
+			setupMocks: func(storage *mocks.MockIFileStorage) {
+				storage.On("Open", mock.Anything, fileID, client.OpenFileOptions{Range: "bytes=0-3", IfNoneMatch: `"etag"`}).
+					Return(&client.FileObject{Body: io.NopCloser(strings.NewReader("test"))}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "invalid file id",
+			req: DownloadFileRequest{
+				FileID: "not-a-uuid",
+			},
+			setupMocks: func(storage *mocks.MockIFileStorage) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_ID", "invalid file id", http.StatusBadRequest, nil),
+		},
+		{
+			name: "file not found",
+			req: DownloadFileRequest{
+				FileID: fileID,
+			},
+			setupMocks: func(storage *mocks.MockIFileStorage) {
+				storage.On("Open", mock.Anything, fileID, client.OpenFileOptions{}).
+					Return(nil, apperrors.NewAppError("FILE_NOT_FOUND", "file not found", http.StatusNotFound, nil))
+			},
+			expectedError: apperrors.NewAppError("FILE_NOT_FOUND", "file not found", http.StatusNotFound, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := mocks.NewMockIFileStorage(t)
+			tt.setupMocks(storage)
+
+			uc := NewFileUseCase(storage, mocks.NewMockIFileRepository(t), UploadConfig{})
+			file, err := uc.DownloadFile(contextWithRole(domain.RoleEditor), tt.req)
+
+			if tt.expectedError != nil {
+				appErr, _ := apperrors.AsAppError(tt.expectedError)
+				actualErr, ok := apperrors.AsAppError(err)
+				assert.True(t, ok, "expected AppError")
+				assert.Equal(t, appErr.Code, actualErr.Code)
+				assert.Nil(t, file)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, file.Body)
+			}
+		})
+	}
+}
+
+func TestGetFileMetadata(t *testing.T) {
+	fileID := uuid.New()
+
+	fileRepo := mocks.NewMockIFileRepository(t)
+	fileRepo.On("GetByID", mock.Anything, fileID.String()).Return(&domain.File{ID: fileID, OriginalFilename: "notes.txt"}, nil)
+
+	uc := NewFileUseCase(mocks.NewMockIFileStorage(t), fileRepo, UploadConfig{})
+	file, err := uc.GetFileMetadata(contextWithRole(domain.RoleEditor), fileID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "notes.txt", file.OriginalFilename)
+}
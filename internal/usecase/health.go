@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+)
+
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheckResult is the outcome of probing one dependency
+type HealthCheckResult struct {
+	Name    string
+	Latency time.Duration
+	// Err is nil when the dependency is healthy
+	Err error
+}
+
+// HealthReport is the outcome of probing every dependency
+type HealthReport struct {
+	Healthy bool
+	Checks  []HealthCheckResult
+}
+
+// HealthUseCase probes the dependencies the service needs to serve requests
+type HealthUseCase struct {
+	checkers map[string]client.IHealthChecker
+	timeout  time.Duration
+}
+
+// NewHealthUseCase creates a new HealthUseCase running each named checker with the
+// given timeout
+func NewHealthUseCase(checkers map[string]client.IHealthChecker, timeout time.Duration) *HealthUseCase {
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	return &HealthUseCase{
+		checkers: checkers,
+		timeout:  timeout,
+	}
+}
+
+// CheckReadiness runs all checks in parallel, each bounded by the timeout, and
+// reports them sorted by name. The service is healthy when every check passes.
+func (uc *HealthUseCase) CheckReadiness(ctx context.Context) *HealthReport {
+	results := make([]HealthCheckResult, 0, len(uc.checkers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range uc.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := uc.check(ctx, name, checker)
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	report := &HealthReport{Healthy: true, Checks: results}
+	for _, result := range results {
+		if result.Err != nil {
+			report.Healthy = false
+		}
+	}
+	return report
+}
+
+// check runs a single checker under the timeout and measures its latency
+func (uc *HealthUseCase) check(ctx context.Context, name string, checker client.IHealthChecker) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+	start := time.Now()
+	err := checker.Check(ctx)
+	if err == nil && ctx.Err() != nil {
+		// The checker ignored its deadline
+		err = ctx.Err()
+	}
+	return HealthCheckResult{
+		Name:    name,
+		Latency: time.Since(start),
+		Err:     err,
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthUseCase_CheckReadiness(t *testing.T) {
+	errUnavailable := errors.New("connection refused")
+
+	tests := []struct {
+		name            string
+		setupMocks      func(mysql, redis, s3 *mocks.MockIHealthChecker)
+		expectedHealthy bool
+		expectedErrors  map[string]error
+	}{
+		{
+			name: "all healthy",
+			setupMocks: func(mysql, redis, s3 *mocks.MockIHealthChecker) {
+				mysql.On("Check", mock.Anything).Return(nil)
+				redis.On("Check", mock.Anything).Return(nil)
+				s3.On("Check", mock.Anything).Return(nil)
+			},
+			expectedHealthy: true,
+		},
+		{
+			name: "one dependency failing",
+			setupMocks: func(mysql, redis, s3 *mocks.MockIHealthChecker) {
+				mysql.On("Check", mock.Anything).Return(nil)
+				redis.On("Check", mock.Anything).Return(errUnavailable)
+				s3.On("Check", mock.Anything).Return(nil)
+			},
+			expectedErrors: map[string]error{"redis": errUnavailable},
+		},
+		{
+			name: "check times out",
+			setupMocks: func(mysql, redis, s3 *mocks.MockIHealthChecker) {
+				mysql.On("Check", mock.Anything).Return(nil)
+				redis.On("Check", mock.Anything).Return(nil)
+				// The checker ignores its context, the deadline is still reported
+				s3.On("Check", mock.Anything).Return(nil).
+					Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() })
+			},
+			expectedErrors: map[string]error{"s3": context.DeadlineExceeded},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mysql := mocks.NewMockIHealthChecker(t)
+			redis := mocks.NewMockIHealthChecker(t)
+			s3 := mocks.NewMockIHealthChecker(t)
+			tt.setupMocks(mysql, redis, s3)
+
+			uc := NewHealthUseCase(map[string]client.IHealthChecker{
+				"s3":    s3,
+				"mysql": mysql,
+				"redis": redis,
+			}, 50*time.Millisecond)
+
+			report := uc.CheckReadiness(context.Background())
+
+			assert.Equal(t, tt.expectedHealthy, report.Healthy)
+			require.Len(t, report.Checks, 3)
+			for i, name := range []string{"mysql", "redis", "s3"} {
+				assert.Equal(t, name, report.Checks[i].Name)
+				assert.ErrorIs(t, report.Checks[i].Err, tt.expectedErrors[name])
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	outboxBatchSize    = 100
+	outboxPollInterval = time.Second
+	outboxBaseBackoff  = time.Second
+	outboxMaxBackoff   = 5 * time.Minute
+	// outboxMaxAttempts is how often a message is published before it is given up,
+	// roughly an hour with the backoff above
+	outboxMaxAttempts = 20
+	// outboxLease is how long a fetched batch stays hidden from other relays while it
+	// is published; messages of a relay that died become due again once it expires
+	outboxLease = time.Minute
+)
+
+// OutboxRelay drains the transactional outbox into the stream publisher.
+// Messages are deleted only after a successful publish, so delivery is at-least-once.
+// Messages of the same aggregate are published one at a time in the order they were
+// enqueued; a message that keeps failing is given up after outboxMaxAttempts.
+type OutboxRelay struct {
+	outboxRepo repository.IOutboxRepository
+	txManager  repository.ITransactionManager
+	publisher  client.IStreamPublisher
+}
+
+// NewOutboxRelay creates a new OutboxRelay
+func NewOutboxRelay(outboxRepo repository.IOutboxRepository, txManager repository.ITransactionManager, publisher client.IStreamPublisher) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		txManager:  txManager,
+		publisher:  publisher,
+	}
+}
+
+// Run relays pending messages until ctx is cancelled
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.Flush(ctx); err != nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush relays batches of due messages until a batch publishes nothing. A batch holds
+// one message per aggregate, so it also drains aggregates with several pending messages.
+func (r *OutboxRelay) Flush(ctx context.Context) error {
+	for {
+		relayed, err := r.RelayBatch(ctx)
+		if err != nil || relayed == 0 {
+			return err
+		}
+	}
+}
+
+// RelayBatch publishes one batch of due messages and returns how many were published.
+// The batch is leased in a short transaction and published after it commits, so no row
+// locks are held during network calls.
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	var messages []*domain.OutboxMessage
+	err := r.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		messages, err = r.outboxRepo.FetchPending(ctx, outboxBatchSize)
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+		ids := make([]string, 0, len(messages))
+		for _, message := range messages {
+			ids = append(ids, message.ID.String())
+		}
+		return r.outboxRepo.Lease(ctx, ids, time.Now().Add(outboxLease))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	relayed := 0
+	for _, message := range messages {
+		id := message.ID.String()
+		if err := r.publish(ctx, message); err != nil {
+			if message.Attempts+1 >= outboxMaxAttempts {
+				log.Printf("outbox relay: giving up on message %s after %d attempts: %v", id, message.Attempts+1, err)
+				if err := r.outboxRepo.MarkExhausted(ctx, id, err.Error()); err != nil {
+					return relayed, err
+				}
+				continue
+			}
+			nextAttemptAt := time.Now().Add(outboxBackoff(message.Attempts + 1))
+			if err := r.outboxRepo.MarkFailed(ctx, id, err.Error(), nextAttemptAt); err != nil {
+				return relayed, err
+			}
+			continue
+		}
+		if err := r.outboxRepo.Delete(ctx, id); err != nil {
+			return relayed, err
+		}
+		relayed++
+	}
+	return relayed, nil
+}
+
+// publish decodes an outbox payload and hands it to the stream publisher. The send span
+// continues the trace of the request that enqueued the message, and its context
+// replaces the payload's so consumers continue from it.
+func (r *OutboxRelay) publish(ctx context.Context, message *domain.OutboxMessage) (err error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(message.Payload), &data); err != nil {
+		return err
+	}
+	traceParent, _ := data["traceparent"].(string)
+	traceState, _ := data["tracestate"].(string)
+	ctx, span := tracer.Start(tracing.Extract(ctx, traceParent, traceState), "send "+message.Stream,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingDestinationName(message.Stream), semconv.MessagingOperationTypeSend),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if traceParent, traceState := tracing.Inject(ctx); traceParent != "" {
+		data["traceparent"] = traceParent
+		delete(data, "tracestate")
+		if traceState != "" {
+			data["tracestate"] = traceState
+		}
+	}
+	return r.publisher.Publish(ctx, message.Stream, data)
+}
+
+// outboxBackoff returns the exponential delay before the given attempt, capped at outboxMaxBackoff
+func outboxBackoff(attempt int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return backoff
+}
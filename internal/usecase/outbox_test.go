@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOutboxRelay_RelayBatch(t *testing.T) {
+	message := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"todo-1"}`))
+	exhausted := domain.NewOutboxMessage("todo-items", "todo-1", []byte(`{"id":"todo-1"}`))
+	exhausted.Attempts = outboxMaxAttempts - 1
+	other := domain.NewOutboxMessage("todo-items", "todo-2", []byte(`{"id":"todo-2"}`))
+
+	tests := []struct {
+		name            string
+		setupMocks      func(*mocks.MockIOutboxRepository, *mocks.MockIStreamPublisher)
+		expectedRelayed int
+		expectedError   error
+	}{
+		{
+			name: "published messages are deleted",
+			setupMocks: func(outboxRepo *mocks.MockIOutboxRepository, publisher *mocks.MockIStreamPublisher) {
+				outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{message}, nil)
+				outboxRepo.On("Lease", mock.Anything, []string{message.ID.String()}, mock.Anything).Return(nil)
+				publisher.On("Publish", mock.Anything, "todo-items", map[string]interface{}{"id": "todo-1"}).Return(nil)
+				outboxRepo.On("Delete", mock.Anything, message.ID.String()).Return(nil)
+			},
+			expectedRelayed: 1,
+		},
+		{
+			name: "failed publish is rescheduled",
+			setupMocks: func(outboxRepo *mocks.MockIOutboxRepository, publisher *mocks.MockIStreamPublisher) {
+				outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{message}, nil)
+				outboxRepo.On("Lease", mock.Anything, []string{message.ID.String()}, mock.Anything).Return(nil)
+				publisher.On("Publish", mock.Anything, "todo-items", mock.Anything).Return(errors.New("redis down"))
+				outboxRepo.On("MarkFailed", mock.Anything, message.ID.String(), "redis down", mock.MatchedBy(func(next time.Time) bool {
+					return next.After(time.Now())
+				})).Return(nil)
+			},
+			expectedRelayed: 0,
+		},
+		{
+			name: "failure does not stop other aggregates",
+			setupMocks: func(outboxRepo *mocks.MockIOutboxRepository, publisher *mocks.MockIStreamPublisher) {
+				outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{message, other}, nil)
+				outboxRepo.On("Lease", mock.Anything, []string{message.ID.String(), other.ID.String()}, mock.MatchedBy(func(until time.Time) bool {
+					return until.After(time.Now())
+				})).Return(nil)
+				publisher.On("Publish", mock.Anything, "todo-items", map[string]interface{}{"id": "todo-1"}).Return(errors.New("redis down"))
+				outboxRepo.On("MarkFailed", mock.Anything, message.ID.String(), "redis down", mock.Anything).Return(nil)
+				publisher.On("Publish", mock.Anything, "todo-items", map[string]interface{}{"id": "todo-2"}).Return(nil)
+				outboxRepo.On("Delete", mock.Anything, other.ID.String()).Return(nil)
+			},
+			expectedRelayed: 1,
+		},
+		{
+			name: "message out of attempts is given up",
+			setupMocks: func(outboxRepo *mocks.MockIOutboxRepository, publisher *mocks.MockIStreamPublisher) {
+				outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{exhausted}, nil)
+				outboxRepo.On("Lease", mock.Anything, []string{exhausted.ID.String()}, mock.Anything).Return(nil)
+				publisher.On("Publish", mock.Anything, "todo-items", mock.Anything).Return(errors.New("redis down"))
+				outboxRepo.On("MarkExhausted", mock.Anything, exhausted.ID.String(), "redis down").Return(nil)
+			},
+			expectedRelayed: 0,
+		},
+		{
+			name: "empty batch is not leased",
+			setupMocks: func(outboxRepo *mocks.MockIOutboxRepository, publisher *mocks.MockIStreamPublisher) {
+				outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{}, nil)
+			},
+		},
+		{
+			name: "lease error",
+			setupMocks: func(outboxRepo *mocks.MockIOutboxRepository, publisher *mocks.MockIStreamPublisher) {
+				outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{message}, nil)
+				outboxRepo.On("Lease", mock.Anything, []string{message.ID.String()}, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+		{
+			name: "fetch error",
+			setupMocks: func(outboxRepo *mocks.MockIOutboxRepository, publisher *mocks.MockIStreamPublisher) {
+				outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			publisher := mocks.NewMockIStreamPublisher(t)
+			tt.setupMocks(outboxRepo, publisher)
+
+			relay := NewOutboxRelay(outboxRepo, newTransactionManager(t), publisher)
+			relayed, err := relay.RelayBatch(context.Background())
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedRelayed, relayed)
+		})
+	}
+}
+
+func TestOutboxRelay_PropagatesTraceContext(t *testing.T) {
+	message := domain.NewOutboxMessage("todo-items", "todo-1", []byte(
+		`{"id":"event-1","traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01","payload":{}}`,
+	))
+
+	outboxRepo := mocks.NewMockIOutboxRepository(t)
+	publisher := mocks.NewMockIStreamPublisher(t)
+	outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{message}, nil)
+	outboxRepo.On("Lease", mock.Anything, []string{message.ID.String()}, mock.Anything).Return(nil)
+	publisher.On("Publish", mock.MatchedBy(func(ctx context.Context) bool {
+		// The publisher runs within the trace of the request that enqueued the message
+		return trace.SpanContextFromContext(ctx).TraceID().String() == "4bf92f3577b34da6a3ce929d0e0e4736"
+	}), "todo-items", mock.MatchedBy(func(data map[string]interface{}) bool {
+		traceParent, _ := data["traceparent"].(string)
+		return strings.HasPrefix(traceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-")
+	})).Return(nil)
+	outboxRepo.On("Delete", mock.Anything, message.ID.String()).Return(nil)
+
+	relay := NewOutboxRelay(outboxRepo, newTransactionManager(t), publisher)
+	relayed, err := relay.RelayBatch(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, relayed)
+}
+
+func TestOutboxRelay_Flush(t *testing.T) {
+	fullBatch := make([]*domain.OutboxMessage, outboxBatchSize)
+	for i := range fullBatch {
+		fullBatch[i] = domain.NewOutboxMessage("todo-items", "", []byte(`{}`))
+	}
+	lastMessage := domain.NewOutboxMessage("todo-items", "", []byte(`{}`))
+
+	outboxRepo := mocks.NewMockIOutboxRepository(t)
+	publisher := mocks.NewMockIStreamPublisher(t)
+	outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return(fullBatch, nil).Once()
+	outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{lastMessage}, nil).Once()
+	outboxRepo.On("FetchPending", mock.Anything, outboxBatchSize).Return([]*domain.OutboxMessage{}, nil).Once()
+	outboxRepo.On("Lease", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	publisher.On("Publish", mock.Anything, "todo-items", mock.Anything).Return(nil).Times(outboxBatchSize + 1)
+	outboxRepo.On("Delete", mock.Anything, mock.Anything).Return(nil).Times(outboxBatchSize + 1)
+
+	relay := NewOutboxRelay(outboxRepo, newTransactionManager(t), publisher)
+	assert.NoError(t, relay.Flush(context.Background()))
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, outboxBackoff(1))
+	assert.Equal(t, 2*time.Second, outboxBackoff(2))
+	assert.Equal(t, 8*time.Second, outboxBackoff(4))
+	assert.Equal(t, outboxMaxBackoff, outboxBackoff(30))
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+)
+
+// StreamUseCase handles inspection of the streams the application publishes to
+type StreamUseCase struct {
+	inspector client.IStreamInspector
+	streams   map[string]bool
+}
+
+// NewStreamUseCase creates a new StreamUseCase
+func NewStreamUseCase(inspector client.IStreamInspector) *StreamUseCase {
+	return &StreamUseCase{
+		inspector: inspector,
+		streams: map[string]bool{
+			todoItemsStream: true,
+		},
+	}
+}
+
+// GetStreamInfo returns the length of a stream and the progress of its consumer groups
+func (uc *StreamUseCase) GetStreamInfo(ctx context.Context, stream string) (*client.StreamInfo, error) {
+	if err := Authorize(ctx, domain.PermissionStreamAdmin); err != nil {
+		return nil, err
+	}
+	if !uc.streams[stream] {
+		return nil, apperrors.NewAppError("STREAM_NOT_FOUND", "stream not found", http.StatusNotFound, nil)
+	}
+	return uc.inspector.Info(ctx, stream)
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+	"github.com/ar-agahian/ice-assignment/mocks"
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetStreamInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		stream         string
+		setupMocks     func(*mocks.MockIStreamInspector)
+		expectedLength int64
+		expectedError  error
+	}{
+		{
+			name:   "known stream",
+			stream: "todo-items",
+			setupMocks: func(inspector *mocks.MockIStreamInspector) {
+				inspector.On("Info", mock.Anything, "todo-items").
+					Return(&client.StreamInfo{Stream: "todo-items", Length: 42}, nil)
+			},
+			expectedLength: 42,
+		},
+		{
+			name:   "unknown stream",
+			stream: "other",
+			setupMocks: func(inspector *mocks.MockIStreamInspector) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("STREAM_NOT_FOUND", "stream not found", http.StatusNotFound, nil),
+		},
+		{
+			name:   "inspector error",
+			stream: "todo-items",
+			setupMocks: func(inspector *mocks.MockIStreamInspector) {
+				inspector.On("Info", mock.Anything, "todo-items").Return(nil, errors.New("redis error"))
+			},
+			expectedError: errors.New("redis error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspector := mocks.NewMockIStreamInspector(t)
+			tt.setupMocks(inspector)
+
+			uc := NewStreamUseCase(inspector)
+			info, err := uc.GetStreamInfo(operatorContext(), tt.stream)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if appErr, ok := apperrors.AsAppError(tt.expectedError); ok {
+					actualErr, ok := apperrors.AsAppError(err)
+					assert.True(t, ok, "expected AppError")
+					assert.Equal(t, appErr.Code, actualErr.Code)
+				}
+				assert.Nil(t, info)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedLength, info.Length)
+			}
+		})
+	}
+}
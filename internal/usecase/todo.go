@@ -2,26 +2,47 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/ar-agahian/ice-assignment/internal/domain"
-	"github.com/ar-agahian/ice-assignment/internal/interfaces/client"
 	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
+	"github.com/ar-agahian/ice-assignment/pkg/correlation"
 	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/ar-agahian/ice-assignment/pkg/metrics"
+	"github.com/ar-agahian/ice-assignment/pkg/tracing"
+	"github.com/google/uuid"
+)
+
+const (
+	todoItemsStream  = "todo-items"
+	defaultListLimit = 20
+	maxListLimit     = 100
 )
 
 // TodoUseCase handles todo item business logic
 type TodoUseCase struct {
 	todoRepo   repository.ITodoRepository
-	streamRepo client.IStreamPublisher
+	fileRepo   repository.IFileRepository
+	outboxRepo repository.IOutboxRepository
+	txManager  repository.ITransactionManager
 }
 
 // NewTodoUseCase creates a new TodoUseCase
-func NewTodoUseCase(todoRepo repository.ITodoRepository, streamRepo client.IStreamPublisher) *TodoUseCase {
+func NewTodoUseCase(
+	todoRepo repository.ITodoRepository,
+	fileRepo repository.IFileRepository,
+	outboxRepo repository.IOutboxRepository,
+	txManager repository.ITransactionManager,
+) *TodoUseCase {
 	return &TodoUseCase{
 		todoRepo:   todoRepo,
-		streamRepo: streamRepo,
+		fileRepo:   fileRepo,
+		outboxRepo: outboxRepo,
+		txManager:  txManager,
 	}
 }
 
@@ -32,29 +53,324 @@ type CreateTodoItemRequest struct {
 	FileID      string
 }
 
-// CreateTodoItem creates a new todo item and publishes it to the stream
-func (uc *TodoUseCase) CreateTodoItem(ctx context.Context, req CreateTodoItemRequest) (*domain.TodoItem, error) {
-	if req.Description == "" {
-		return nil, apperrors.NewAppError("INVALID_DESCRIPTION", "description cannot be empty", http.StatusBadRequest, nil)
+// UpdateTodoItemRequest represents the request to update a todo item.
+// Nil fields are left unchanged.
+type UpdateTodoItemRequest struct {
+	Description *string
+	DueDate     *time.Time
+	FileID      *string
+}
+
+// ListTodoItemsRequest represents the request to list todo items
+type ListTodoItemsRequest struct {
+	DueBefore     *time.Time
+	DueAfter      *time.Time
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	HasAttachment *bool
+	SortBy        string
+	Descending    bool
+	Cursor        string
+	Limit         int
+}
+
+// ListTodoItemsResult represents a page of todo items
+type ListTodoItemsResult struct {
+	Items      []*domain.TodoItem
+	NextCursor string
+}
+
+// listCursor is the opaque cursor handed to clients between pages
+type listCursor struct {
+	SortBy     string    `json:"s"`
+	Descending bool      `json:"d"`
+	SortValue  time.Time `json:"v"`
+	ID         uuid.UUID `json:"i"`
+}
+
+// CreateTodoItem creates a new todo item owned by the caller and enqueues it for the
+// stream in the same transaction
+func (uc *TodoUseCase) CreateTodoItem(ctx context.Context, req CreateTodoItemRequest) (_ *domain.TodoItem, err error) {
+	ctx, span := tracer.Start(ctx, "TodoUseCase.CreateTodoItem")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionTodoCreate); err != nil {
+		return nil, err
 	}
-	if len(req.Description) > 500 {
-		return nil, apperrors.NewAppError("INVALID_DESCRIPTION", "description must be at most 500 characters", http.StatusBadRequest, nil)
+	if err := validateDescription(req.Description); err != nil {
+		return nil, err
 	}
-	if req.DueDate.Before(time.Now()) {
-		return nil, apperrors.NewAppError("INVALID_DUE_DATE", "due date must be in the future", http.StatusBadRequest, nil)
+	if err := validateDueDate(req.DueDate); err != nil {
+		return nil, err
+	}
+	if err := uc.validateFileID(ctx, req.FileID); err != nil {
+		return nil, err
 	}
 	todoItem := domain.NewTodoItem(req.Description, req.DueDate, req.FileID)
-	if err := uc.todoRepo.Create(ctx, todoItem); err != nil {
+	todoItem.AssignOwner(domain.PrincipalFromContext(ctx))
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.todoRepo.Create(ctx, todoItem); err != nil {
+			return err
+		}
+		return uc.enqueue(ctx, domain.EventTodoCreated, todoItem.ID.String(), domain.NewTodoCreatedPayload(todoItem))
+	})
+	if err != nil {
 		return nil, err
 	}
-	streamData := map[string]interface{}{
-		"id":          todoItem.ID.String(),
-		"description": todoItem.Description,
-		"dueDate":     todoItem.DueDate.Format(time.RFC3339),
-		"fileId":      todoItem.FileID,
+	metrics.IncTodosCreated()
+	return todoItem, nil
+}
+
+// GetTodoItem retrieves a todo item by its ID
+func (uc *TodoUseCase) GetTodoItem(ctx context.Context, id string) (_ *domain.TodoItem, err error) {
+	ctx, span := tracer.Start(ctx, "TodoUseCase.GetTodoItem")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionTodoRead); err != nil {
+		return nil, err
 	}
-	if err := uc.streamRepo.Publish(ctx, "todo-items", streamData); err != nil {
-		return todoItem, err
+	return uc.todoRepo.GetByID(ctx, id)
+}
+
+// UpdateTodoItem applies the given changes to an existing todo item and publishes its new state
+func (uc *TodoUseCase) UpdateTodoItem(ctx context.Context, id string, req UpdateTodoItemRequest) (_ *domain.TodoItem, err error) {
+	ctx, span := tracer.Start(ctx, "TodoUseCase.UpdateTodoItem")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionTodoUpdate); err != nil {
+		return nil, err
+	}
+	if req.Description != nil {
+		if err := validateDescription(*req.Description); err != nil {
+			return nil, err
+		}
+	}
+	if req.DueDate != nil {
+		if err := validateDueDate(*req.DueDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.FileID != nil {
+		if err := uc.validateFileID(ctx, *req.FileID); err != nil {
+			return nil, err
+		}
+	}
+	var todoItem *domain.TodoItem
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The row stays locked until commit so concurrent updates do not overwrite each other's fields
+		item, err := uc.todoRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if req.Description != nil {
+			item.Description = *req.Description
+		}
+		if req.DueDate != nil {
+			item.DueDate = *req.DueDate
+		}
+		if req.FileID != nil {
+			item.FileID = *req.FileID
+		}
+		todoItem = item
+		if err := uc.todoRepo.Update(ctx, item); err != nil {
+			return err
+		}
+		return uc.enqueue(ctx, domain.EventTodoUpdated, item.ID.String(), domain.NewTodoUpdatedPayload(item))
+	})
+	if err != nil {
+		return nil, err
 	}
 	return todoItem, nil
 }
+
+// DeleteTodoItem deletes a todo item by its ID and publishes the deletion
+func (uc *TodoUseCase) DeleteTodoItem(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "TodoUseCase.DeleteTodoItem")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionTodoDelete); err != nil {
+		return err
+	}
+	return uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.todoRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return uc.enqueue(ctx, domain.EventTodoDeleted, id, domain.TodoDeletedPayload{ID: id})
+	})
+}
+
+// ChangeTodoStatus moves a todo item through its lifecycle and publishes the transition
+func (uc *TodoUseCase) ChangeTodoStatus(ctx context.Context, id string, status domain.TodoStatus) (_ *domain.TodoItem, err error) {
+	ctx, span := tracer.Start(ctx, "TodoUseCase.ChangeTodoStatus")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionTodoUpdate); err != nil {
+		return nil, err
+	}
+	if !status.IsValid() {
+		return nil, apperrors.NewAppError("INVALID_STATUS", "unknown todo status", http.StatusBadRequest, nil)
+	}
+	var todoItem *domain.TodoItem
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The row stays locked until commit so concurrent transitions are checked one after another
+		item, err := uc.todoRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		previousStatus := item.Status
+		if err := item.TransitionTo(status, time.Now()); err != nil {
+			return apperrors.NewAppError(
+				"INVALID_STATUS_TRANSITION",
+				fmt.Sprintf("cannot change status from %s to %s", previousStatus, status),
+				http.StatusConflict,
+				err,
+			)
+		}
+		payload := domain.TodoStatusChangedPayload{
+			ID:             item.ID.String(),
+			PreviousStatus: previousStatus,
+			Status:         item.Status,
+			CompletedAt:    item.CompletedAt,
+		}
+		todoItem = item
+		if err := uc.todoRepo.Update(ctx, item); err != nil {
+			return err
+		}
+		return uc.enqueue(ctx, domain.EventTodoStatusChanged, payload.ID, payload)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todoItem, nil
+}
+
+// enqueue wraps a payload into an event envelope carrying the caller's tenant and trace
+// context and stores it in the outbox for the relay to publish in order with the other events
+// of the todo item identified by todoID
+func (uc *TodoUseCase) enqueue(ctx context.Context, eventType domain.EventType, todoID string, payload interface{}) error {
+	event, err := domain.NewEvent(eventType, payload, correlation.FromContext(ctx), time.Now())
+	if err != nil {
+		return err
+	}
+	event.TenantID = domain.TenantFromContext(ctx)
+	event.TraceParent, event.TraceState = tracing.Inject(ctx)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return uc.outboxRepo.Add(ctx, domain.NewOutboxMessage(todoItemsStream, todoID, data))
+}
+
+// ListTodoItems returns a page of todo items matching the given filters
+func (uc *TodoUseCase) ListTodoItems(ctx context.Context, req ListTodoItemsRequest) (_ *ListTodoItemsResult, err error) {
+	ctx, span := tracer.Start(ctx, "TodoUseCase.ListTodoItems")
+	defer func() { tracing.End(span, err) }()
+	if err := Authorize(ctx, domain.PermissionTodoRead); err != nil {
+		return nil, err
+	}
+	sortBy := repository.SortField(req.SortBy)
+	if sortBy == "" {
+		sortBy = repository.SortByDueDate
+	}
+	if sortBy != repository.SortByDueDate && sortBy != repository.SortByCreatedAt {
+		return nil, apperrors.NewAppError("INVALID_SORT", "sort must be one of dueDate, createdAt", http.StatusBadRequest, nil)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	query := repository.ListQuery{
+		DueBefore:     req.DueBefore,
+		DueAfter:      req.DueAfter,
+		CreatedBefore: req.CreatedBefore,
+		CreatedAfter:  req.CreatedAfter,
+		UpdatedBefore: req.UpdatedBefore,
+		UpdatedAfter:  req.UpdatedAfter,
+		HasAttachment: req.HasAttachment,
+		SortBy:        sortBy,
+		Descending:    req.Descending,
+		Limit:         limit + 1,
+	}
+	if req.Cursor != "" {
+		cursor, err := decodeListCursor(req.Cursor)
+		if err != nil || cursor.SortBy != string(sortBy) || cursor.Descending != req.Descending {
+			return nil, apperrors.NewAppError("INVALID_CURSOR", "invalid pagination cursor", http.StatusBadRequest, err)
+		}
+		query.After = &repository.ListCursor{SortValue: cursor.SortValue, ID: cursor.ID}
+	}
+
+	items, err := uc.todoRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	result := &ListTodoItemsResult{Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		last := result.Items[limit-1]
+		sortValue := last.DueDate
+		if sortBy == repository.SortByCreatedAt {
+			sortValue = last.CreatedAt
+		}
+		result.NextCursor = encodeListCursor(listCursor{
+			SortBy:     string(sortBy),
+			Descending: req.Descending,
+			SortValue:  sortValue,
+			ID:         last.ID,
+		})
+	}
+	return result, nil
+}
+
+// encodeListCursor serializes a cursor into an opaque URL-safe token
+func encodeListCursor(cursor listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeListCursor parses a token produced by encodeListCursor
+func decodeListCursor(token string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// validateFileID checks that a referenced file has actually been uploaded
+func (uc *TodoUseCase) validateFileID(ctx context.Context, fileID string) error {
+	if fileID == "" {
+		return nil
+	}
+	exists, err := uc.fileRepo.Exists(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NewAppError("FILE_NOT_FOUND", "referenced file does not exist", http.StatusUnprocessableEntity, nil)
+	}
+	return nil
+}
+
+// validateDescription checks the description business rules
+func validateDescription(description string) error {
+	if description == "" {
+		return apperrors.NewAppError("INVALID_DESCRIPTION", "description cannot be empty", http.StatusBadRequest, nil)
+	}
+	if len(description) > 500 {
+		return apperrors.NewAppError("INVALID_DESCRIPTION", "description must be at most 500 characters", http.StatusBadRequest, nil)
+	}
+	return nil
+}
+
+// validateDueDate checks that the due date lies in the future
+func validateDueDate(dueDate time.Time) error {
+	if dueDate.Before(time.Now()) {
+		return apperrors.NewAppError("INVALID_DUE_DATE", "due date must be in the future", http.StatusBadRequest, nil)
+	}
+	return nil
+}
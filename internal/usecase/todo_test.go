@@ -2,23 +2,54 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
 	"github.com/ar-agahian/ice-assignment/mocks"
+	"github.com/ar-agahian/ice-assignment/pkg/correlation"
 	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// newTransactionManager returns a transaction manager mock that runs fn directly
+func newTransactionManager(t *testing.T) *mocks.MockITransactionManager {
+	txManager := mocks.NewMockITransactionManager(t)
+	txManager.EXPECT().WithinTransaction(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		Maybe()
+	return txManager
+}
+
+// outboxEvent matches an outbox message carrying a todo-items event of the given type
+func outboxEvent(eventType domain.EventType, match func(*domain.Event) bool) interface{} {
+	return mock.MatchedBy(func(message *domain.OutboxMessage) bool {
+		var event domain.Event
+		if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+			return false
+		}
+		return message.Stream == "todo-items" &&
+			event.Type == eventType &&
+			event.SchemaVersion == domain.EventSchemaVersion &&
+			event.ID != "" &&
+			(match == nil || match(&event))
+	})
+}
+
 func TestCreateTodoItem(t *testing.T) {
 	tests := []struct {
 		name          string
 		req           CreateTodoItemRequest
-		setupMocks    func(*mocks.MockITodoRepository, *mocks.MockIStreamPublisher)
+		setupMocks    func(*mocks.MockITodoRepository, *mocks.MockIFileRepository, *mocks.MockIOutboxRepository)
 		expectedError error
 	}{
 		{
@@ -28,9 +59,14 @@ func TestCreateTodoItem(t *testing.T) {
 				DueDate:     time.Now().Add(24 * time.Hour),
 				FileID:      "file-123",
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				fileRepo.On("Exists", mock.Anything, "file-123").Return(true, nil)
 				todoRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TodoItem")).Return(nil)
-				streamRepo.On("Publish", mock.Anything, "todo-items", mock.Anything).Return(nil)
+				outboxRepo.On("Add", mock.Anything, outboxEvent(domain.EventTodoCreated, func(event *domain.Event) bool {
+					var payload domain.TodoCreatedPayload
+					return event.DecodePayload(&payload) == nil &&
+						payload.Description == "Test todo" && payload.FileID == "file-123" && payload.Status == domain.TodoStatusOpen
+				})).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -40,7 +76,7 @@ func TestCreateTodoItem(t *testing.T) {
 				Description: "",
 				DueDate:     time.Now().Add(24 * time.Hour),
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedError: apperrors.NewAppError("INVALID_DESCRIPTION", "description cannot be empty", http.StatusBadRequest, nil),
@@ -51,7 +87,7 @@ func TestCreateTodoItem(t *testing.T) {
 				Description: strings.Repeat("a", 501),
 				DueDate:     time.Now().Add(24 * time.Hour),
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedError: apperrors.NewAppError("INVALID_DESCRIPTION", "description must be at most 500 characters", http.StatusBadRequest, nil),
@@ -62,44 +98,57 @@ func TestCreateTodoItem(t *testing.T) {
 				Description: "Test todo",
 				DueDate:     time.Now().Add(-24 * time.Hour),
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
 				// No mocks needed, validation fails early
 			},
 			expectedError: apperrors.NewAppError("INVALID_DUE_DATE", "due date must be in the future", http.StatusBadRequest, nil),
 		},
+		{
+			name: "unknown file",
+			req: CreateTodoItemRequest{
+				Description: "Test todo",
+				DueDate:     time.Now().Add(24 * time.Hour),
+				FileID:      "file-404",
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				fileRepo.On("Exists", mock.Anything, "file-404").Return(false, nil)
+			},
+			expectedError: apperrors.NewAppError("FILE_NOT_FOUND", "referenced file does not exist", http.StatusUnprocessableEntity, nil),
+		},
 		{
 			name: "database error",
 			req: CreateTodoItemRequest{
 				Description: "Test todo",
 				DueDate:     time.Now().Add(24 * time.Hour),
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
 				todoRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TodoItem")).Return(errors.New("db error"))
 			},
 			expectedError: errors.New("db error"),
 		},
 		{
-			name: "stream publish error",
+			name: "outbox error",
 			req: CreateTodoItemRequest{
 				Description: "Test todo",
 				DueDate:     time.Now().Add(24 * time.Hour),
 			},
-			setupMocks: func(todoRepo *mocks.MockITodoRepository, streamRepo *mocks.MockIStreamPublisher) {
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
 				todoRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TodoItem")).Return(nil)
-				streamRepo.On("Publish", mock.Anything, "todo-items", mock.Anything).Return(errors.New("stream error"))
+				outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(errors.New("outbox error"))
 			},
-			expectedError: errors.New("stream error"),
+			expectedError: errors.New("outbox error"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			todoRepo := mocks.NewMockITodoRepository(t)
-			streamRepo := mocks.NewMockIStreamPublisher(t)
-			tt.setupMocks(todoRepo, streamRepo)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo, fileRepo, outboxRepo)
 
-			uc := NewTodoUseCase(todoRepo, streamRepo)
-			result, err := uc.CreateTodoItem(context.Background(), tt.req)
+			uc := NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t))
+			result, err := uc.CreateTodoItem(contextWithRole(domain.RoleEditor), tt.req)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -110,18 +159,376 @@ func TestCreateTodoItem(t *testing.T) {
 				} else {
 					assert.NotNil(t, err)
 				}
-				if tt.name == "stream publish error" {
-					// Stream errors don't fail the operation, item is still returned
-					assert.NotNil(t, result)
-				} else {
-					assert.Nil(t, result)
-				}
+				// The todo row and its outbox message are written together, so no item is returned
+				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, tt.req.Description, result.Description)
 				todoRepo.AssertExpectations(t)
-				streamRepo.AssertExpectations(t)
+				outboxRepo.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestCreateTodoItem_AssignsOwner(t *testing.T) {
+	todoRepo := mocks.NewMockITodoRepository(t)
+	outboxRepo := mocks.NewMockIOutboxRepository(t)
+	todoRepo.On("Create", mock.Anything, mock.MatchedBy(func(item *domain.TodoItem) bool {
+		return item.TenantID == "acme" && item.OwnerID == "user-1"
+	})).Return(nil)
+	outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(nil)
+
+	uc := NewTodoUseCase(todoRepo, mocks.NewMockIFileRepository(t), outboxRepo, newTransactionManager(t))
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{ID: "user-1", Type: domain.PrincipalUser, TenantID: "acme", Role: domain.RoleEditor})
+	result, err := uc.CreateTodoItem(ctx, CreateTodoItemRequest{
+		Description: "Test todo",
+		DueDate:     time.Now().Add(24 * time.Hour),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", result.TenantID)
+	assert.Equal(t, "user-1", result.OwnerID)
+}
+
+func TestGetTodoItem(t *testing.T) {
+	todoID := uuid.New()
+	tests := []struct {
+		name          string
+		id            string
+		setupMocks    func(*mocks.MockITodoRepository)
+		expectedError error
+	}{
+		{
+			name: "found",
+			id:   todoID.String(),
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				todoRepo.On("GetByID", mock.Anything, todoID.String()).Return(&domain.TodoItem{ID: todoID}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "not found",
+			id:   todoID.String(),
+			setupMocks: func(todoRepo *mocks.MockITodoRepository) {
+				todoRepo.On("GetByID", mock.Anything, todoID.String()).Return(nil, apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil))
+			},
+			expectedError: apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo)
+
+			uc := NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t))
+			result, err := uc.GetTodoItem(contextWithRole(domain.RoleEditor), tt.id)
+
+			if tt.expectedError != nil {
+				appErr, _ := apperrors.AsAppError(tt.expectedError)
+				actualErr, ok := apperrors.AsAppError(err)
+				assert.True(t, ok, "expected AppError")
+				assert.Equal(t, appErr.Code, actualErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, todoID, result.ID)
+			}
+		})
+	}
+}
+
+func TestUpdateTodoItem(t *testing.T) {
+	todoID := uuid.New()
+	newDescription := "Updated todo"
+	emptyDescription := ""
+	pastDueDate := time.Now().Add(-24 * time.Hour)
+	newFileID := uuid.New().String()
+
+	tests := []struct {
+		name          string
+		req           UpdateTodoItemRequest
+		setupMocks    func(*mocks.MockITodoRepository, *mocks.MockIFileRepository, *mocks.MockIOutboxRepository)
+		expectedError error
+	}{
+		{
+			name: "successful partial update",
+			req: UpdateTodoItemRequest{
+				Description: &newDescription,
+				FileID:      &newFileID,
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				fileRepo.On("Exists", mock.Anything, newFileID).Return(true, nil)
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(&domain.TodoItem{ID: todoID, Description: "Test todo"}, nil)
+				todoRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *domain.TodoItem) bool {
+					return item.Description == newDescription && item.FileID == newFileID
+				})).Return(nil)
+				outboxRepo.On("Add", mock.Anything, outboxEvent(domain.EventTodoUpdated, func(event *domain.Event) bool {
+					var payload domain.TodoUpdatedPayload
+					return event.DecodePayload(&payload) == nil &&
+						payload.ID == todoID.String() && payload.Description == newDescription && payload.FileID == newFileID
+				})).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "empty description",
+			req: UpdateTodoItemRequest{
+				Description: &emptyDescription,
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_DESCRIPTION", "description cannot be empty", http.StatusBadRequest, nil),
+		},
+		{
+			name: "past due date",
+			req: UpdateTodoItemRequest{
+				DueDate: &pastDueDate,
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_DUE_DATE", "due date must be in the future", http.StatusBadRequest, nil),
+		},
+		{
+			name: "unknown file",
+			req: UpdateTodoItemRequest{
+				FileID: &newFileID,
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				fileRepo.On("Exists", mock.Anything, newFileID).Return(false, nil)
+			},
+			expectedError: apperrors.NewAppError("FILE_NOT_FOUND", "referenced file does not exist", http.StatusUnprocessableEntity, nil),
+		},
+		{
+			name: "not found",
+			req: UpdateTodoItemRequest{
+				Description: &newDescription,
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(nil, apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil))
+			},
+			expectedError: apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil),
+		},
+		{
+			name: "database error",
+			req: UpdateTodoItemRequest{
+				Description: &newDescription,
+			},
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, fileRepo *mocks.MockIFileRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(&domain.TodoItem{ID: todoID}, nil)
+				todoRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.TodoItem")).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			tt.setupMocks(todoRepo, fileRepo, outboxRepo)
+
+			uc := NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t))
+			result, err := uc.UpdateTodoItem(contextWithRole(domain.RoleEditor), todoID.String(), tt.req)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if appErr, ok := apperrors.AsAppError(tt.expectedError); ok {
+					actualErr, ok := apperrors.AsAppError(err)
+					assert.True(t, ok, "expected AppError")
+					assert.Equal(t, appErr.Code, actualErr.Code)
+				}
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, newDescription, result.Description)
+			}
+		})
+	}
+}
+
+func TestDeleteTodoItem(t *testing.T) {
+	todoID := uuid.New()
+
+	todoRepo := mocks.NewMockITodoRepository(t)
+	outboxRepo := mocks.NewMockIOutboxRepository(t)
+	fileRepo := mocks.NewMockIFileRepository(t)
+	todoRepo.On("Delete", mock.Anything, todoID.String()).Return(nil)
+	outboxRepo.On("Add", mock.Anything, outboxEvent(domain.EventTodoDeleted, func(event *domain.Event) bool {
+		var payload domain.TodoDeletedPayload
+		return event.DecodePayload(&payload) == nil &&
+			payload.ID == todoID.String() && event.CorrelationID == "correlation-123" && event.TenantID == domain.DefaultTenantID
+	})).Return(nil)
+
+	uc := NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t))
+	err := uc.DeleteTodoItem(correlation.WithID(contextWithRole(domain.RoleEditor), "correlation-123"), todoID.String())
+	assert.NoError(t, err)
+}
+
+func TestListTodoItems(t *testing.T) {
+	now := time.Now()
+	items := []*domain.TodoItem{
+		{ID: uuid.New(), DueDate: now.Add(1 * time.Hour)},
+		{ID: uuid.New(), DueDate: now.Add(2 * time.Hour)},
+		{ID: uuid.New(), DueDate: now.Add(3 * time.Hour)},
+	}
+
+	t.Run("returns next cursor when more items exist", func(t *testing.T) {
+		todoRepo := mocks.NewMockITodoRepository(t)
+		outboxRepo := mocks.NewMockIOutboxRepository(t)
+		fileRepo := mocks.NewMockIFileRepository(t)
+		todoRepo.On("List", mock.Anything, mock.MatchedBy(func(q repository.ListQuery) bool {
+			return q.Limit == 3 && q.SortBy == repository.SortByDueDate && q.After == nil
+		})).Return(items, nil)
+
+		uc := NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t))
+		result, err := uc.ListTodoItems(contextWithRole(domain.RoleEditor), ListTodoItemsRequest{Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 2)
+		assert.NotEmpty(t, result.NextCursor)
+
+		todoRepo.On("List", mock.Anything, mock.MatchedBy(func(q repository.ListQuery) bool {
+			return q.After != nil && q.After.ID == items[1].ID && q.After.SortValue.Equal(items[1].DueDate)
+		})).Return(items[2:], nil)
+
+		next, err := uc.ListTodoItems(contextWithRole(domain.RoleEditor), ListTodoItemsRequest{Limit: 2, Cursor: result.NextCursor})
+		assert.NoError(t, err)
+		assert.Len(t, next.Items, 1)
+		assert.Empty(t, next.NextCursor)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		uc := NewTodoUseCase(mocks.NewMockITodoRepository(t), mocks.NewMockIFileRepository(t), mocks.NewMockIOutboxRepository(t), newTransactionManager(t))
+		_, err := uc.ListTodoItems(contextWithRole(domain.RoleEditor), ListTodoItemsRequest{SortBy: "description"})
+		appErr, ok := apperrors.AsAppError(err)
+		assert.True(t, ok, "expected AppError")
+		assert.Equal(t, "INVALID_SORT", appErr.Code)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		uc := NewTodoUseCase(mocks.NewMockITodoRepository(t), mocks.NewMockIFileRepository(t), mocks.NewMockIOutboxRepository(t), newTransactionManager(t))
+		_, err := uc.ListTodoItems(contextWithRole(domain.RoleEditor), ListTodoItemsRequest{Cursor: "not-a-cursor"})
+		appErr, ok := apperrors.AsAppError(err)
+		assert.True(t, ok, "expected AppError")
+		assert.Equal(t, "INVALID_CURSOR", appErr.Code)
+	})
+
+	t.Run("cursor from a different sort order", func(t *testing.T) {
+		uc := NewTodoUseCase(mocks.NewMockITodoRepository(t), mocks.NewMockIFileRepository(t), mocks.NewMockIOutboxRepository(t), newTransactionManager(t))
+		cursor := encodeListCursor(listCursor{SortBy: "createdAt", SortValue: now, ID: uuid.New()})
+		_, err := uc.ListTodoItems(contextWithRole(domain.RoleEditor), ListTodoItemsRequest{Cursor: cursor})
+		appErr, ok := apperrors.AsAppError(err)
+		assert.True(t, ok, "expected AppError")
+		assert.Equal(t, "INVALID_CURSOR", appErr.Code)
+	})
+}
+
+func TestChangeTodoStatus(t *testing.T) {
+	todoID := uuid.New()
+	completedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		current       *domain.TodoItem
+		status        domain.TodoStatus
+		setupMocks    func(*mocks.MockITodoRepository, *mocks.MockIOutboxRepository)
+		expectedError error
+	}{
+		{
+			name:    "complete open item",
+			current: &domain.TodoItem{ID: todoID, Status: domain.TodoStatusOpen},
+			status:  domain.TodoStatusDone,
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *domain.TodoItem) bool {
+					return item.Status == domain.TodoStatusDone && item.CompletedAt != nil
+				})).Return(nil)
+				outboxRepo.On("Add", mock.Anything, outboxEvent(domain.EventTodoStatusChanged, func(event *domain.Event) bool {
+					var payload domain.TodoStatusChangedPayload
+					return event.DecodePayload(&payload) == nil &&
+						payload.PreviousStatus == domain.TodoStatusOpen && payload.Status == domain.TodoStatusDone && payload.CompletedAt != nil
+				})).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "reopen done item",
+			current: &domain.TodoItem{ID: todoID, Status: domain.TodoStatusDone, CompletedAt: &completedAt},
+			status:  domain.TodoStatusOpen,
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *domain.TodoItem) bool {
+					return item.Status == domain.TodoStatusOpen && item.CompletedAt == nil
+				})).Return(nil)
+				outboxRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.OutboxMessage")).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "complete cancelled item",
+			current: &domain.TodoItem{ID: todoID, Status: domain.TodoStatusCancelled},
+			status:  domain.TodoStatusDone,
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				// No update, transition is rejected
+			},
+			expectedError: apperrors.NewAppError("INVALID_STATUS_TRANSITION", "cannot change status from cancelled to done", http.StatusConflict, nil),
+		},
+		{
+			name:    "reopen open item",
+			current: &domain.TodoItem{ID: todoID, Status: domain.TodoStatusOpen},
+			status:  domain.TodoStatusOpen,
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				// No update, transition is rejected
+			},
+			expectedError: apperrors.NewAppError("INVALID_STATUS_TRANSITION", "cannot change status from open to open", http.StatusConflict, nil),
+		},
+		{
+			name:    "not found",
+			current: nil,
+			status:  domain.TodoStatusDone,
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(nil, apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil))
+			},
+			expectedError: apperrors.NewAppError("TODO_NOT_FOUND", "todo item not found", http.StatusNotFound, nil),
+		},
+		{
+			name:    "unknown status",
+			current: nil,
+			status:  domain.TodoStatus("archived"),
+			setupMocks: func(todoRepo *mocks.MockITodoRepository, outboxRepo *mocks.MockIOutboxRepository) {
+				// No mocks needed, validation fails early
+			},
+			expectedError: apperrors.NewAppError("INVALID_STATUS", "unknown todo status", http.StatusBadRequest, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := mocks.NewMockITodoRepository(t)
+			outboxRepo := mocks.NewMockIOutboxRepository(t)
+			fileRepo := mocks.NewMockIFileRepository(t)
+			if tt.current != nil {
+				todoRepo.On("GetByIDForUpdate", mock.Anything, todoID.String()).Return(tt.current, nil)
+			}
+			tt.setupMocks(todoRepo, outboxRepo)
+
+			uc := NewTodoUseCase(todoRepo, fileRepo, outboxRepo, newTransactionManager(t))
+			result, err := uc.ChangeTodoStatus(contextWithRole(domain.RoleEditor), todoID.String(), tt.status)
+
+			if tt.expectedError != nil {
+				appErr, _ := apperrors.AsAppError(tt.expectedError)
+				actualErr, ok := apperrors.AsAppError(err)
+				assert.True(t, ok, "expected AppError")
+				assert.Equal(t, appErr.Code, actualErr.Code)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.status, result.Status)
 			}
 		})
 	}
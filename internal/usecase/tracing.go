@@ -0,0 +1,8 @@
+package usecase
+
+import (
+	"go.opentelemetry.io/otel"
+)
+
+// tracer starts the spans of the use case methods
+var tracer = otel.Tracer("github.com/ar-agahian/ice-assignment/internal/usecase")
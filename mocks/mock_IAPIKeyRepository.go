@@ -0,0 +1,251 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/ar-agahian/ice-assignment/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockIAPIKeyRepository is an autogenerated mock type for the IAPIKeyRepository type
+type MockIAPIKeyRepository struct {
+	mock.Mock
+}
+
+type MockIAPIKeyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIAPIKeyRepository) EXPECT() *MockIAPIKeyRepository_Expecter {
+	return &MockIAPIKeyRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, key
+func (_m *MockIAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.APIKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIAPIKeyRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIAPIKeyRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key *domain.APIKey
+func (_e *MockIAPIKeyRepository_Expecter) Create(ctx interface{}, key interface{}) *MockIAPIKeyRepository_Create_Call {
+	return &MockIAPIKeyRepository_Create_Call{Call: _e.mock.On("Create", ctx, key)}
+}
+
+func (_c *MockIAPIKeyRepository_Create_Call) Run(run func(ctx context.Context, key *domain.APIKey)) *MockIAPIKeyRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.APIKey))
+	})
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_Create_Call) Return(_a0 error) *MockIAPIKeyRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_Create_Call) RunAndReturn(run func(context.Context, *domain.APIKey) error) *MockIAPIKeyRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByHash provides a mock function with given fields: ctx, keyHash
+func (_m *MockIAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByHash")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.APIKey, error)); ok {
+		return rf(ctx, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.APIKey); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIAPIKeyRepository_GetByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByHash'
+type MockIAPIKeyRepository_GetByHash_Call struct {
+	*mock.Call
+}
+
+// GetByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyHash string
+func (_e *MockIAPIKeyRepository_Expecter) GetByHash(ctx interface{}, keyHash interface{}) *MockIAPIKeyRepository_GetByHash_Call {
+	return &MockIAPIKeyRepository_GetByHash_Call{Call: _e.mock.On("GetByHash", ctx, keyHash)}
+}
+
+func (_c *MockIAPIKeyRepository_GetByHash_Call) Run(run func(ctx context.Context, keyHash string)) *MockIAPIKeyRepository_GetByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_GetByHash_Call) Return(_a0 *domain.APIKey, _a1 error) *MockIAPIKeyRepository_GetByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_GetByHash_Call) RunAndReturn(run func(context.Context, string) (*domain.APIKey, error)) *MockIAPIKeyRepository_GetByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *MockIAPIKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIAPIKeyRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockIAPIKeyRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockIAPIKeyRepository_Expecter) List(ctx interface{}) *MockIAPIKeyRepository_List_Call {
+	return &MockIAPIKeyRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *MockIAPIKeyRepository_List_Call) Run(run func(ctx context.Context)) *MockIAPIKeyRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_List_Call) Return(_a0 []*domain.APIKey, _a1 error) *MockIAPIKeyRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_List_Call) RunAndReturn(run func(context.Context) ([]*domain.APIKey, error)) *MockIAPIKeyRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function with given fields: ctx, id, revokedAt
+func (_m *MockIAPIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	ret := _m.Called(ctx, id, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIAPIKeyRepository_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type MockIAPIKeyRepository_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - revokedAt time.Time
+func (_e *MockIAPIKeyRepository_Expecter) Revoke(ctx interface{}, id interface{}, revokedAt interface{}) *MockIAPIKeyRepository_Revoke_Call {
+	return &MockIAPIKeyRepository_Revoke_Call{Call: _e.mock.On("Revoke", ctx, id, revokedAt)}
+}
+
+func (_c *MockIAPIKeyRepository_Revoke_Call) Run(run func(ctx context.Context, id string, revokedAt time.Time)) *MockIAPIKeyRepository_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_Revoke_Call) Return(_a0 error) *MockIAPIKeyRepository_Revoke_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIAPIKeyRepository_Revoke_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *MockIAPIKeyRepository_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIAPIKeyRepository creates a new instance of MockIAPIKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIAPIKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIAPIKeyRepository {
+	mock := &MockIAPIKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
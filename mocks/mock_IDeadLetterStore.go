@@ -0,0 +1,215 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	client "github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIDeadLetterStore is an autogenerated mock type for the IDeadLetterStore type
+type MockIDeadLetterStore struct {
+	mock.Mock
+}
+
+type MockIDeadLetterStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIDeadLetterStore) EXPECT() *MockIDeadLetterStore_Expecter {
+	return &MockIDeadLetterStore_Expecter{mock: &_m.Mock}
+}
+
+// List provides a mock function with given fields: ctx, stream, after, limit
+func (_m *MockIDeadLetterStore) List(ctx context.Context, stream string, after string, limit int64) ([]*client.DeadLetter, error) {
+	ret := _m.Called(ctx, stream, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*client.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) ([]*client.DeadLetter, error)); ok {
+		return rf(ctx, stream, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []*client.DeadLetter); ok {
+		r0 = rf(ctx, stream, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, stream, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIDeadLetterStore_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockIDeadLetterStore_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stream string
+//   - after string
+//   - limit int64
+func (_e *MockIDeadLetterStore_Expecter) List(ctx interface{}, stream interface{}, after interface{}, limit interface{}) *MockIDeadLetterStore_List_Call {
+	return &MockIDeadLetterStore_List_Call{Call: _e.mock.On("List", ctx, stream, after, limit)}
+}
+
+func (_c *MockIDeadLetterStore_List_Call) Run(run func(ctx context.Context, stream string, after string, limit int64)) *MockIDeadLetterStore_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *MockIDeadLetterStore_List_Call) Return(_a0 []*client.DeadLetter, _a1 error) *MockIDeadLetterStore_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIDeadLetterStore_List_Call) RunAndReturn(run func(context.Context, string, string, int64) ([]*client.DeadLetter, error)) *MockIDeadLetterStore_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function with given fields: ctx, stream, ids
+func (_m *MockIDeadLetterStore) Purge(ctx context.Context, stream string, ids []string) (int64, error) {
+	ret := _m.Called(ctx, stream, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (int64, error)); ok {
+		return rf(ctx, stream, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) int64); ok {
+		r0 = rf(ctx, stream, ids)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, stream, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIDeadLetterStore_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type MockIDeadLetterStore_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stream string
+//   - ids []string
+func (_e *MockIDeadLetterStore_Expecter) Purge(ctx interface{}, stream interface{}, ids interface{}) *MockIDeadLetterStore_Purge_Call {
+	return &MockIDeadLetterStore_Purge_Call{Call: _e.mock.On("Purge", ctx, stream, ids)}
+}
+
+func (_c *MockIDeadLetterStore_Purge_Call) Run(run func(ctx context.Context, stream string, ids []string)) *MockIDeadLetterStore_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockIDeadLetterStore_Purge_Call) Return(_a0 int64, _a1 error) *MockIDeadLetterStore_Purge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIDeadLetterStore_Purge_Call) RunAndReturn(run func(context.Context, string, []string) (int64, error)) *MockIDeadLetterStore_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Replay provides a mock function with given fields: ctx, stream, id
+func (_m *MockIDeadLetterStore) Replay(ctx context.Context, stream string, id string) (string, error) {
+	ret := _m.Called(ctx, stream, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replay")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, stream, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, stream, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, stream, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIDeadLetterStore_Replay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replay'
+type MockIDeadLetterStore_Replay_Call struct {
+	*mock.Call
+}
+
+// Replay is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stream string
+//   - id string
+func (_e *MockIDeadLetterStore_Expecter) Replay(ctx interface{}, stream interface{}, id interface{}) *MockIDeadLetterStore_Replay_Call {
+	return &MockIDeadLetterStore_Replay_Call{Call: _e.mock.On("Replay", ctx, stream, id)}
+}
+
+func (_c *MockIDeadLetterStore_Replay_Call) Run(run func(ctx context.Context, stream string, id string)) *MockIDeadLetterStore_Replay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockIDeadLetterStore_Replay_Call) Return(_a0 string, _a1 error) *MockIDeadLetterStore_Replay_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIDeadLetterStore_Replay_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *MockIDeadLetterStore_Replay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIDeadLetterStore creates a new instance of MockIDeadLetterStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIDeadLetterStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIDeadLetterStore {
+	mock := &MockIDeadLetterStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,200 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/ar-agahian/ice-assignment/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIFileRepository is an autogenerated mock type for the IFileRepository type
+type MockIFileRepository struct {
+	mock.Mock
+}
+
+type MockIFileRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIFileRepository) EXPECT() *MockIFileRepository_Expecter {
+	return &MockIFileRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, file
+func (_m *MockIFileRepository) Create(ctx context.Context, file *domain.File) error {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.File) error); ok {
+		r0 = rf(ctx, file)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIFileRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIFileRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - file *domain.File
+func (_e *MockIFileRepository_Expecter) Create(ctx interface{}, file interface{}) *MockIFileRepository_Create_Call {
+	return &MockIFileRepository_Create_Call{Call: _e.mock.On("Create", ctx, file)}
+}
+
+func (_c *MockIFileRepository_Create_Call) Run(run func(ctx context.Context, file *domain.File)) *MockIFileRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.File))
+	})
+	return _c
+}
+
+func (_c *MockIFileRepository_Create_Call) Return(_a0 error) *MockIFileRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIFileRepository_Create_Call) RunAndReturn(run func(context.Context, *domain.File) error) *MockIFileRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Exists provides a mock function with given fields: ctx, id
+func (_m *MockIFileRepository) Exists(ctx context.Context, id string) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIFileRepository_Exists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exists'
+type MockIFileRepository_Exists_Call struct {
+	*mock.Call
+}
+
+// Exists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockIFileRepository_Expecter) Exists(ctx interface{}, id interface{}) *MockIFileRepository_Exists_Call {
+	return &MockIFileRepository_Exists_Call{Call: _e.mock.On("Exists", ctx, id)}
+}
+
+func (_c *MockIFileRepository_Exists_Call) Run(run func(ctx context.Context, id string)) *MockIFileRepository_Exists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockIFileRepository_Exists_Call) Return(_a0 bool, _a1 error) *MockIFileRepository_Exists_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIFileRepository_Exists_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockIFileRepository_Exists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockIFileRepository) GetByID(ctx context.Context, id string) (*domain.File, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.File, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.File); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIFileRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockIFileRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockIFileRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockIFileRepository_GetByID_Call {
+	return &MockIFileRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockIFileRepository_GetByID_Call) Run(run func(ctx context.Context, id string)) *MockIFileRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockIFileRepository_GetByID_Call) Return(_a0 *domain.File, _a1 error) *MockIFileRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIFileRepository_GetByID_Call) RunAndReturn(run func(context.Context, string) (*domain.File, error)) *MockIFileRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIFileRepository creates a new instance of MockIFileRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIFileRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIFileRepository {
+	mock := &MockIFileRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,6 +4,9 @@ package mocks
 
 import (
 	context "context"
+
+	client "github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -22,59 +25,49 @@ func (_m *MockIFileStorage) EXPECT() *MockIFileStorage_Expecter {
 	return &MockIFileStorage_Expecter{mock: &_m.Mock}
 }
 
-// Exists provides a mock function with given fields: ctx, fileID
-func (_m *MockIFileStorage) Exists(ctx context.Context, fileID string) (bool, error) {
+// Delete provides a mock function with given fields: ctx, fileID
+func (_m *MockIFileStorage) Delete(ctx context.Context, fileID string) error {
 	ret := _m.Called(ctx, fileID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Exists")
+		panic("no return value specified for Delete")
 	}
 
-	var r0 bool
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return rf(ctx, fileID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
 		r0 = rf(ctx, fileID)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, fileID)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
+	return r0
 }
 
-// MockIFileStorage_Exists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exists'
-type MockIFileStorage_Exists_Call struct {
+// MockIFileStorage_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockIFileStorage_Delete_Call struct {
 	*mock.Call
 }
 
-// Exists is a helper method to define mock.On call
+// Delete is a helper method to define mock.On call
 //   - ctx context.Context
 //   - fileID string
-func (_e *MockIFileStorage_Expecter) Exists(ctx interface{}, fileID interface{}) *MockIFileStorage_Exists_Call {
-	return &MockIFileStorage_Exists_Call{Call: _e.mock.On("Exists", ctx, fileID)}
+func (_e *MockIFileStorage_Expecter) Delete(ctx interface{}, fileID interface{}) *MockIFileStorage_Delete_Call {
+	return &MockIFileStorage_Delete_Call{Call: _e.mock.On("Delete", ctx, fileID)}
 }
 
-func (_c *MockIFileStorage_Exists_Call) Run(run func(ctx context.Context, fileID string)) *MockIFileStorage_Exists_Call {
+func (_c *MockIFileStorage_Delete_Call) Run(run func(ctx context.Context, fileID string)) *MockIFileStorage_Delete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockIFileStorage_Exists_Call) Return(_a0 bool, _a1 error) *MockIFileStorage_Exists_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockIFileStorage_Delete_Call) Return(_a0 error) *MockIFileStorage_Delete_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockIFileStorage_Exists_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockIFileStorage_Exists_Call {
+func (_c *MockIFileStorage_Delete_Call) RunAndReturn(run func(context.Context, string) error) *MockIFileStorage_Delete_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -138,9 +131,69 @@ func (_c *MockIFileStorage_Get_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
-// Upload provides a mock function with given fields: ctx, file, contentType
-func (_m *MockIFileStorage) Upload(ctx context.Context, file io.Reader, contentType string) (string, error) {
-	ret := _m.Called(ctx, file, contentType)
+// Open provides a mock function with given fields: ctx, fileID, opts
+func (_m *MockIFileStorage) Open(ctx context.Context, fileID string, opts client.OpenFileOptions) (*client.FileObject, error) {
+	ret := _m.Called(ctx, fileID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 *client.FileObject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.OpenFileOptions) (*client.FileObject, error)); ok {
+		return rf(ctx, fileID, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, client.OpenFileOptions) *client.FileObject); ok {
+		r0 = rf(ctx, fileID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.FileObject)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, client.OpenFileOptions) error); ok {
+		r1 = rf(ctx, fileID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIFileStorage_Open_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Open'
+type MockIFileStorage_Open_Call struct {
+	*mock.Call
+}
+
+// Open is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fileID string
+//   - opts client.OpenFileOptions
+func (_e *MockIFileStorage_Expecter) Open(ctx interface{}, fileID interface{}, opts interface{}) *MockIFileStorage_Open_Call {
+	return &MockIFileStorage_Open_Call{Call: _e.mock.On("Open", ctx, fileID, opts)}
+}
+
+func (_c *MockIFileStorage_Open_Call) Run(run func(ctx context.Context, fileID string, opts client.OpenFileOptions)) *MockIFileStorage_Open_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(client.OpenFileOptions))
+	})
+	return _c
+}
+
+func (_c *MockIFileStorage_Open_Call) Return(_a0 *client.FileObject, _a1 error) *MockIFileStorage_Open_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIFileStorage_Open_Call) RunAndReturn(run func(context.Context, string, client.OpenFileOptions) (*client.FileObject, error)) *MockIFileStorage_Open_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Upload provides a mock function with given fields: ctx, file, contentType, filename
+func (_m *MockIFileStorage) Upload(ctx context.Context, file io.Reader, contentType string, filename string) (string, error) {
+	ret := _m.Called(ctx, file, contentType, filename)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Upload")
@@ -148,17 +201,17 @@ func (_m *MockIFileStorage) Upload(ctx context.Context, file io.Reader, contentT
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string) (string, error)); ok {
-		return rf(ctx, file, contentType)
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string) (string, error)); ok {
+		return rf(ctx, file, contentType, filename)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string) string); ok {
-		r0 = rf(ctx, file, contentType)
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string) string); ok {
+		r0 = rf(ctx, file, contentType, filename)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, string) error); ok {
-		r1 = rf(ctx, file, contentType)
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, string, string) error); ok {
+		r1 = rf(ctx, file, contentType, filename)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -175,13 +228,14 @@ type MockIFileStorage_Upload_Call struct {
 //   - ctx context.Context
 //   - file io.Reader
 //   - contentType string
-func (_e *MockIFileStorage_Expecter) Upload(ctx interface{}, file interface{}, contentType interface{}) *MockIFileStorage_Upload_Call {
-	return &MockIFileStorage_Upload_Call{Call: _e.mock.On("Upload", ctx, file, contentType)}
+//   - filename string
+func (_e *MockIFileStorage_Expecter) Upload(ctx interface{}, file interface{}, contentType interface{}, filename interface{}) *MockIFileStorage_Upload_Call {
+	return &MockIFileStorage_Upload_Call{Call: _e.mock.On("Upload", ctx, file, contentType, filename)}
 }
 
-func (_c *MockIFileStorage_Upload_Call) Run(run func(ctx context.Context, file io.Reader, contentType string)) *MockIFileStorage_Upload_Call {
+func (_c *MockIFileStorage_Upload_Call) Run(run func(ctx context.Context, file io.Reader, contentType string, filename string)) *MockIFileStorage_Upload_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(io.Reader), args[2].(string))
+		run(args[0].(context.Context), args[1].(io.Reader), args[2].(string), args[3].(string))
 	})
 	return _c
 }
@@ -191,7 +245,7 @@ func (_c *MockIFileStorage_Upload_Call) Return(fileID string, err error) *MockIF
 	return _c
 }
 
-func (_c *MockIFileStorage_Upload_Call) RunAndReturn(run func(context.Context, io.Reader, string) (string, error)) *MockIFileStorage_Upload_Call {
+func (_c *MockIFileStorage_Upload_Call) RunAndReturn(run func(context.Context, io.Reader, string, string) (string, error)) *MockIFileStorage_Upload_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIHealthChecker is an autogenerated mock type for the IHealthChecker type
+type MockIHealthChecker struct {
+	mock.Mock
+}
+
+type MockIHealthChecker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIHealthChecker) EXPECT() *MockIHealthChecker_Expecter {
+	return &MockIHealthChecker_Expecter{mock: &_m.Mock}
+}
+
+// Check provides a mock function with given fields: ctx
+func (_m *MockIHealthChecker) Check(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIHealthChecker_Check_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Check'
+type MockIHealthChecker_Check_Call struct {
+	*mock.Call
+}
+
+// Check is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockIHealthChecker_Expecter) Check(ctx interface{}) *MockIHealthChecker_Check_Call {
+	return &MockIHealthChecker_Check_Call{Call: _e.mock.On("Check", ctx)}
+}
+
+func (_c *MockIHealthChecker_Check_Call) Run(run func(ctx context.Context)) *MockIHealthChecker_Check_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIHealthChecker_Check_Call) Return(_a0 error) *MockIHealthChecker_Check_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIHealthChecker_Check_Call) RunAndReturn(run func(context.Context) error) *MockIHealthChecker_Check_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIHealthChecker creates a new instance of MockIHealthChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIHealthChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIHealthChecker {
+	mock := &MockIHealthChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
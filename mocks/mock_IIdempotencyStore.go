@@ -0,0 +1,240 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	client "github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIIdempotencyStore is an autogenerated mock type for the IIdempotencyStore type
+type MockIIdempotencyStore struct {
+	mock.Mock
+}
+
+type MockIIdempotencyStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIIdempotencyStore) EXPECT() *MockIIdempotencyStore_Expecter {
+	return &MockIIdempotencyStore_Expecter{mock: &_m.Mock}
+}
+
+// Complete provides a mock function with given fields: ctx, key, record
+func (_m *MockIIdempotencyStore) Complete(ctx context.Context, key string, record *client.IdempotencyRecord) error {
+	ret := _m.Called(ctx, key, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *client.IdempotencyRecord) error); ok {
+		r0 = rf(ctx, key, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIIdempotencyStore_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type MockIIdempotencyStore_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - record *client.IdempotencyRecord
+func (_e *MockIIdempotencyStore_Expecter) Complete(ctx interface{}, key interface{}, record interface{}) *MockIIdempotencyStore_Complete_Call {
+	return &MockIIdempotencyStore_Complete_Call{Call: _e.mock.On("Complete", ctx, key, record)}
+}
+
+func (_c *MockIIdempotencyStore_Complete_Call) Run(run func(ctx context.Context, key string, record *client.IdempotencyRecord)) *MockIIdempotencyStore_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*client.IdempotencyRecord))
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Complete_Call) Return(_a0 error) *MockIIdempotencyStore_Complete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Complete_Call) RunAndReturn(run func(context.Context, string, *client.IdempotencyRecord) error) *MockIIdempotencyStore_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Extend provides a mock function with given fields: ctx, key
+func (_m *MockIIdempotencyStore) Extend(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Extend")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIIdempotencyStore_Extend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Extend'
+type MockIIdempotencyStore_Extend_Call struct {
+	*mock.Call
+}
+
+// Extend is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockIIdempotencyStore_Expecter) Extend(ctx interface{}, key interface{}) *MockIIdempotencyStore_Extend_Call {
+	return &MockIIdempotencyStore_Extend_Call{Call: _e.mock.On("Extend", ctx, key)}
+}
+
+func (_c *MockIIdempotencyStore_Extend_Call) Run(run func(ctx context.Context, key string)) *MockIIdempotencyStore_Extend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Extend_Call) Return(_a0 error) *MockIIdempotencyStore_Extend_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Extend_Call) RunAndReturn(run func(context.Context, string) error) *MockIIdempotencyStore_Extend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function with given fields: ctx, key
+func (_m *MockIIdempotencyStore) Release(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIIdempotencyStore_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type MockIIdempotencyStore_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockIIdempotencyStore_Expecter) Release(ctx interface{}, key interface{}) *MockIIdempotencyStore_Release_Call {
+	return &MockIIdempotencyStore_Release_Call{Call: _e.mock.On("Release", ctx, key)}
+}
+
+func (_c *MockIIdempotencyStore_Release_Call) Run(run func(ctx context.Context, key string)) *MockIIdempotencyStore_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Release_Call) Return(_a0 error) *MockIIdempotencyStore_Release_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Release_Call) RunAndReturn(run func(context.Context, string) error) *MockIIdempotencyStore_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reserve provides a mock function with given fields: ctx, key, fingerprint
+func (_m *MockIIdempotencyStore) Reserve(ctx context.Context, key string, fingerprint string) (*client.IdempotencyRecord, error) {
+	ret := _m.Called(ctx, key, fingerprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 *client.IdempotencyRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*client.IdempotencyRecord, error)); ok {
+		return rf(ctx, key, fingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *client.IdempotencyRecord); ok {
+		r0 = rf(ctx, key, fingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, key, fingerprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIIdempotencyStore_Reserve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reserve'
+type MockIIdempotencyStore_Reserve_Call struct {
+	*mock.Call
+}
+
+// Reserve is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - fingerprint string
+func (_e *MockIIdempotencyStore_Expecter) Reserve(ctx interface{}, key interface{}, fingerprint interface{}) *MockIIdempotencyStore_Reserve_Call {
+	return &MockIIdempotencyStore_Reserve_Call{Call: _e.mock.On("Reserve", ctx, key, fingerprint)}
+}
+
+func (_c *MockIIdempotencyStore_Reserve_Call) Run(run func(ctx context.Context, key string, fingerprint string)) *MockIIdempotencyStore_Reserve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Reserve_Call) Return(_a0 *client.IdempotencyRecord, _a1 error) *MockIIdempotencyStore_Reserve_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIIdempotencyStore_Reserve_Call) RunAndReturn(run func(context.Context, string, string) (*client.IdempotencyRecord, error)) *MockIIdempotencyStore_Reserve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIIdempotencyStore creates a new instance of MockIIdempotencyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIIdempotencyStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIIdempotencyStore {
+	mock := &MockIIdempotencyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
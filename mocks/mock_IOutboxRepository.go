@@ -0,0 +1,337 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/ar-agahian/ice-assignment/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockIOutboxRepository is an autogenerated mock type for the IOutboxRepository type
+type MockIOutboxRepository struct {
+	mock.Mock
+}
+
+type MockIOutboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIOutboxRepository) EXPECT() *MockIOutboxRepository_Expecter {
+	return &MockIOutboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// Add provides a mock function with given fields: ctx, message
+func (_m *MockIOutboxRepository) Add(ctx context.Context, message *domain.OutboxMessage) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OutboxMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIOutboxRepository_Add_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Add'
+type MockIOutboxRepository_Add_Call struct {
+	*mock.Call
+}
+
+// Add is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *domain.OutboxMessage
+func (_e *MockIOutboxRepository_Expecter) Add(ctx interface{}, message interface{}) *MockIOutboxRepository_Add_Call {
+	return &MockIOutboxRepository_Add_Call{Call: _e.mock.On("Add", ctx, message)}
+}
+
+func (_c *MockIOutboxRepository_Add_Call) Run(run func(ctx context.Context, message *domain.OutboxMessage)) *MockIOutboxRepository_Add_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Add_Call) Return(_a0 error) *MockIOutboxRepository_Add_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Add_Call) RunAndReturn(run func(context.Context, *domain.OutboxMessage) error) *MockIOutboxRepository_Add_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockIOutboxRepository) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIOutboxRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockIOutboxRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockIOutboxRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockIOutboxRepository_Delete_Call {
+	return &MockIOutboxRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockIOutboxRepository_Delete_Call) Run(run func(ctx context.Context, id string)) *MockIOutboxRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Delete_Call) Return(_a0 error) *MockIOutboxRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Delete_Call) RunAndReturn(run func(context.Context, string) error) *MockIOutboxRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchPending provides a mock function with given fields: ctx, limit
+func (_m *MockIOutboxRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchPending")
+	}
+
+	var r0 []*domain.OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*domain.OutboxMessage, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*domain.OutboxMessage); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIOutboxRepository_FetchPending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchPending'
+type MockIOutboxRepository_FetchPending_Call struct {
+	*mock.Call
+}
+
+// FetchPending is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *MockIOutboxRepository_Expecter) FetchPending(ctx interface{}, limit interface{}) *MockIOutboxRepository_FetchPending_Call {
+	return &MockIOutboxRepository_FetchPending_Call{Call: _e.mock.On("FetchPending", ctx, limit)}
+}
+
+func (_c *MockIOutboxRepository_FetchPending_Call) Run(run func(ctx context.Context, limit int)) *MockIOutboxRepository_FetchPending_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_FetchPending_Call) Return(_a0 []*domain.OutboxMessage, _a1 error) *MockIOutboxRepository_FetchPending_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_FetchPending_Call) RunAndReturn(run func(context.Context, int) ([]*domain.OutboxMessage, error)) *MockIOutboxRepository_FetchPending_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Lease provides a mock function with given fields: ctx, ids, until
+func (_m *MockIOutboxRepository) Lease(ctx context.Context, ids []string, until time.Time) error {
+	ret := _m.Called(ctx, ids, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time) error); ok {
+		r0 = rf(ctx, ids, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIOutboxRepository_Lease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lease'
+type MockIOutboxRepository_Lease_Call struct {
+	*mock.Call
+}
+
+// Lease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []string
+//   - until time.Time
+func (_e *MockIOutboxRepository_Expecter) Lease(ctx interface{}, ids interface{}, until interface{}) *MockIOutboxRepository_Lease_Call {
+	return &MockIOutboxRepository_Lease_Call{Call: _e.mock.On("Lease", ctx, ids, until)}
+}
+
+func (_c *MockIOutboxRepository_Lease_Call) Run(run func(ctx context.Context, ids []string, until time.Time)) *MockIOutboxRepository_Lease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Lease_Call) Return(_a0 error) *MockIOutboxRepository_Lease_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_Lease_Call) RunAndReturn(run func(context.Context, []string, time.Time) error) *MockIOutboxRepository_Lease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkExhausted provides a mock function with given fields: ctx, id, lastError
+func (_m *MockIOutboxRepository) MarkExhausted(ctx context.Context, id string, lastError string) error {
+	ret := _m.Called(ctx, id, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkExhausted")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIOutboxRepository_MarkExhausted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkExhausted'
+type MockIOutboxRepository_MarkExhausted_Call struct {
+	*mock.Call
+}
+
+// MarkExhausted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - lastError string
+func (_e *MockIOutboxRepository_Expecter) MarkExhausted(ctx interface{}, id interface{}, lastError interface{}) *MockIOutboxRepository_MarkExhausted_Call {
+	return &MockIOutboxRepository_MarkExhausted_Call{Call: _e.mock.On("MarkExhausted", ctx, id, lastError)}
+}
+
+func (_c *MockIOutboxRepository_MarkExhausted_Call) Run(run func(ctx context.Context, id string, lastError string)) *MockIOutboxRepository_MarkExhausted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkExhausted_Call) Return(_a0 error) *MockIOutboxRepository_MarkExhausted_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkExhausted_Call) RunAndReturn(run func(context.Context, string, string) error) *MockIOutboxRepository_MarkExhausted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkFailed provides a mock function with given fields: ctx, id, lastError, nextAttemptAt
+func (_m *MockIOutboxRepository) MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error {
+	ret := _m.Called(ctx, id, lastError, nextAttemptAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, lastError, nextAttemptAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIOutboxRepository_MarkFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkFailed'
+type MockIOutboxRepository_MarkFailed_Call struct {
+	*mock.Call
+}
+
+// MarkFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - lastError string
+//   - nextAttemptAt time.Time
+func (_e *MockIOutboxRepository_Expecter) MarkFailed(ctx interface{}, id interface{}, lastError interface{}, nextAttemptAt interface{}) *MockIOutboxRepository_MarkFailed_Call {
+	return &MockIOutboxRepository_MarkFailed_Call{Call: _e.mock.On("MarkFailed", ctx, id, lastError, nextAttemptAt)}
+}
+
+func (_c *MockIOutboxRepository_MarkFailed_Call) Run(run func(ctx context.Context, id string, lastError string, nextAttemptAt time.Time)) *MockIOutboxRepository_MarkFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkFailed_Call) Return(_a0 error) *MockIOutboxRepository_MarkFailed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_MarkFailed_Call) RunAndReturn(run func(context.Context, string, string, time.Time) error) *MockIOutboxRepository_MarkFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIOutboxRepository creates a new instance of MockIOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIOutboxRepository {
+	mock := &MockIOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,250 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/ar-agahian/ice-assignment/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIRoleRepository is an autogenerated mock type for the IRoleRepository type
+type MockIRoleRepository struct {
+	mock.Mock
+}
+
+type MockIRoleRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIRoleRepository) EXPECT() *MockIRoleRepository_Expecter {
+	return &MockIRoleRepository_Expecter{mock: &_m.Mock}
+}
+
+// Assign provides a mock function with given fields: ctx, assignment
+func (_m *MockIRoleRepository) Assign(ctx context.Context, assignment *domain.RoleAssignment) error {
+	ret := _m.Called(ctx, assignment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Assign")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.RoleAssignment) error); ok {
+		r0 = rf(ctx, assignment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIRoleRepository_Assign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Assign'
+type MockIRoleRepository_Assign_Call struct {
+	*mock.Call
+}
+
+// Assign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - assignment *domain.RoleAssignment
+func (_e *MockIRoleRepository_Expecter) Assign(ctx interface{}, assignment interface{}) *MockIRoleRepository_Assign_Call {
+	return &MockIRoleRepository_Assign_Call{Call: _e.mock.On("Assign", ctx, assignment)}
+}
+
+func (_c *MockIRoleRepository_Assign_Call) Run(run func(ctx context.Context, assignment *domain.RoleAssignment)) *MockIRoleRepository_Assign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.RoleAssignment))
+	})
+	return _c
+}
+
+func (_c *MockIRoleRepository_Assign_Call) Return(_a0 error) *MockIRoleRepository_Assign_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIRoleRepository_Assign_Call) RunAndReturn(run func(context.Context, *domain.RoleAssignment) error) *MockIRoleRepository_Assign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, principalType, principalID
+func (_m *MockIRoleRepository) Delete(ctx context.Context, principalType domain.PrincipalType, principalID string) error {
+	ret := _m.Called(ctx, principalType, principalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PrincipalType, string) error); ok {
+		r0 = rf(ctx, principalType, principalID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIRoleRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockIRoleRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - principalType domain.PrincipalType
+//   - principalID string
+func (_e *MockIRoleRepository_Expecter) Delete(ctx interface{}, principalType interface{}, principalID interface{}) *MockIRoleRepository_Delete_Call {
+	return &MockIRoleRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, principalType, principalID)}
+}
+
+func (_c *MockIRoleRepository_Delete_Call) Run(run func(ctx context.Context, principalType domain.PrincipalType, principalID string)) *MockIRoleRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.PrincipalType), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockIRoleRepository_Delete_Call) Return(_a0 error) *MockIRoleRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIRoleRepository_Delete_Call) RunAndReturn(run func(context.Context, domain.PrincipalType, string) error) *MockIRoleRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, principalType, principalID
+func (_m *MockIRoleRepository) Get(ctx context.Context, principalType domain.PrincipalType, principalID string) (*domain.RoleAssignment, error) {
+	ret := _m.Called(ctx, principalType, principalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.RoleAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PrincipalType, string) (*domain.RoleAssignment, error)); ok {
+		return rf(ctx, principalType, principalID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PrincipalType, string) *domain.RoleAssignment); ok {
+		r0 = rf(ctx, principalType, principalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RoleAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PrincipalType, string) error); ok {
+		r1 = rf(ctx, principalType, principalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIRoleRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockIRoleRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - principalType domain.PrincipalType
+//   - principalID string
+func (_e *MockIRoleRepository_Expecter) Get(ctx interface{}, principalType interface{}, principalID interface{}) *MockIRoleRepository_Get_Call {
+	return &MockIRoleRepository_Get_Call{Call: _e.mock.On("Get", ctx, principalType, principalID)}
+}
+
+func (_c *MockIRoleRepository_Get_Call) Run(run func(ctx context.Context, principalType domain.PrincipalType, principalID string)) *MockIRoleRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.PrincipalType), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockIRoleRepository_Get_Call) Return(_a0 *domain.RoleAssignment, _a1 error) *MockIRoleRepository_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIRoleRepository_Get_Call) RunAndReturn(run func(context.Context, domain.PrincipalType, string) (*domain.RoleAssignment, error)) *MockIRoleRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *MockIRoleRepository) List(ctx context.Context) ([]*domain.RoleAssignment, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.RoleAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.RoleAssignment, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.RoleAssignment); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.RoleAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIRoleRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockIRoleRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockIRoleRepository_Expecter) List(ctx interface{}) *MockIRoleRepository_List_Call {
+	return &MockIRoleRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *MockIRoleRepository_List_Call) Run(run func(ctx context.Context)) *MockIRoleRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIRoleRepository_List_Call) Return(_a0 []*domain.RoleAssignment, _a1 error) *MockIRoleRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIRoleRepository_List_Call) RunAndReturn(run func(context.Context) ([]*domain.RoleAssignment, error)) *MockIRoleRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIRoleRepository creates a new instance of MockIRoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIRoleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIRoleRepository {
+	mock := &MockIRoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,165 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	client "github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIStreamConsumer is an autogenerated mock type for the IStreamConsumer type
+type MockIStreamConsumer struct {
+	mock.Mock
+}
+
+type MockIStreamConsumer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIStreamConsumer) EXPECT() *MockIStreamConsumer_Expecter {
+	return &MockIStreamConsumer_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function with given fields: stream, handler, policy
+func (_m *MockIStreamConsumer) Handle(stream string, handler client.MessageHandler, policy client.RetryPolicy) {
+	_m.Called(stream, handler, policy)
+}
+
+// MockIStreamConsumer_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIStreamConsumer_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - stream string
+//   - handler client.MessageHandler
+//   - policy client.RetryPolicy
+func (_e *MockIStreamConsumer_Expecter) Handle(stream interface{}, handler interface{}, policy interface{}) *MockIStreamConsumer_Handle_Call {
+	return &MockIStreamConsumer_Handle_Call{Call: _e.mock.On("Handle", stream, handler, policy)}
+}
+
+func (_c *MockIStreamConsumer_Handle_Call) Run(run func(stream string, handler client.MessageHandler, policy client.RetryPolicy)) *MockIStreamConsumer_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(client.MessageHandler), args[2].(client.RetryPolicy))
+	})
+	return _c
+}
+
+func (_c *MockIStreamConsumer_Handle_Call) Return() *MockIStreamConsumer_Handle_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockIStreamConsumer_Handle_Call) RunAndReturn(run func(string, client.MessageHandler, client.RetryPolicy)) *MockIStreamConsumer_Handle_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Run provides a mock function with given fields: ctx
+func (_m *MockIStreamConsumer) Run(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIStreamConsumer_Run_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Run'
+type MockIStreamConsumer_Run_Call struct {
+	*mock.Call
+}
+
+// Run is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockIStreamConsumer_Expecter) Run(ctx interface{}) *MockIStreamConsumer_Run_Call {
+	return &MockIStreamConsumer_Run_Call{Call: _e.mock.On("Run", ctx)}
+}
+
+func (_c *MockIStreamConsumer_Run_Call) Run(run func(ctx context.Context)) *MockIStreamConsumer_Run_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStreamConsumer_Run_Call) Return(_a0 error) *MockIStreamConsumer_Run_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIStreamConsumer_Run_Call) RunAndReturn(run func(context.Context) error) *MockIStreamConsumer_Run_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *MockIStreamConsumer) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stop")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockIStreamConsumer_Stop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stop'
+type MockIStreamConsumer_Stop_Call struct {
+	*mock.Call
+}
+
+// Stop is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockIStreamConsumer_Expecter) Stop(ctx interface{}) *MockIStreamConsumer_Stop_Call {
+	return &MockIStreamConsumer_Stop_Call{Call: _e.mock.On("Stop", ctx)}
+}
+
+func (_c *MockIStreamConsumer_Stop_Call) Run(run func(ctx context.Context)) *MockIStreamConsumer_Stop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockIStreamConsumer_Stop_Call) Return(_a0 error) *MockIStreamConsumer_Stop_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockIStreamConsumer_Stop_Call) RunAndReturn(run func(context.Context) error) *MockIStreamConsumer_Stop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIStreamConsumer creates a new instance of MockIStreamConsumer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIStreamConsumer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIStreamConsumer {
+	mock := &MockIStreamConsumer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
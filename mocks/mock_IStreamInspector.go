@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	client "github.com/ar-agahian/ice-assignment/internal/interfaces/client"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIStreamInspector is an autogenerated mock type for the IStreamInspector type
+type MockIStreamInspector struct {
+	mock.Mock
+}
+
+type MockIStreamInspector_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIStreamInspector) EXPECT() *MockIStreamInspector_Expecter {
+	return &MockIStreamInspector_Expecter{mock: &_m.Mock}
+}
+
+// Info provides a mock function with given fields: ctx, stream
+func (_m *MockIStreamInspector) Info(ctx context.Context, stream string) (*client.StreamInfo, error) {
+	ret := _m.Called(ctx, stream)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Info")
+	}
+
+	var r0 *client.StreamInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.StreamInfo, error)); ok {
+		return rf(ctx, stream)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *client.StreamInfo); ok {
+		r0 = rf(ctx, stream)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.StreamInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, stream)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIStreamInspector_Info_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Info'
+type MockIStreamInspector_Info_Call struct {
+	*mock.Call
+}
+
+// Info is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stream string
+func (_e *MockIStreamInspector_Expecter) Info(ctx interface{}, stream interface{}) *MockIStreamInspector_Info_Call {
+	return &MockIStreamInspector_Info_Call{Call: _e.mock.On("Info", ctx, stream)}
+}
+
+func (_c *MockIStreamInspector_Info_Call) Run(run func(ctx context.Context, stream string)) *MockIStreamInspector_Info_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockIStreamInspector_Info_Call) Return(_a0 *client.StreamInfo, _a1 error) *MockIStreamInspector_Info_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIStreamInspector_Info_Call) RunAndReturn(run func(context.Context, string) (*client.StreamInfo, error)) *MockIStreamInspector_Info_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIStreamInspector creates a new instance of MockIStreamInspector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIStreamInspector(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIStreamInspector {
+	mock := &MockIStreamInspector{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/ar-agahian/ice-assignment/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/ar-agahian/ice-assignment/internal/interfaces/repository"
 )
 
 // MockITodoRepository is an autogenerated mock type for the ITodoRepository type
@@ -69,6 +71,53 @@ func (_c *MockITodoRepository_Create_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockITodoRepository) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockITodoRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockITodoRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockITodoRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockITodoRepository_Delete_Call {
+	return &MockITodoRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockITodoRepository_Delete_Call) Run(run func(ctx context.Context, id string)) *MockITodoRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockITodoRepository_Delete_Call) Return(_a0 error) *MockITodoRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockITodoRepository_Delete_Call) RunAndReturn(run func(context.Context, string) error) *MockITodoRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *MockITodoRepository) GetByID(ctx context.Context, id string) (*domain.TodoItem, error) {
 	ret := _m.Called(ctx, id)
@@ -128,6 +177,171 @@ func (_c *MockITodoRepository_GetByID_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// GetByIDForUpdate provides a mock function with given fields: ctx, id
+func (_m *MockITodoRepository) GetByIDForUpdate(ctx context.Context, id string) (*domain.TodoItem, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDForUpdate")
+	}
+
+	var r0 *domain.TodoItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TodoItem, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TodoItem); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TodoItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockITodoRepository_GetByIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByIDForUpdate'
+type MockITodoRepository_GetByIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// GetByIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockITodoRepository_Expecter) GetByIDForUpdate(ctx interface{}, id interface{}) *MockITodoRepository_GetByIDForUpdate_Call {
+	return &MockITodoRepository_GetByIDForUpdate_Call{Call: _e.mock.On("GetByIDForUpdate", ctx, id)}
+}
+
+func (_c *MockITodoRepository_GetByIDForUpdate_Call) Run(run func(ctx context.Context, id string)) *MockITodoRepository_GetByIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockITodoRepository_GetByIDForUpdate_Call) Return(_a0 *domain.TodoItem, _a1 error) *MockITodoRepository_GetByIDForUpdate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockITodoRepository_GetByIDForUpdate_Call) RunAndReturn(run func(context.Context, string) (*domain.TodoItem, error)) *MockITodoRepository_GetByIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *MockITodoRepository) List(ctx context.Context, query repository.ListQuery) ([]*domain.TodoItem, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.TodoItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ListQuery) ([]*domain.TodoItem, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ListQuery) []*domain.TodoItem); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TodoItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.ListQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockITodoRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockITodoRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query repository.ListQuery
+func (_e *MockITodoRepository_Expecter) List(ctx interface{}, query interface{}) *MockITodoRepository_List_Call {
+	return &MockITodoRepository_List_Call{Call: _e.mock.On("List", ctx, query)}
+}
+
+func (_c *MockITodoRepository_List_Call) Run(run func(ctx context.Context, query repository.ListQuery)) *MockITodoRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.ListQuery))
+	})
+	return _c
+}
+
+func (_c *MockITodoRepository_List_Call) Return(_a0 []*domain.TodoItem, _a1 error) *MockITodoRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockITodoRepository_List_Call) RunAndReturn(run func(context.Context, repository.ListQuery) ([]*domain.TodoItem, error)) *MockITodoRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, item
+func (_m *MockITodoRepository) Update(ctx context.Context, item *domain.TodoItem) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TodoItem) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockITodoRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockITodoRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - item *domain.TodoItem
+func (_e *MockITodoRepository_Expecter) Update(ctx interface{}, item interface{}) *MockITodoRepository_Update_Call {
+	return &MockITodoRepository_Update_Call{Call: _e.mock.On("Update", ctx, item)}
+}
+
+func (_c *MockITodoRepository_Update_Call) Run(run func(ctx context.Context, item *domain.TodoItem)) *MockITodoRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.TodoItem))
+	})
+	return _c
+}
+
+func (_c *MockITodoRepository_Update_Call) Return(_a0 error) *MockITodoRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockITodoRepository_Update_Call) RunAndReturn(run func(context.Context, *domain.TodoItem) error) *MockITodoRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockITodoRepository creates a new instance of MockITodoRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockITodoRepository(t interface {
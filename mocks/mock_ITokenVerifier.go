@@ -0,0 +1,96 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/ar-agahian/ice-assignment/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockITokenVerifier is an autogenerated mock type for the ITokenVerifier type
+type MockITokenVerifier struct {
+	mock.Mock
+}
+
+type MockITokenVerifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockITokenVerifier) EXPECT() *MockITokenVerifier_Expecter {
+	return &MockITokenVerifier_Expecter{mock: &_m.Mock}
+}
+
+// Verify provides a mock function with given fields: ctx, token
+func (_m *MockITokenVerifier) Verify(ctx context.Context, token string) (*domain.Principal, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 *domain.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Principal, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Principal); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Principal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockITokenVerifier_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type MockITokenVerifier_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockITokenVerifier_Expecter) Verify(ctx interface{}, token interface{}) *MockITokenVerifier_Verify_Call {
+	return &MockITokenVerifier_Verify_Call{Call: _e.mock.On("Verify", ctx, token)}
+}
+
+func (_c *MockITokenVerifier_Verify_Call) Run(run func(ctx context.Context, token string)) *MockITokenVerifier_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockITokenVerifier_Verify_Call) Return(_a0 *domain.Principal, _a1 error) *MockITokenVerifier_Verify_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockITokenVerifier_Verify_Call) RunAndReturn(run func(context.Context, string) (*domain.Principal, error)) *MockITokenVerifier_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockITokenVerifier creates a new instance of MockITokenVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockITokenVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockITokenVerifier {
+	mock := &MockITokenVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
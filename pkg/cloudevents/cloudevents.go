@@ -0,0 +1,155 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SpecVersion is the CloudEvents specification version produced and accepted
+	SpecVersion = "1.0"
+	// ContentType is the content type of a structured-mode CloudEvent
+	ContentType = "application/cloudevents+json"
+
+	// ContentTypeField is the stream field carrying the message content type
+	ContentTypeField = "content-type"
+	// DataField is the stream field carrying the event (structured) or its data (binary)
+	DataField = "data"
+	// BinaryFieldPrefix prefixes attribute fields in binary mode
+	BinaryFieldPrefix = "ce_"
+)
+
+// Event is a CloudEvents 1.0 event with the extension attributes this service uses,
+// including the distributed tracing extension
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	TenantID        string          `json:"tenantid,omitempty"`
+	CorrelationID   string          `json:"correlationid,omitempty"`
+	SchemaVersion   int             `json:"schemaversion,omitempty"`
+	TraceParent     string          `json:"traceparent,omitempty"`
+	TraceState      string          `json:"tracestate,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// Validate checks the attributes required by the specification
+func (e *Event) Validate() error {
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("unsupported specversion %q", e.SpecVersion)
+	}
+	if e.ID == "" || e.Source == "" || e.Type == "" {
+		return errors.New("id, source and type are required")
+	}
+	return nil
+}
+
+// EncodeStructured encodes the event as a single JSON document in the data field
+func EncodeStructured(e *Event) (map[string]interface{}, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	doc, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		ContentTypeField: ContentType,
+		DataField:        string(doc),
+	}, nil
+}
+
+// EncodeBinary encodes each attribute as its own ce_-prefixed field and the event data in the data field
+func EncodeBinary(e *Event) (map[string]interface{}, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{
+		BinaryFieldPrefix + "specversion": e.SpecVersion,
+		BinaryFieldPrefix + "id":          e.ID,
+		BinaryFieldPrefix + "source":      e.Source,
+		BinaryFieldPrefix + "type":        e.Type,
+		DataField:                         string(e.Data),
+	}
+	optional := map[string]string{
+		"subject":       e.Subject,
+		"dataschema":    e.DataSchema,
+		"tenantid":      e.TenantID,
+		"correlationid": e.CorrelationID,
+		"traceparent":   e.TraceParent,
+		"tracestate":    e.TraceState,
+	}
+	if e.Time != nil {
+		optional["time"] = e.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if e.SchemaVersion != 0 {
+		optional["schemaversion"] = strconv.Itoa(e.SchemaVersion)
+	}
+	for name, value := range optional {
+		if value != "" {
+			values[BinaryFieldPrefix+name] = value
+		}
+	}
+	if e.DataContentType != "" {
+		values[ContentTypeField] = e.DataContentType
+	}
+	return values, nil
+}
+
+// Decode decodes a stream message in either CloudEvents mode. It returns false
+// when the message is not a CloudEvent.
+func Decode(values map[string]interface{}) (*Event, bool, error) {
+	field := func(name string) string {
+		value, _ := values[name].(string)
+		return value
+	}
+	if strings.HasPrefix(field(ContentTypeField), ContentType) {
+		var event Event
+		if err := json.Unmarshal([]byte(field(DataField)), &event); err != nil {
+			return nil, true, fmt.Errorf("decode structured cloudevent: %w", err)
+		}
+		return &event, true, event.Validate()
+	}
+	if field(BinaryFieldPrefix+"specversion") == "" {
+		return nil, false, nil
+	}
+
+	event := Event{
+		SpecVersion:     field(BinaryFieldPrefix + "specversion"),
+		ID:              field(BinaryFieldPrefix + "id"),
+		Source:          field(BinaryFieldPrefix + "source"),
+		Type:            field(BinaryFieldPrefix + "type"),
+		Subject:         field(BinaryFieldPrefix + "subject"),
+		DataContentType: field(ContentTypeField),
+		DataSchema:      field(BinaryFieldPrefix + "dataschema"),
+		TenantID:        field(BinaryFieldPrefix + "tenantid"),
+		CorrelationID:   field(BinaryFieldPrefix + "correlationid"),
+		TraceParent:     field(BinaryFieldPrefix + "traceparent"),
+		TraceState:      field(BinaryFieldPrefix + "tracestate"),
+		Data:            json.RawMessage(field(DataField)),
+	}
+	if value := field(BinaryFieldPrefix + "time"); value != "" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, true, fmt.Errorf("decode cloudevent time: %w", err)
+		}
+		event.Time = &t
+	}
+	if value := field(BinaryFieldPrefix + "schemaversion"); value != "" {
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, true, fmt.Errorf("decode cloudevent schemaversion: %w", err)
+		}
+		event.SchemaVersion = version
+	}
+	return &event, true, event.Validate()
+}
@@ -0,0 +1,124 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent() *Event {
+	occurredAt := time.Date(2024, 6, 10, 6, 13, 20, 0, time.UTC)
+	return &Event{
+		SpecVersion:     SpecVersion,
+		ID:              "event-1",
+		Source:          "/ice-assignment",
+		Type:            "todo.created",
+		Subject:         "todo-1",
+		Time:            &occurredAt,
+		DataContentType: "application/json",
+		DataSchema:      "https://example.com/todo.created.v1.json",
+		TenantID:        "acme",
+		CorrelationID:   "req-123",
+		SchemaVersion:   1,
+		TraceParent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		TraceState:      "vendor=value",
+		Data:            json.RawMessage(`{"id":"todo-1"}`),
+	}
+}
+
+func TestEncodeStructured(t *testing.T) {
+	values, err := EncodeStructured(newTestEvent())
+	require.NoError(t, err)
+	assert.Equal(t, ContentType, values[ContentTypeField])
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(values[DataField].(string)), &doc))
+	assert.Equal(t, "1.0", doc["specversion"])
+	assert.Equal(t, "todo.created", doc["type"])
+	assert.Equal(t, "2024-06-10T06:13:20Z", doc["time"])
+	assert.Equal(t, "acme", doc["tenantid"])
+	assert.Equal(t, "req-123", doc["correlationid"])
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", doc["traceparent"])
+	assert.Equal(t, map[string]interface{}{"id": "todo-1"}, doc["data"])
+
+	decoded, ok, err := Decode(values)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, newTestEvent(), decoded)
+}
+
+func TestEncodeBinary(t *testing.T) {
+	values, err := EncodeBinary(newTestEvent())
+	require.NoError(t, err)
+	assert.Equal(t, "1.0", values["ce_specversion"])
+	assert.Equal(t, "event-1", values["ce_id"])
+	assert.Equal(t, "todo.created", values["ce_type"])
+	assert.Equal(t, "2024-06-10T06:13:20Z", values["ce_time"])
+	assert.Equal(t, "1", values["ce_schemaversion"])
+	assert.Equal(t, "acme", values["ce_tenantid"])
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", values["ce_traceparent"])
+	assert.Equal(t, "vendor=value", values["ce_tracestate"])
+	assert.Equal(t, "application/json", values[ContentTypeField])
+	assert.Equal(t, `{"id":"todo-1"}`, values[DataField])
+
+	decoded, ok, err := Decode(values)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, newTestEvent(), decoded)
+}
+
+func TestEncode_RequiresAttributes(t *testing.T) {
+	event := newTestEvent()
+	event.Source = ""
+	_, err := EncodeStructured(event)
+	assert.Error(t, err)
+	_, err = EncodeBinary(event)
+	assert.Error(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]interface{}
+		expectOK    bool
+		expectError bool
+	}{
+		{
+			name:   "plain data message",
+			values: map[string]interface{}{"data": `{"id":"1"}`},
+		},
+		{
+			name:        "malformed structured event",
+			values:      map[string]interface{}{"content-type": ContentType, "data": "{"},
+			expectOK:    true,
+			expectError: true,
+		},
+		{
+			name:        "unsupported spec version",
+			values:      map[string]interface{}{"ce_specversion": "0.3", "ce_id": "1", "ce_source": "/s", "ce_type": "t"},
+			expectOK:    true,
+			expectError: true,
+		},
+		{
+			name:        "invalid binary time",
+			values:      map[string]interface{}{"ce_specversion": "1.0", "ce_id": "1", "ce_source": "/s", "ce_type": "t", "ce_time": "yesterday"},
+			expectOK:    true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := Decode(tt.values)
+			assert.Equal(t, tt.expectOK, ok)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package correlation
+
+import (
+	"context"
+)
+
+// Header is the HTTP header carrying the correlation ID
+const Header = "X-Correlation-ID"
+
+// contextKey is the context key for the correlation ID
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the correlation ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the correlation ID carried by ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
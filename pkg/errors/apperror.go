@@ -7,10 +7,18 @@ import (
 
 // AppError represents an application error with code, message, and HTTP status
 type AppError struct {
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	HTTPStatus int    `json:"-"`
-	Err        error  `json:"-"`
+	Code       string       `json:"code"`
+	Message    string       `json:"message"`
+	Fields     []FieldError `json:"fields,omitempty"`
+	HTTPStatus int          `json:"-"`
+	Err        error        `json:"-"`
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 // Error implements the error interface
@@ -31,6 +39,17 @@ func NewAppError(code, message string, httpStatus int, err error) *AppError {
 	}
 }
 
+// NewValidationError creates a 400 AppError listing the rejected request fields
+func NewValidationError(message string, fields []FieldError, err error) *AppError {
+	return &AppError{
+		Code:       "INVALID_INPUT",
+		Message:    message,
+		Fields:     fields,
+		HTTPStatus: http.StatusBadRequest,
+		Err:        err,
+	}
+}
+
 // IsAppError checks if an error is an AppError
 func IsAppError(err error) bool {
 	_, ok := err.(*AppError)
@@ -54,11 +73,15 @@ func GetHTTPStatus(err error) int {
 // GetErrorResponse returns a structured error response
 func GetErrorResponse(err error) map[string]interface{} {
 	if appErr, ok := AsAppError(err); ok {
+		body := map[string]interface{}{
+			"code":    appErr.Code,
+			"message": appErr.Message,
+		}
+		if len(appErr.Fields) > 0 {
+			body["fields"] = appErr.Fields
+		}
 		return map[string]interface{}{
-			"error": map[string]interface{}{
-				"code":    appErr.Code,
-				"message": appErr.Message,
-			},
+			"error": body,
 		}
 	}
 	return map[string]interface{}{
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the problem type URI derived from an error code
+const ProblemTypeBase = "urn:ice-assignment:problem:"
+
+// ProblemDetails is an RFC 7807 problem details object. Code and Fields are extension
+// members carrying the AppError code and field errors.
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Fields   []FieldError `json:"fields,omitempty"`
+}
+
+// GetProblemDetails returns the problem details for an error raised while serving instance
+func GetProblemDetails(err error, instance string) *ProblemDetails {
+	appErr, ok := AsAppError(err)
+	if !ok {
+		appErr = NewAppError("INTERNAL_ERROR", "internal server error", http.StatusInternalServerError, err)
+	}
+	return &ProblemDetails{
+		Type:     ProblemType(appErr.Code),
+		Title:    http.StatusText(appErr.HTTPStatus),
+		Status:   appErr.HTTPStatus,
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.Code,
+		Fields:   appErr.Fields,
+	}
+}
+
+// ProblemType returns the problem type URI for an error code, e.g.
+// "urn:ice-assignment:problem:todo-not-found" for TODO_NOT_FOUND
+func ProblemType(code string) string {
+	return ProblemTypeBase + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
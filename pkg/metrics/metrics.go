@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every application metric
+const namespace = "ice"
+
+// streamStatsTimeout bounds the time spent reading stream statistics on a scrape
+const streamStatsTimeout = 2 * time.Second
+
+// registry holds the application metrics next to the Go runtime and process metrics
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by method, route and status.",
+	}, []string{"method", "route", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency, by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	redisXAddDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_xadd_duration_seconds",
+		Help:      "Redis XADD latency, by stream.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"stream"})
+	redisXAddErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_xadd_errors_total",
+		Help:      "Failed Redis XADD calls, by stream.",
+	}, []string{"stream"})
+
+	s3RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "s3_request_duration_seconds",
+		Help:      "S3 request latency, by operation.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"operation"})
+	s3RequestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "s3_request_errors_total",
+		Help:      "S3 requests answered with an error, including 304 and 404 responses, by operation.",
+	}, []string{"operation"})
+	s3BytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "s3_bytes_total",
+		Help:      "Bytes sent to or received from S3, by operation.",
+	}, []string{"operation"})
+
+	todosCreatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "todos_created_total",
+		Help:      "Todo items created.",
+	})
+	uploadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "uploads_total",
+		Help:      "Files uploaded, by detected MIME type.",
+	}, []string{"mime_type"})
+	uploadRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_rejections_total",
+		Help:      "Rejected uploads, by error code.",
+	}, []string{"code"})
+
+	streamLengthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "stream", "length"),
+		"Entries in a stream, by stream.",
+		[]string{"stream"}, nil,
+	)
+	streamGroupLagDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "stream", "group_lag"),
+		"Stream entries not yet delivered to a consumer group, by stream and group.",
+		[]string{"stream", "group"}, nil,
+	)
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		dbQueryDuration,
+		redisXAddDuration,
+		redisXAddErrorsTotal,
+		s3RequestDuration,
+		s3RequestErrorsTotal,
+		s3BytesTotal,
+		todosCreatedTotal,
+		uploadsTotal,
+		uploadRejectionsTotal,
+	)
+}
+
+// Handler returns the HTTP handler exposing all metrics in the Prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats exposes the connection pool statistics of db under the given name.
+// Registering the same name twice is a no-op.
+func RegisterDBStats(db *sql.DB, name string) error {
+	err := registry.Register(collectors.NewDBStatsCollector(db, name))
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
+
+// StreamStats is the length of a stream and the lag of its consumer groups by group
+// name. Groups whose lag is unknown are left out.
+type StreamStats struct {
+	Length   int64
+	GroupLag map[string]int64
+}
+
+// StreamStatsFunc reads the statistics of a stream
+type StreamStatsFunc func(ctx context.Context, stream string) (*StreamStats, error)
+
+// streamStatsCollector reads the statistics of its streams on every scrape
+type streamStatsCollector struct {
+	streams []string
+	stats   StreamStatsFunc
+}
+
+// Describe implements prometheus.Collector
+func (c *streamStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- streamLengthDesc
+	ch <- streamGroupLagDesc
+}
+
+// Collect implements prometheus.Collector. Streams whose statistics cannot be read are
+// left out rather than failing the scrape.
+func (c *streamStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), streamStatsTimeout)
+	defer cancel()
+	for _, stream := range c.streams {
+		stats, err := c.stats(ctx, stream)
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(streamLengthDesc, prometheus.GaugeValue, float64(stats.Length), stream)
+		for group, lag := range stats.GroupLag {
+			ch <- prometheus.MustNewConstMetric(streamGroupLagDesc, prometheus.GaugeValue, float64(lag), stream, group)
+		}
+	}
+}
+
+// RegisterStreamStats exposes the length and consumer group lag of streams, read through
+// stats on every scrape. Registering stream statistics twice is a no-op.
+func RegisterStreamStats(streams []string, stats StreamStatsFunc) error {
+	err := registry.Register(&streamStatsCollector{streams: streams, stats: stats})
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
+
+// ObserveHTTPRequest records a handled HTTP request. route must be the route
+// template, not the request path, to keep the number of series bounded.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	httpRequestsTotal.WithLabelValues(method, route, statusLabel).Inc()
+	httpRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// ObserveDBQuery records the latency of a database query
+func ObserveDBQuery(operation, table string, duration time.Duration) {
+	dbQueryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
+}
+
+// ObserveRedisXAdd records the latency of an XADD call and counts it as failed when
+// err is not nil
+func ObserveRedisXAdd(stream string, duration time.Duration, err error) {
+	redisXAddDuration.WithLabelValues(stream).Observe(duration.Seconds())
+	if err != nil {
+		redisXAddErrorsTotal.WithLabelValues(stream).Inc()
+	}
+}
+
+// ObserveS3Request records the latency of an S3 request and counts it as failed when
+// err is not nil
+func ObserveS3Request(operation string, duration time.Duration, err error) {
+	s3RequestDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		s3RequestErrorsTotal.WithLabelValues(operation).Inc()
+	}
+}
+
+// AddS3Bytes records bytes sent to or received from S3
+func AddS3Bytes(operation string, n int64) {
+	if n > 0 {
+		s3BytesTotal.WithLabelValues(operation).Add(float64(n))
+	}
+}
+
+// IncTodosCreated counts a created todo item
+func IncTodosCreated() {
+	todosCreatedTotal.Inc()
+}
+
+// IncUploads counts a stored upload of the given MIME type
+func IncUploads(mimeType string) {
+	uploadsTotal.WithLabelValues(mimeType).Inc()
+}
+
+// ObserveUploadRejection counts a rejected upload by its AppError code. Errors that
+// are not AppErrors are failures rather than rejections and are not counted.
+func ObserveUploadRejection(err error) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		uploadRejectionsTotal.WithLabelValues(appErr.Code).Inc()
+	}
+}
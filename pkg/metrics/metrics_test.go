@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/ar-agahian/ice-assignment/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveUploadRejection(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expectedCode  string
+		expectedDelta float64
+	}{
+		{
+			name:          "app error",
+			err:           apperrors.NewAppError("INVALID_FILE_TYPE", "file type not allowed", http.StatusBadRequest, nil),
+			expectedCode:  "INVALID_FILE_TYPE",
+			expectedDelta: 1,
+		},
+		{
+			name:         "internal error",
+			err:          errors.New("connection reset"),
+			expectedCode: "INTERNAL_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := testutil.ToFloat64(uploadRejectionsTotal.WithLabelValues(tt.expectedCode))
+			ObserveUploadRejection(tt.err)
+			after := testutil.ToFloat64(uploadRejectionsTotal.WithLabelValues(tt.expectedCode))
+			assert.Equal(t, tt.expectedDelta, after-before)
+		})
+	}
+}
+
+func TestObserveS3Request(t *testing.T) {
+	before := testutil.ToFloat64(s3RequestErrorsTotal.WithLabelValues("GetObject"))
+	ObserveS3Request("GetObject", time.Millisecond, nil)
+	ObserveS3Request("GetObject", time.Millisecond, errors.New("timeout"))
+	AddS3Bytes("GetObject", 0)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(s3RequestErrorsTotal.WithLabelValues("GetObject"))-before)
+}
+
+func TestStreamStatsCollector(t *testing.T) {
+	collector := &streamStatsCollector{
+		streams: []string{"todo-items", "audit"},
+		stats: func(_ context.Context, stream string) (*StreamStats, error) {
+			if stream == "audit" {
+				return nil, errors.New("connection refused")
+			}
+			return &StreamStats{Length: 42, GroupLag: map[string]int64{"todo-workers": 3}}, nil
+		},
+	}
+
+	expected := `
+# HELP ice_stream_group_lag Stream entries not yet delivered to a consumer group, by stream and group.
+# TYPE ice_stream_group_lag gauge
+ice_stream_group_lag{group="todo-workers",stream="todo-items"} 3
+# HELP ice_stream_length Entries in a stream, by stream.
+# TYPE ice_stream_length gauge
+ice_stream_length{stream="todo-items"} 42
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters selectable through Config.Exporter
+const (
+	// ExporterNone disables span export; trace context is still propagated
+	ExporterNone = "none"
+	// ExporterStdout writes spans as JSON to stdout or Config.File, for local use
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends spans to an OTLP/HTTP collector
+	ExporterOTLP = "otlp"
+)
+
+// Config holds the tracing settings. The OTLP exporter also honours the standard
+// OTEL_EXPORTER_OTLP_* environment variables, e.g. for headers.
+type Config struct {
+	Exporter    string `yaml:"exporter" env:"TRACING_EXPORTER" default:"none"`
+	ServiceName string `yaml:"serviceName" env:"TRACING_SERVICE_NAME" default:"ice-assignment"`
+	// File receives the spans of the stdout exporter instead of stdout
+	File string `yaml:"file" env:"TRACING_FILE"`
+	// OTLPEndpoint is the collector's host:port, localhost:4318 unless set
+	OTLPEndpoint string `yaml:"otlpEndpoint" env:"TRACING_OTLP_ENDPOINT"`
+	// OTLPInsecure sends spans over plain HTTP
+	OTLPInsecure bool `yaml:"otlpInsecure" env:"TRACING_OTLP_INSECURE"`
+}
+
+// Setup installs the W3C trace context propagator and a tracer provider exporting
+// through the configured exporter. The returned function flushes pending spans and
+// releases the exporter.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var file io.Closer
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		var w io.Writer = os.Stdout
+		if cfg.File != "" {
+			f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("open trace file: %w", err)
+			}
+			w, file = f, f
+		}
+		stdoutExporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, err
+		}
+		exporter = stdoutExporter
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		otlpExporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		exporter = otlpExporter
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+		return err
+	}, nil
+}
+
+// Inject returns the W3C traceparent and tracestate of the span carried by ctx, or
+// empty strings when there is none
+func Inject(ctx context.Context) (traceParent, traceState string) {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier["traceparent"], carrier["tracestate"]
+}
+
+// Extract returns a copy of ctx carrying the remote span described by traceParent
+// and traceState, so spans started from it continue that trace
+func Extract(ctx context.Context, traceParent, traceState string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{
+		"traceparent": traceParent,
+		"tracestate":  traceState,
+	})
+}
+
+// End records err on span, unless it is nil, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestInjectExtract(t *testing.T) {
+	ctx := Extract(context.Background(), testTraceParent, "vendor=value")
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	assert.True(t, spanContext.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+
+	traceParent, traceState := Inject(ctx)
+	assert.Equal(t, testTraceParent, traceParent)
+	assert.Equal(t, "vendor=value", traceState)
+
+	// Without a trace context there is nothing to propagate
+	traceParent, traceState = Inject(Extract(context.Background(), "", ""))
+	assert.Empty(t, traceParent)
+	assert.Empty(t, traceState)
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           Config
+		expectedError string
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{Exporter: ExporterNone},
+		},
+		{
+			name: "stdout to file",
+			cfg:  Config{Exporter: ExporterStdout, ServiceName: "test", File: filepath.Join(t.TempDir(), "spans.json")},
+		},
+		{
+			name:          "unknown exporter",
+			cfg:           Config{Exporter: "jaeger"},
+			expectedError: `unknown tracing exporter "jaeger"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+			t.Cleanup(func() {
+				otel.SetTracerProvider(provider)
+				otel.SetTextMapPropagator(propagator)
+			})
+
+			shutdown, err := Setup(context.Background(), tt.cfg)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			_, span := otel.Tracer("test").Start(Extract(context.Background(), testTraceParent, ""), "operation")
+			span.End()
+			require.NoError(t, shutdown(context.Background()))
+
+			if tt.cfg.File != "" {
+				spans, err := os.ReadFile(tt.cfg.File)
+				require.NoError(t, err)
+				assert.Contains(t, string(spans), `"Name":"operation"`)
+				assert.Contains(t, string(spans), "4bf92f3577b34da6a3ce929d0e0e4736")
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package schemas
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+// BaseURL is the base of the $id of every event schema
+const BaseURL = "https://github.com/ar-agahian/ice-assignment/schemas/events/"
+
+//go:embed events/*.json
+var events embed.FS
+
+// Events returns the file system holding the JSON Schemas of all published events
+func Events() fs.FS {
+	sub, _ := fs.Sub(events, "events")
+	return sub
+}
+
+// EventSchema returns the JSON Schema of an event type at the given schema version
+func EventSchema(eventType string, version int) ([]byte, error) {
+	return events.ReadFile(fmt.Sprintf("events/%s.v%d.json", eventType, version))
+}
+
+// EventSchemaURL returns the $id of the JSON Schema of an event type at the given schema version
+func EventSchemaURL(eventType string, version int) string {
+	return fmt.Sprintf("%s%s.v%d.json", BaseURL, eventType, version)
+}
@@ -0,0 +1,116 @@
+package schemas
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/ar-agahian/ice-assignment/internal/domain"
+	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compileEventSchema compiles the schema of an event type with all schemas available for $ref
+func compileEventSchema(t *testing.T, eventType domain.EventType) *jsonschema.Schema {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	files, err := fs.Glob(Events(), "*.json")
+	require.NoError(t, err)
+	for _, name := range files {
+		data, err := fs.ReadFile(Events(), name)
+		require.NoError(t, err)
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, compiler.AddResource(BaseURL+name, doc))
+	}
+	schema, err := compiler.Compile(EventSchemaURL(string(eventType), 1))
+	require.NoError(t, err)
+	return schema
+}
+
+// validate validates a JSON document against a schema
+func validate(t *testing.T, schema *jsonschema.Schema, data []byte) error {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	require.NoError(t, err)
+	return schema.Validate(instance)
+}
+
+func TestEventSchemas(t *testing.T) {
+	now := time.Now()
+	item := domain.NewTodoItem("Test todo", now.Add(24*time.Hour), uuid.New().String())
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	require.NoError(t, item.TransitionTo(domain.TodoStatusDone, now))
+
+	tests := []struct {
+		eventType domain.EventType
+		payload   interface{}
+	}{
+		{domain.EventTodoCreated, domain.NewTodoCreatedPayload(item)},
+		{domain.EventTodoUpdated, domain.NewTodoUpdatedPayload(item)},
+		{domain.EventTodoDeleted, domain.TodoDeletedPayload{ID: item.ID.String()}},
+		{domain.EventTodoStatusChanged, domain.TodoStatusChangedPayload{
+			ID:             item.ID.String(),
+			PreviousStatus: domain.TodoStatusOpen,
+			Status:         domain.TodoStatusDone,
+			CompletedAt:    item.CompletedAt,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.eventType), func(t *testing.T) {
+			schema := compileEventSchema(t, tt.eventType)
+
+			event, err := domain.NewEvent(tt.eventType, tt.payload, "correlation-123", now)
+			require.NoError(t, err)
+			data, err := json.Marshal(event)
+			require.NoError(t, err)
+			assert.NoError(t, validate(t, schema, data))
+
+			raw, err := EventSchema(string(tt.eventType), domain.EventSchemaVersion)
+			require.NoError(t, err)
+			assert.NotEmpty(t, raw)
+		})
+	}
+}
+
+func TestEventSchemas_RejectInvalidEvents(t *testing.T) {
+	schema := compileEventSchema(t, domain.EventTodoDeleted)
+	id := uuid.New().String()
+
+	tests := []struct {
+		name  string
+		event string
+	}{
+		{
+			name:  "missing payload",
+			event: `{"id":"` + id + `","type":"todo.deleted","schemaVersion":1,"occurredAt":"2024-01-01T00:00:00Z"}`,
+		},
+		{
+			name:  "wrong type",
+			event: `{"id":"` + id + `","type":"todo.created","schemaVersion":1,"occurredAt":"2024-01-01T00:00:00Z","payload":{"id":"` + id + `"}}`,
+		},
+		{
+			name:  "unsupported version",
+			event: `{"id":"` + id + `","type":"todo.deleted","schemaVersion":2,"occurredAt":"2024-01-01T00:00:00Z","payload":{"id":"` + id + `"}}`,
+		},
+		{
+			name:  "invalid payload id",
+			event: `{"id":"` + id + `","type":"todo.deleted","schemaVersion":1,"occurredAt":"2024-01-01T00:00:00Z","payload":{"id":"not-a-uuid"}}`,
+		},
+		{
+			name:  "invalid timestamp",
+			event: `{"id":"` + id + `","type":"todo.deleted","schemaVersion":1,"occurredAt":"yesterday","payload":{"id":"` + id + `"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, validate(t, schema, []byte(tt.event)))
+		})
+	}
+}